@@ -443,7 +443,7 @@ const docTemplate = `{
                         "Bearer": []
                     }
                 ],
-                "description": "Update a photo by id with authentication user",
+                "description": "Update a photo by id with authentication user, the photo url of an uploaded photo can't be changed",
                 "consumes": [
                     "application/json"
                 ],
@@ -2,18 +2,25 @@ package config
 
 import (
 	"os"
+	"strings"
 )
 
 type AppConfig struct {
-	Name    string
-	Version string
-	Port    string
+	Name                 string
+	Version              string
+	Port                 string
+	PasswordResetURL     string
+	EmailVerificationURL string
+	AdminUserIDs         []string
 }
 
 func LoadAppConfig() AppConfig {
 	return AppConfig{
-		Name:    os.Getenv("APP_NAME"),
-		Version: os.Getenv("APP_VERSION"),
-		Port:    os.Getenv("APP_PORT"),
+		Name:                 os.Getenv("APP_NAME"),
+		Version:              os.Getenv("APP_VERSION"),
+		Port:                 os.Getenv("APP_PORT"),
+		PasswordResetURL:     os.Getenv("PASSWORD_RESET_URL"),
+		EmailVerificationURL: os.Getenv("EMAIL_VERIFICATION_URL"),
+		AdminUserIDs:         strings.FieldsFunc(os.Getenv("ADMIN_USER_IDS"), func(r rune) bool { return r == ',' || r == ' ' }),
 	}
 }
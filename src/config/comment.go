@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+)
+
+type CommentConfig struct {
+	MaxDepth      int
+	InlineReplies int
+}
+
+// LoadCommentConfig reads how deep the replies to comments can be nested, 1
+// for replies to top-level comments only, and how many replies are inlined
+// in the comments of a photo.
+func LoadCommentConfig() CommentConfig {
+	return CommentConfig{
+		MaxDepth:      int(parseUint(os.Getenv("COMMENT_MAX_DEPTH"), 1, 8)),
+		InlineReplies: int(parseUint(os.Getenv("COMMENT_INLINE_REPLIES"), 3, 8)),
+	}
+}
@@ -34,9 +34,19 @@ func ConnectDB() *gorm.DB {
 		log.Fatal("Error connecting to database: ", err)
 	}
 
-	if err = db.AutoMigrate(&domain.User{}, &domain.Photo{}, &domain.Comment{}, &domain.SocialMedia{}); err != nil {
+	// the accounts from before email verification are taken as verified
+	// once, when the column is added, they couldn't post anything otherwise
+	backfillVerified := db.Migrator().HasTable(&domain.User{}) && !db.Migrator().HasColumn(&domain.User{}, "EmailVerifiedAt")
+
+	if err = db.AutoMigrate(&domain.User{}, &domain.Hashtag{}, &domain.Photo{}, &domain.PhotoVariant{}, &domain.Comment{}, &domain.SocialMedia{}, &domain.RefreshToken{}, &domain.RevokedToken{}, &domain.UserTokenVersion{}, &domain.PasswordReset{}, &domain.UserMFA{}, &domain.MFARecoveryCode{}, &domain.LoginThrottle{}, &domain.AuditLog{}, &domain.APIKey{}, &domain.Session{}, &domain.Follow{}, &domain.FeedItem{}, &domain.Like{}, &domain.Mention{}, &domain.Block{}, &domain.Notification{}); err != nil {
 		log.Fatal("Error migrating database: ", err.Error())
 	}
 
+	if backfillVerified {
+		if err = db.Model(&domain.User{}).Where("email_verified_at IS NULL").UpdateColumn("email_verified_at", gorm.Expr("created_at")).Error; err != nil {
+			log.Fatal("Error marking the existing users as verified: ", err.Error())
+		}
+	}
+
 	return db
 }
@@ -0,0 +1,20 @@
+package config
+
+import (
+	"os"
+)
+
+type FeedConfig struct {
+	Strategy      string
+	BackfillLimit int
+}
+
+// LoadFeedConfig reads how home feeds are built, "read" queries the photos of
+// the followed users on every read and "write" copies each photo into the
+// feeds of the followers when it is published.
+func LoadFeedConfig() FeedConfig {
+	return FeedConfig{
+		Strategy:      parseString(os.Getenv("FEED_STRATEGY"), "read"),
+		BackfillLimit: int(parseUint(os.Getenv("FEED_BACKFILL_LIMIT"), 100, 32)),
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+type LoginConfig struct {
+	Account domain.LoginThrottlePolicy
+	IP      domain.LoginThrottlePolicy
+}
+
+// LoadLoginConfig reads how failed logins are throttled. Accounts back off and
+// then get locked, client IPs share many accounts behind a NAT so they only
+// back off, from a higher number of failures.
+func LoadLoginConfig() LoginConfig {
+	backoffBase := parseDuration(os.Getenv("LOGIN_BACKOFF_BASE"), time.Second)
+	backoffMax := parseDuration(os.Getenv("LOGIN_BACKOFF_MAX"), 15*time.Minute)
+	window := parseDuration(os.Getenv("LOGIN_FAILURE_WINDOW"), time.Hour)
+
+	return LoginConfig{
+		Account: domain.LoginThrottlePolicy{
+			BackoffAfter:  uint(parseUint(os.Getenv("LOGIN_BACKOFF_AFTER"), 3, 32)),
+			BackoffBase:   backoffBase,
+			BackoffMax:    backoffMax,
+			LockThreshold: uint(parseUint(os.Getenv("LOGIN_LOCK_THRESHOLD"), 10, 32)),
+			LockDuration:  parseDuration(os.Getenv("LOGIN_LOCK_DURATION"), 30*time.Minute),
+			Window:        window,
+		},
+		IP: domain.LoginThrottlePolicy{
+			BackoffAfter: uint(parseUint(os.Getenv("LOGIN_IP_BACKOFF_AFTER"), 20, 32)),
+			BackoffBase:  backoffBase,
+			BackoffMax:   backoffMax,
+			Window:       window,
+		},
+	}
+}
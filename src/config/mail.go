@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+)
+
+type MailConfig struct {
+	Driver   string
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+	Dir      string
+}
+
+func LoadMailConfig() MailConfig {
+	return MailConfig{
+		Driver:   os.Getenv("MAIL_DRIVER"),
+		Host:     os.Getenv("MAIL_SMTP_HOST"),
+		Port:     os.Getenv("MAIL_SMTP_PORT"),
+		Username: os.Getenv("MAIL_SMTP_USER"),
+		Password: os.Getenv("MAIL_SMTP_PASSWD"),
+		From:     os.Getenv("MAIL_FROM"),
+		Dir:      os.Getenv("MAIL_DIR"),
+	}
+}
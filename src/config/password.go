@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+)
+
+type PasswordConfig struct {
+	Argon2id helpers.Argon2idParams
+}
+
+// LoadPasswordConfig reads the argon2id cost, every parameter left unset
+// keeps its default. Raising them rehashes passwords on the next login.
+func LoadPasswordConfig() PasswordConfig {
+	params := helpers.DefaultArgon2idParams
+
+	params.Memory = uint32(parseUint(os.Getenv("PASSWORD_ARGON2_MEMORY"), uint64(params.Memory), 32))
+	params.Iterations = uint32(parseUint(os.Getenv("PASSWORD_ARGON2_ITERATIONS"), uint64(params.Iterations), 32))
+	params.Parallelism = uint8(parseUint(os.Getenv("PASSWORD_ARGON2_PARALLELISM"), uint64(params.Parallelism), 8))
+
+	return PasswordConfig{Argon2id: params}
+}
+
+func parseUint(value string, fallback uint64, bitSize int) uint64 {
+	number, err := strconv.ParseUint(value, 10, bitSize)
+
+	if err != nil || number == 0 {
+		return fallback
+	}
+
+	return number
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+type PhotoConfig struct {
+	VariantWidths []int
+	Workers       int
+	QueueSize     int
+}
+
+// LoadPhotoConfig reads the widths of the variants generated for uploaded
+// photos and how many workers generate them.
+func LoadPhotoConfig() PhotoConfig {
+	return PhotoConfig{
+		VariantWidths: parseWidths(os.Getenv("PHOTO_VARIANT_WIDTHS"), []int{150, 640, 1080}),
+		Workers:       int(parseUint(os.Getenv("PHOTO_WORKERS"), 2, 16)),
+		QueueSize:     int(parseUint(os.Getenv("PHOTO_QUEUE_SIZE"), 100, 32)),
+	}
+}
+
+func parseWidths(value string, fallback []int) []int {
+	var widths []int
+
+	for _, field := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' }) {
+		width, err := strconv.Atoi(field)
+		if err != nil || width <= 0 {
+			return fallback
+		}
+
+		widths = append(widths, width)
+	}
+
+	if len(widths) == 0 {
+		return fallback
+	}
+
+	return widths
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"os"
+)
+
+type StorageConfig struct {
+	Driver         string
+	Dir            string
+	PublicURL      string
+	S3Endpoint     string
+	S3Region       string
+	S3Bucket       string
+	S3AccessKey    string
+	S3SecretKey    string
+	S3PublicURL    string
+	MaxUploadBytes int64
+}
+
+// LoadStorageConfig reads where uploaded photos are kept, "local" writes them
+// under STORAGE_DIR, "s3" puts them in a bucket of any S3 compatible service
+// and "memory" keeps them until the app stops.
+func LoadStorageConfig() StorageConfig {
+	return StorageConfig{
+		Driver:         parseString(os.Getenv("STORAGE_DRIVER"), "local"),
+		Dir:            parseString(os.Getenv("STORAGE_DIR"), "./public/uploads"),
+		PublicURL:      parseString(os.Getenv("STORAGE_PUBLIC_URL"), "http://localhost:8080/public/uploads"),
+		S3Endpoint:     os.Getenv("S3_ENDPOINT"),
+		S3Region:       parseString(os.Getenv("S3_REGION"), "us-east-1"),
+		S3Bucket:       os.Getenv("S3_BUCKET"),
+		S3AccessKey:    os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:    os.Getenv("S3_SECRET_KEY"),
+		S3PublicURL:    os.Getenv("S3_PUBLIC_URL"),
+		MaxUploadBytes: int64(parseUint(os.Getenv("UPLOAD_MAX_BYTES"), 10<<20, 63)),
+	}
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+type TokenConfig struct {
+	AccessTokenTTL       time.Duration
+	RefreshTokenTTL      time.Duration
+	RevocationStore      string
+	KeysDir              string
+	SigningKeyID         string
+	PasswordResetTTL     time.Duration
+	EmailVerificationTTL time.Duration
+	MFAChallengeTTL      time.Duration
+	MFAMaxAttempts       uint
+	MFALockDuration      time.Duration
+	Issuer               string
+	Audience             string
+}
+
+func LoadTokenConfig() TokenConfig {
+	return TokenConfig{
+		AccessTokenTTL:       parseDuration(os.Getenv("ACCESS_TOKEN_TTL"), 15*time.Minute),
+		RefreshTokenTTL:      parseDuration(os.Getenv("REFRESH_TOKEN_TTL"), 30*24*time.Hour),
+		RevocationStore:      os.Getenv("REVOCATION_STORE"),
+		KeysDir:              os.Getenv("JWT_KEYS_DIR"),
+		SigningKeyID:         os.Getenv("JWT_SIGNING_KEY_ID"),
+		PasswordResetTTL:     parseDuration(os.Getenv("PASSWORD_RESET_TTL"), time.Hour),
+		EmailVerificationTTL: parseDuration(os.Getenv("EMAIL_VERIFICATION_TTL"), 48*time.Hour),
+		MFAChallengeTTL:      parseDuration(os.Getenv("MFA_CHALLENGE_TTL"), 5*time.Minute),
+		MFAMaxAttempts:       uint(parseUint(os.Getenv("MFA_MAX_ATTEMPTS"), 5, 32)),
+		MFALockDuration:      parseDuration(os.Getenv("MFA_LOCK_DURATION"), 15*time.Minute),
+		Issuer:               parseString(os.Getenv("JWT_ISSUER"), "mygram"),
+		Audience:             parseString(os.Getenv("JWT_AUDIENCE"), "mygram-api"),
+	}
+}
+
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	duration, err := time.ParseDuration(value)
+
+	if err != nil || duration <= 0 {
+		return fallback
+	}
+
+	return duration
+}
+
+func parseString(value string, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
@@ -0,0 +1,118 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Scopes an API key can be granted, an API key can only use the endpoints of
+// its scopes while an access token can use every endpoint.
+const (
+	ScopePhotosRead        = "photos:read"
+	ScopePhotosWrite       = "photos:write"
+	ScopeCommentsRead      = "comments:read"
+	ScopeCommentsWrite     = "comments:write"
+	ScopeSocialMediasRead  = "socialmedias:read"
+	ScopeSocialMediasWrite = "socialmedias:write"
+)
+
+var (
+	ErrInvalidAPIKey      = errors.New("the api key is invalid, expired or revoked")
+	ErrInvalidAPIKeyScope = errors.New("the scopes must be some of photos:read, photos:write, comments:read, comments:write, socialmedias:read or socialmedias:write")
+)
+
+// IsValidScope reports whether scope can be granted to an API key.
+func IsValidScope(scope string) bool {
+	switch scope {
+	case ScopePhotosRead, ScopePhotosWrite, ScopeCommentsRead, ScopeCommentsWrite, ScopeSocialMediasRead, ScopeSocialMediasWrite:
+		return true
+	}
+
+	return false
+}
+
+// APIKey represents a personal API key of a user, only the hash of the key is
+// stored. The prefix is the start of the key, kept so users can tell their
+// keys apart.
+type APIKey struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);index;not null" json:"user_id"`
+	Name       string     `gorm:"type:VARCHAR(50);not null" json:"name"`
+	Prefix     string     `gorm:"type:VARCHAR(12);not null" json:"prefix"`
+	KeyHash    string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	Scopes     string     `gorm:"not null" json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// ScopeList returns the scopes of the key, which are stored space separated.
+func (apiKey APIKey) ScopeList() []string {
+	return strings.Fields(apiKey.Scopes)
+}
+
+type APIKeyRepository interface {
+	Save(context.Context, *APIKey) error
+	FindAllByUser(context.Context, *[]APIKey, string) error
+	FindByHash(context.Context, *APIKey, string) error
+	Touch(context.Context, string, time.Time) error
+	Revoke(context.Context, string, string) error
+	RevokeAllByUser(context.Context, string) error
+}
+
+type APIKeyUseCase interface {
+	Create(context.Context, string, CreateAPIKey) (string, APIKey, error)
+	FindAllByUser(context.Context, *[]APIKey, string) error
+	Revoke(context.Context, string, string) error
+	RevokeAllByUser(context.Context, string) error
+	Authenticate(context.Context, string) (APIKey, error)
+}
+
+// Represents for request create api key
+type CreateAPIKey struct {
+	Name      string     `json:"name" valid:"required,stringlength(1|50)" example:"deploy script"`
+	Scopes    []string   `json:"scopes" example:"photos:read,comments:write"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2030-01-01T00:00:00Z"`
+}
+
+// Represents for api key
+type GetAPIKey struct {
+	ID         string     `json:"id" example:"here is the generated api key id"`
+	Name       string     `json:"name" example:"deploy script"`
+	Prefix     string     `json:"prefix" example:"mgk_V1StGXR8"`
+	Scopes     []string   `json:"scopes" example:"photos:read,comments:write"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+}
+
+// Represents for created api key, the key is only ever shown here
+type CreatedDataAPIKey struct {
+	GetAPIKey
+	Key string `json:"key" example:"the api key generated here"`
+}
+
+// Represents for response created api key
+type CreatedAPIKey struct {
+	Status  string            `json:"status" example:"success"`
+	Message string            `json:"message" example:"message you if the process has been successful"`
+	Data    CreatedDataAPIKey `json:"data"`
+}
+
+// Represents for response fetched api keys
+type FetchedAPIKeys struct {
+	Status  string      `json:"status" example:"success"`
+	Message string      `json:"message" example:"message you if the process has been successful"`
+	Data    []GetAPIKey `json:"data"`
+}
+
+// Represents for response revoked api key
+type RevokedAPIKey struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
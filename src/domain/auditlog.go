@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// AuditLog records a user acting on a resource of another user, like a
+// moderator deleting a photo.
+type AuditLog struct {
+	ID           string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	ActorID      string     `gorm:"type:VARCHAR(50);index;not null" json:"actor_id"`
+	ActorRole    string     `gorm:"type:VARCHAR(20);not null" json:"actor_role"`
+	Action       string     `gorm:"type:VARCHAR(20);not null" json:"action"`
+	ResourceType string     `gorm:"type:VARCHAR(20);not null" json:"resource_type"`
+	ResourceID   string     `gorm:"type:VARCHAR(50);index;not null" json:"resource_id"`
+	OwnerID      string     `gorm:"type:VARCHAR(50);not null" json:"owner_id"`
+	CreatedAt    *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+}
+
+type AuditLogRepository interface {
+	Save(context.Context, *AuditLog) error
+}
+
+type AuditLogUseCase interface {
+	Record(context.Context, *AuditLog) error
+}
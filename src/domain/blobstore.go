@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrBlobNotFound = errors.New("the blob doesn't exist")
+
+// BlobStore stores the uploaded files under keys made of slash separated
+// segments, like photos/<user id>/<name>.jpg.
+type BlobStore interface {
+	Put(context.Context, string, io.Reader, int64, string) error
+	Get(context.Context, string) (io.ReadCloser, error)
+	Delete(context.Context, string) error
+	// URL is where clients download the blob of the key from
+	URL(string) string
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrSelfBlock      = errors.New("you can't block yourself")
+	ErrAlreadyBlocked = errors.New("you already block this user")
+	ErrNotBlocked     = errors.New("you don't block this user")
+)
+
+// Block represents a user blocking another one, neither of them can mention
+// the other.
+type Block struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	BlockerID string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_blocks_blocker_blocked" json:"blocker_id"`
+	BlockedID string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_blocks_blocker_blocked;index" json:"blocked_id"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	Blocker   *User      `gorm:"foreignKey:BlockerID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Blocked   *User      `gorm:"foreignKey:BlockedID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type BlockRepository interface {
+	Save(context.Context, *Block) error
+	Delete(context.Context, string, string) error
+	FindBlockedUserIDs(context.Context, string, []string) (map[string]bool, error)
+}
+
+type BlockUseCase interface {
+	Block(context.Context, string, string) (Block, error)
+	Unblock(context.Context, string, string) error
+}
+
+// Represents for response blocked user
+type Blocked struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
+
+// Represents for response unblocked user
+type Unblocked struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
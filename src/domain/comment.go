@@ -2,21 +2,45 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrCommentDeleted     = errors.New("the comment has been deleted")
+	ErrCommentHidden      = errors.New("the comment has been hidden")
+	ErrReplyTooDeep       = errors.New("the replies can't be nested any deeper")
+	ErrReplyPhotoMismatch = errors.New("the reply must be on the photo of the comment it replies to")
+	ErrCommentNotPinnable = errors.New("only a top-level comment that isn't hidden or deleted can be pinned")
+)
+
 type Comment struct {
-	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	UserID    string     `gorm:"type:VARCHAR(50);not null" json:"user_id"`
-	PhotoID   string     `gorm:"type:VARCHAR(50);not null" form:"photo_id" json:"photo_id"`
-	Message   string     `gorm:"not null" valid:"required" form:"message" json:"message" example:"A comment"`
-	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
-	User      *User      `gorm:"foreignKey:UserID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"user,omitempty"`
-	Photo     *Photo     `gorm:"foreignKey:PhotoID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"photo,omitempty"`
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);not null" json:"user_id"`
+	PhotoID    string     `gorm:"type:VARCHAR(50);not null" form:"photo_id" json:"photo_id"`
+	ParentID   *string    `gorm:"type:VARCHAR(50);index" json:"parent_id"`
+	Depth      int        `gorm:"not null;default:0" json:"depth"`
+	Message    string     `gorm:"not null" valid:"required" form:"message" json:"message" example:"A comment"`
+	ReplyCount int        `gorm:"not null;default:0" json:"reply_count"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	HiddenAt   *time.Time `json:"hidden_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"user,omitempty"`
+	Photo      *Photo     `gorm:"foreignKey:PhotoID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"photo,omitempty"`
+	Parent     *Comment   `gorm:"foreignKey:ParentID;constraint:opUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Mentions   []Mention  `gorm:"foreignKey:CommentID" json:"mentions,omitempty"`
+	Replies    []Comment  `gorm:"-" json:"replies,omitempty"`
+}
+
+// Deleted tells whether the comment is a tombstone, a deleted comment kept
+// with a blank message because it still has replies.
+func (c *Comment) Deleted() bool {
+	return c.DeletedAt != nil
 }
 
 func (c *Comment) BeforeCreate(db *gorm.DB) (err error) {
@@ -34,12 +58,30 @@ func (c *Comment) BeforeUpdate(db *gorm.DB) (err error) {
 	return
 }
 
+// Hidden tells whether the owner of the photo hid the comment, it's only
+// listed for them then.
+func (c *Comment) Hidden() bool {
+	return c.HiddenAt != nil
+}
+
+// CommentFilter narrows the lists of comments, the hidden comments are left
+// out unless WithHidden, but those on the photos of PhotoOwnerID, and so is
+// the comment with ExceptID, the pinned one.
+type CommentFilter struct {
+	WithHidden   bool
+	PhotoOwnerID string
+	ExceptID     string
+}
+
 type CommentRepository interface {
 	Save(context.Context, *Comment) error
 	Update(context.Context, Comment, string) (Comment, error)
+	UpdateHidden(context.Context, string, *time.Time) error
 	DeleteById(context.Context, string) error
-	FindAllByUser(context.Context, *[]Comment, string) error
-	FindAllByPhoto(context.Context, *[]Comment, string) error
+	FindAllByUser(context.Context, *[]Comment, string, CommentFilter, pagination.Params) error
+	FindAllByPhoto(context.Context, *[]Comment, string, CommentFilter, pagination.Params) error
+	FindAllByParent(context.Context, *[]Comment, string, CommentFilter, pagination.Params) error
+	FindFirstReplies(context.Context, *[]Comment, []string, CommentFilter, int) error
 	FindById(context.Context, *Comment, string) error
 }
 
@@ -47,22 +89,31 @@ type CommentUseCase interface {
 	Save(context.Context, *Comment) error
 	Update(context.Context, Comment, string) (Comment, error)
 	DeleteById(context.Context, string) error
-	FindAllByUser(context.Context, *[]Comment, string) error
-	FindAllByPhoto(context.Context, *[]Comment, string) error
+	Hide(context.Context, string, bool) (Comment, error)
+	Pin(context.Context, string) (Comment, error)
+	Unpin(context.Context, string) error
+	FindAllByUser(context.Context, *[]Comment, string, string, pagination.Params) error
+	FindAllByPhoto(context.Context, *[]Comment, string, string, pagination.Params) error
+	FindPinned(context.Context, *Comment, string, string) error
+	FindReplies(context.Context, *[]Comment, string, string, pagination.Params) error
 	FindById(context.Context, *Comment, string) error
+	FindVisible(context.Context, *Comment, string, string) error
 }
 
 // Represents for request add comment
 type AddComment struct {
-	Message string `json:"message" form:"message" example:"A comment"`
-	PhotoID string `json:"photo_id"  form:"photoId" example:"photo-123"`
-	UserID  string
+	Message  string `json:"message" form:"message" example:"A comment"`
+	PhotoID  string `json:"photo_id"  form:"photoId" example:"photo-123"`
+	ParentID string `json:"parent_id" form:"parentId" example:"comment-123"`
+	UserID   string
 }
 
 // Represents for added comment
 type AddedDataComment struct {
 	ID        string     `json:"id" example:"here is the generated comment id"`
+	ParentID  *string    `json:"parent_id" example:"comment-123"`
 	Message   string     `json:"message" form:"message" example:"A comment"`
+	Mentions  []Mention  `json:"mentions"`
 	User      *GetUser   `json:"user"`
 	Photo     *GetPhoto  `json:"photo"`
 	CreatedAt *time.Time `json:"created_at" example:"the created at generated here"`
@@ -85,6 +136,7 @@ type UpdateComment struct {
 type UpdatedDataComment struct {
 	ID        string     `json:"id"`
 	Message   string     `json:"message" form:"message" example:"A comment"`
+	Mentions  []Mention  `json:"mentions"`
 	User      *GetUser   `json:"user"`
 	Photo     *GetPhoto  `json:"photo"`
 	UpdatedAt *time.Time `json:"updated_at"`
@@ -117,6 +169,24 @@ type GetAllComments struct {
 	Status  string    `json:"status" example:"success"`
 	Message string    `json:"message" example:"message you if the process has been successful"`
 	Data    []Comment `json:"data"`
+	pagination.Meta
+}
+
+// Represents for response get comments by photo, the pinned comment leads the
+// first page
+type GetPhotoComments struct {
+	Status  string    `json:"status" example:"success"`
+	Message string    `json:"message" example:"message you if the process has been successful"`
+	Pinned  *Comment  `json:"pinned,omitempty"`
+	Data    []Comment `json:"data"`
+	pagination.Meta
+}
+
+// Represents for response hidden, shown or pinned comment
+type ModeratedComment struct {
+	Status  string  `json:"status" example:"success"`
+	Message string  `json:"message" example:"the comment has been hidden"`
+	Data    Comment `json:"data"`
 }
 
 // Represents for response get comment
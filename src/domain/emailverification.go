@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidEmailVerificationToken = errors.New("the email verification link is invalid or has expired")
+	ErrEmailNotVerified              = errors.New("verify your email address to proceed")
+)
+
+type EmailVerificationUseCase interface {
+	Send(context.Context, User) error
+	Resend(context.Context, string) error
+	Verify(context.Context, string) (User, error)
+}
+
+// Represents for request verify email
+type VerifyEmail struct {
+	Token string `json:"token" valid:"required" example:"the token from the verification mail"`
+}
+
+// Represents for response verify email
+type VerifiedEmail struct {
+	Status  string  `json:"status" example:"success"`
+	Message string  `json:"message" example:"message you if the process has been successful"`
+	Data    GetUser `json:"data"`
+}
+
+// Represents for response resend email verification
+type EmailVerificationResponse struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// FeedStrategy builds the home feed of a user: the photos of the users they
+// follow and their own, the most recent first. A fan-out-on-read strategy
+// queries them when the feed is read and ignores the other calls, a
+// fan-out-on-write one copies every photo into the feed of each follower
+// when it is published.
+type FeedStrategy interface {
+	AddPhoto(context.Context, Photo) error
+	AddFollow(context.Context, string, string) error
+	RemoveFollow(context.Context, string, string) error
+	FindByUser(context.Context, *[]Photo, string, pagination.Params) error
+}
+
+type FeedUseCase interface {
+	FindByUser(context.Context, *[]Photo, string, pagination.Params) error
+}
+
+// FeedItem represents a photo copied into the feed of a user by the
+// fan-out-on-write strategy.
+type FeedItem struct {
+	UserID         string     `gorm:"primaryKey;type:VARCHAR(50)"`
+	PhotoID        string     `gorm:"primaryKey;type:VARCHAR(50)"`
+	AuthorID       string     `gorm:"type:VARCHAR(50);not null;index"`
+	PhotoCreatedAt *time.Time `gorm:"not null;index"`
+	User           *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE"`
+	Photo          *Photo     `gorm:"foreignKey:PhotoID;constraint:onUpdate:CASCADE,onDelete:CASCADE"`
+}
+
+// Represents for response fetched feed
+type FetchedFeed struct {
+	Status  string            `json:"status" example:"success"`
+	Message string            `json:"message" example:"message you if the process has been successful"`
+	Data    []*GetDetailPhoto `json:"data"`
+	pagination.Meta
+}
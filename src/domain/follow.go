@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+var (
+	ErrSelfFollow       = errors.New("you can't follow yourself")
+	ErrAlreadyFollowing = errors.New("you already follow this user")
+	ErrNotFollowing     = errors.New("you don't follow this user")
+)
+
+// Follow represents a user following another one. The follower_count and
+// following_count of both users are changed in the same transaction as the
+// follow so that profiles are read without counting.
+type Follow struct {
+	ID          string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	FollowerID  string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_follows_follower_following" json:"follower_id"`
+	FollowingID string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_follows_follower_following;index" json:"following_id"`
+	CreatedAt   *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	Follower    *User      `gorm:"foreignKey:FollowerID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Following   *User      `gorm:"foreignKey:FollowingID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type FollowRepository interface {
+	Save(context.Context, *Follow) error
+	Delete(context.Context, string, string) error
+	IsFollowing(context.Context, string, string) (bool, error)
+	FindFollowers(context.Context, *[]Follow, string, pagination.Params) error
+	FindFollowing(context.Context, *[]Follow, string, pagination.Params) error
+}
+
+type FollowUseCase interface {
+	Follow(context.Context, string, string) (Follow, error)
+	Unfollow(context.Context, string, string) error
+	FindFollowers(context.Context, *[]Follow, string, pagination.Params) error
+	FindFollowing(context.Context, *[]Follow, string, pagination.Params) error
+}
+
+// Represents for user of followers and following
+type GetFollowUser struct {
+	ID          string     `json:"id" example:"here is the generated user id"`
+	Username    string     `json:"username" example:"johndoe"`
+	DisplayName string     `json:"display_name,omitempty" example:"John Doe"`
+	AvatarURL   string     `json:"avatar_url,omitempty" example:"https://www.example.com/avatar.jpg"`
+	FollowedAt  *time.Time `json:"followed_at"`
+}
+
+// Represents for response followed user
+type Followed struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
+
+// Represents for response unfollowed user
+type Unfollowed struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
+
+// Represents for response fetched followers or following
+type FetchedFollows struct {
+	Status  string          `json:"status" example:"success"`
+	Message string          `json:"message" example:"message you if the process has been successful"`
+	Data    []GetFollowUser `json:"data"`
+	pagination.Meta
+}
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+var ErrInvalidHashtag = errors.New("the hashtag must be letters, digits and underscores with at least one letter")
+
+// Hashtag represents a hashtag written in the caption of a photo, the name is
+// lowercased. The photos are joined through photo_hashtags, which is kept in
+// sync with the caption whenever it's saved.
+type Hashtag struct {
+	Name      string     `gorm:"primaryKey;type:VARCHAR(100)" json:"name"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+}
+
+// HashtagUsage represents a hashtag with how many photos have it.
+type HashtagUsage struct {
+	Name       string `json:"name" example:"sunset"`
+	PhotoCount int64  `json:"photo_count" example:"42"`
+}
+
+type HashtagRepository interface {
+	FindPhotos(context.Context, *[]Photo, string, pagination.Params) error
+	Search(context.Context, *[]HashtagUsage, string, int) error
+}
+
+type HashtagUseCase interface {
+	FindPhotos(context.Context, *[]Photo, string, pagination.Params) error
+	Search(context.Context, *[]HashtagUsage, string, int) error
+}
+
+// Represents for response searched hashtags
+type SearchedHashtags struct {
+	Status  string         `json:"status" example:"success"`
+	Message string         `json:"message" example:"message you if the process has been successful"`
+	Data    []HashtagUsage `json:"data"`
+}
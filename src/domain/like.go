@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// Like represents a user liking a photo. The like_count of the photo is
+// changed in the same transaction as the like so that photos are listed
+// without counting.
+type Like struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_likes_user_photo" json:"user_id"`
+	PhotoID   string     `gorm:"type:VARCHAR(50);not null;uniqueIndex:idx_likes_user_photo;index" json:"photo_id"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Photo     *Photo     `gorm:"foreignKey:PhotoID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type LikeRepository interface {
+	Save(context.Context, *Like) error
+	Delete(context.Context, string, string) error
+	FindByPhoto(context.Context, *[]Like, string, pagination.Params) error
+	FindLikedPhotoIDs(context.Context, string, []string) (map[string]bool, error)
+}
+
+type LikeUseCase interface {
+	Like(context.Context, string, string) error
+	Unlike(context.Context, string, string) error
+	FindByPhoto(context.Context, *[]Like, string, pagination.Params) error
+	FindLikedPhotoIDs(context.Context, string, []string) (map[string]bool, error)
+}
+
+// Represents for user of likes
+type GetLikeUser struct {
+	ID          string     `json:"id" example:"here is the generated user id"`
+	Username    string     `json:"username" example:"johndoe"`
+	DisplayName string     `json:"display_name,omitempty" example:"John Doe"`
+	AvatarURL   string     `json:"avatar_url,omitempty" example:"https://www.example.com/avatar.jpg"`
+	LikedAt     *time.Time `json:"liked_at"`
+}
+
+// Represents for response liked photo
+type Liked struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
+
+// Represents for response unliked photo
+type Unliked struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
+
+// Represents for response fetched likes
+type FetchedLikes struct {
+	Status  string        `json:"status" example:"success"`
+	Message string        `json:"message" example:"message you if the process has been successful"`
+	Data    []GetLikeUser `json:"data"`
+	pagination.Meta
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrTooManyLoginAttempts = errors.New("too many failed login attempts, please try again later")
+
+// LoginThrottle represents the recent failed logins of a subject, which is
+// either an account as "account:<email>", a client as "ip:<address>" or the
+// two-factor codes of a user as "mfa:<user id>".
+type LoginThrottle struct {
+	Subject      string     `gorm:"primaryKey;type:VARCHAR(330)" json:"subject"`
+	Failures     uint       `gorm:"not null;default:0" json:"failures"`
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty"`
+	UpdatedAt    *time.Time `gorm:"not null;autoUpdateTime" json:"updated_at,omitempty"`
+}
+
+// LoginThrottlePolicy tells how long a subject has to wait after failed
+// logins. From BackoffAfter failures on the wait doubles with every failure,
+// from LockThreshold failures on the subject is locked for LockDuration, a
+// LockThreshold of 0 never locks. Failures older than Window are forgotten.
+type LoginThrottlePolicy struct {
+	BackoffAfter  uint
+	BackoffBase   time.Duration
+	BackoffMax    time.Duration
+	LockThreshold uint
+	LockDuration  time.Duration
+	Window        time.Duration
+}
+
+type LoginThrottleRepository interface {
+	Find(context.Context, *LoginThrottle, string) error
+	RecordFailure(context.Context, string, time.Duration) (LoginThrottle, error)
+	LockUntil(context.Context, string, time.Time) error
+	Delete(context.Context, string) error
+}
+
+type LoginAttemptUseCase interface {
+	Check(context.Context, string, string) (time.Duration, error)
+	Fail(context.Context, string, string) error
+	Succeed(context.Context, string) error
+	Status(context.Context, string) (LoginLockStatus, error)
+	Unlock(context.Context, string) error
+}
+
+// Represents for lock status of an account
+type LoginLockStatus struct {
+	Locked       bool       `json:"locked" example:"true"`
+	Failures     uint       `json:"failures" example:"10"`
+	LastFailedAt *time.Time `json:"last_failed_at,omitempty" example:"last failed login time should be here"`
+	LockedUntil  *time.Time `json:"locked_until,omitempty" example:"end of the lock should be here"`
+}
+
+// Represents for response lock status of an account
+type LoginLockStatusResponse struct {
+	Status  string          `json:"status" example:"success"`
+	Message string          `json:"message" example:"message you if the process has been successful"`
+	Data    LoginLockStatus `json:"data"`
+}
+
+// Represents for response unlock an account
+type UnlockedUser struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
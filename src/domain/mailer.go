@@ -0,0 +1,14 @@
+package domain
+
+import "context"
+
+// Mail represents a plain text email message
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(context.Context, Mail) error
+}
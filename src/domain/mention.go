@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Mention is a user mentioned with @username in the caption of a photo, or in
+// one of its comments when CommentID is set. Offset and Length are counted in
+// runes from the start of the text and cover the @, so that clients can link
+// the mention where it's written.
+type Mention struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"-"`
+	PhotoID   string     `gorm:"type:VARCHAR(50);not null;index" json:"-"`
+	CommentID *string    `gorm:"type:VARCHAR(50);index" json:"-"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	Username  string     `gorm:"type:VARCHAR(50);not null" json:"username" example:"johndoe"`
+	Offset    int        `gorm:"not null" json:"offset"`
+	Length    int        `gorm:"not null" json:"length"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"-"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Photo     *Photo     `gorm:"foreignKey:PhotoID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Comment   *Comment   `gorm:"foreignKey:CommentID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type MentionUseCase interface {
+	Resolve(context.Context, string, string) []Mention
+	Notify(context.Context, string, []Mention, []Mention) error
+}
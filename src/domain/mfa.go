@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidMFACode      = errors.New("the code you entered is invalid")
+	ErrInvalidMFAChallenge = errors.New("the two-factor challenge is invalid or has expired, please sign in again")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled       = errors.New("two-factor authentication is not enabled")
+	ErrMFANotEnrolled      = errors.New("start the two-factor enrollment first")
+	ErrTooManyMFAAttempts  = errors.New("too many wrong two-factor codes, please try again later")
+)
+
+// UserMFA represents the TOTP secret of a user. Two-factor authentication is
+// on once EnabledAt is set, until then the enrollment is pending.
+type UserMFA struct {
+	UserID          string     `gorm:"primaryKey;type:VARCHAR(50)" json:"user_id"`
+	Secret          string     `gorm:"type:VARCHAR(64);not null" json:"-"`
+	EnabledAt       *time.Time `json:"enabled_at,omitempty"`
+	LastUsedCounter int64      `gorm:"not null;default:0" json:"-"`
+	CreatedAt       *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt       *time.Time `gorm:"not null;autoUpdateTime" json:"updated_at,omitempty"`
+	User            *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// MFARecoveryCode represents a single use code which replaces a TOTP code
+// when the authenticator is lost, only the hash of the code is stored.
+type MFARecoveryCode struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);index;not null" json:"user_id"`
+	CodeHash  string     `gorm:"type:VARCHAR(64);not null" json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type MFARepository interface {
+	Save(context.Context, *UserMFA) error
+	FindByUserID(context.Context, *UserMFA, string) error
+	Enable(context.Context, UserMFA, int64, []string) error
+	UseCounter(context.Context, string, int64) error
+	UseRecoveryCode(context.Context, string, string) error
+	Delete(context.Context, string) error
+}
+
+type MFAUseCase interface {
+	Enroll(context.Context, User) (MFAEnrollment, error)
+	Confirm(context.Context, string, string) ([]string, error)
+	Disable(context.Context, string, string) error
+	IsEnabled(context.Context, string) (bool, error)
+	Challenge(context.Context, User) (MFAChallenge, error)
+	Verify(context.Context, string, string) (User, error)
+}
+
+// Represents for enrollment of two-factor authentication
+type MFAEnrollment struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXP"`
+	OTPAuthURI string `json:"otpauth_uri" example:"otpauth://totp/MyGram:johndoe@example.com?secret=JBSWY3DPEHPK3PXP&issuer=MyGram"`
+}
+
+// Represents for response enroll two-factor authentication
+type EnrolledMFA struct {
+	Status  string        `json:"status" example:"success"`
+	Message string        `json:"message" example:"message you if the process has been successful"`
+	Data    MFAEnrollment `json:"data"`
+}
+
+// Represents for request confirm and disable two-factor authentication
+type MFACode struct {
+	Code string `json:"code" valid:"required" example:"123456"`
+}
+
+// Represents for response confirm two-factor authentication
+type ConfirmedMFA struct {
+	Status  string   `json:"status" example:"success"`
+	Message string   `json:"message" example:"message you if the process has been successful"`
+	Data    []string `json:"data" example:"k3j5h-9x2mq"`
+}
+
+// Represents for response disable two-factor authentication
+type DisabledMFA struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
+
+// Represents for two-factor challenge of a login
+type MFAChallenge struct {
+	ChallengeToken string `json:"challenge_token" example:"the challenge token generated here"`
+	ExpiresIn      int64  `json:"expires_in" example:"300"`
+}
+
+// Represents for response login which requires two-factor authentication
+type MFARequired struct {
+	Status  string       `json:"status" example:"mfa_required"`
+	Message string       `json:"message" example:"enter the code of your authenticator app to proceed"`
+	Data    MFAChallenge `json:"data"`
+}
+
+// Represents for request verify two-factor challenge
+type VerifyMFA struct {
+	ChallengeToken string `json:"challenge_token" valid:"required" example:"the challenge token of the login"`
+	Code           string `json:"code" valid:"required" example:"123456"`
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// BlockRepository is an autogenerated mock type for the BlockRepository type
+type BlockRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *BlockRepository) Delete(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindBlockedUserIDs provides a mock function with given fields: _a0, _a1, _a2
+func (_m *BlockRepository) FindBlockedUserIDs(_a0 context.Context, _a1 string, _a2 []string) (map[string]bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 map[string]bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (map[string]bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) map[string]bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: _a0, _a1
+func (_m *BlockRepository) Save(_a0 context.Context, _a1 *domain.Block) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Block) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewBlockRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewBlockRepository creates a new instance of BlockRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewBlockRepository(t mockConstructorTestingTNewBlockRepository) *BlockRepository {
+	mock := &BlockRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,10 @@ import (
 
 	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
+
+	time "time"
 )
 
 // CommentRepository is an autogenerated mock type for the CommentRepository type
@@ -28,13 +32,13 @@ func (_m *CommentRepository) DeleteById(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// FindAllByPhoto provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentRepository) FindAllByPhoto(_a0 context.Context, _a1 *[]domain.Comment, _a2 string) error {
-	ret := _m.Called(_a0, _a1, _a2)
+// FindAllByParent provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *CommentRepository) FindAllByParent(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 domain.CommentFilter, _a4 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string) error); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, domain.CommentFilter, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -42,13 +46,27 @@ func (_m *CommentRepository) FindAllByPhoto(_a0 context.Context, _a1 *[]domain.C
 	return r0
 }
 
-// FindAllByUser provides a mock function with given fields: _a0, _a1, _a2
-func (_m *CommentRepository) FindAllByUser(_a0 context.Context, _a1 *[]domain.Comment, _a2 string) error {
-	ret := _m.Called(_a0, _a1, _a2)
+// FindAllByPhoto provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *CommentRepository) FindAllByPhoto(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 domain.CommentFilter, _a4 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string) error); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, domain.CommentFilter, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAllByUser provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *CommentRepository) FindAllByUser(_a0 context.Context, _a1 *[]domain.Comment, _a2 string, _a3 domain.CommentFilter, _a4 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, string, domain.CommentFilter, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -70,6 +88,20 @@ func (_m *CommentRepository) FindById(_a0 context.Context, _a1 *domain.Comment,
 	return r0
 }
 
+// FindFirstReplies provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *CommentRepository) FindFirstReplies(_a0 context.Context, _a1 *[]domain.Comment, _a2 []string, _a3 domain.CommentFilter, _a4 int) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Comment, []string, domain.CommentFilter, int) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: _a0, _a1
 func (_m *CommentRepository) Save(_a0 context.Context, _a1 *domain.Comment) error {
 	ret := _m.Called(_a0, _a1)
@@ -108,6 +140,20 @@ func (_m *CommentRepository) Update(_a0 context.Context, _a1 domain.Comment, _a2
 	return r0, r1
 }
 
+// UpdateHidden provides a mock function with given fields: _a0, _a1, _a2
+func (_m *CommentRepository) UpdateHidden(_a0 context.Context, _a1 string, _a2 *time.Time) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewCommentRepository interface {
 	mock.TestingT
 	Cleanup(func())
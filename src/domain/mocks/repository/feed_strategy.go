@@ -0,0 +1,88 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// FeedStrategy is an autogenerated mock type for the FeedStrategy type
+type FeedStrategy struct {
+	mock.Mock
+}
+
+// AddFollow provides a mock function with given fields: _a0, _a1, _a2
+func (_m *FeedStrategy) AddFollow(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddPhoto provides a mock function with given fields: _a0, _a1
+func (_m *FeedStrategy) AddPhoto(_a0 context.Context, _a1 domain.Photo) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Photo) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByUser provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *FeedStrategy) FindByUser(_a0 context.Context, _a1 *[]domain.Photo, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveFollow provides a mock function with given fields: _a0, _a1, _a2
+func (_m *FeedStrategy) RemoveFollow(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewFeedStrategy interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewFeedStrategy creates a new instance of FeedStrategy. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewFeedStrategy(t mockConstructorTestingTNewFeedStrategy) *FeedStrategy {
+	mock := &FeedStrategy{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,112 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// FollowRepository is an autogenerated mock type for the FollowRepository type
+type FollowRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *FollowRepository) Delete(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindFollowers provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *FollowRepository) FindFollowers(_a0 context.Context, _a1 *[]domain.Follow, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Follow, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindFollowing provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *FollowRepository) FindFollowing(_a0 context.Context, _a1 *[]domain.Follow, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Follow, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IsFollowing provides a mock function with given fields: _a0, _a1, _a2
+func (_m *FollowRepository) IsFollowing(_a0 context.Context, _a1 string, _a2 string) (bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: _a0, _a1
+func (_m *FollowRepository) Save(_a0 context.Context, _a1 *domain.Follow) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Follow) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewFollowRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewFollowRepository creates a new instance of FollowRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewFollowRepository(t mockConstructorTestingTNewFollowRepository) *FollowRepository {
+	mock := &FollowRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// HashtagRepository is an autogenerated mock type for the HashtagRepository type
+type HashtagRepository struct {
+	mock.Mock
+}
+
+// FindPhotos provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *HashtagRepository) FindPhotos(_a0 context.Context, _a1 *[]domain.Photo, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *HashtagRepository) Search(_a0 context.Context, _a1 *[]domain.HashtagUsage, _a2 string, _a3 int) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.HashtagUsage, string, int) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewHashtagRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewHashtagRepository creates a new instance of HashtagRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewHashtagRepository(t mockConstructorTestingTNewHashtagRepository) *HashtagRepository {
+	mock := &HashtagRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
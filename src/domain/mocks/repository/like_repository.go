@@ -0,0 +1,100 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// LikeRepository is an autogenerated mock type for the LikeRepository type
+type LikeRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LikeRepository) Delete(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindByPhoto provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeRepository) FindByPhoto(_a0 context.Context, _a1 *[]domain.Like, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Like, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindLikedPhotoIDs provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LikeRepository) FindLikedPhotoIDs(_a0 context.Context, _a1 string, _a2 []string) (map[string]bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 map[string]bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (map[string]bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) map[string]bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: _a0, _a1
+func (_m *LikeRepository) Save(_a0 context.Context, _a1 *domain.Like) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Like) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLikeRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLikeRepository creates a new instance of LikeRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLikeRepository(t mockConstructorTestingTNewLikeRepository) *LikeRepository {
+	mock := &LikeRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
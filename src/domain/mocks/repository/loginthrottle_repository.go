@@ -0,0 +1,98 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginThrottleRepository is an autogenerated mock type for the LoginThrottleRepository type
+type LoginThrottleRepository struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: _a0, _a1
+func (_m *LoginThrottleRepository) Delete(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Find provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginThrottleRepository) Find(_a0 context.Context, _a1 *domain.LoginThrottle, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.LoginThrottle, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LockUntil provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginThrottleRepository) LockUntil(_a0 context.Context, _a1 string, _a2 time.Time) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecordFailure provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginThrottleRepository) RecordFailure(_a0 context.Context, _a1 string, _a2 time.Duration) (domain.LoginThrottle, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.LoginThrottle
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (domain.LoginThrottle, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) domain.LoginThrottle); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.LoginThrottle)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewLoginThrottleRepository interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginThrottleRepository creates a new instance of LoginThrottleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginThrottleRepository(t mockConstructorTestingTNewLoginThrottleRepository) *LoginThrottleRepository {
+	mock := &LoginThrottleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
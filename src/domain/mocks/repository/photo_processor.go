@@ -0,0 +1,44 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PhotoProcessor is an autogenerated mock type for the PhotoProcessor type
+type PhotoProcessor struct {
+	mock.Mock
+}
+
+// Enqueue provides a mock function with given fields: _a0, _a1
+func (_m *PhotoProcessor) Enqueue(_a0 context.Context, _a1 domain.Photo) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Photo) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewPhotoProcessor interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewPhotoProcessor creates a new instance of PhotoProcessor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewPhotoProcessor(t mockConstructorTestingTNewPhotoProcessor) *PhotoProcessor {
+	mock := &PhotoProcessor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
 )
 
 // PhotoRepository is an autogenerated mock type for the PhotoRepository type
@@ -28,13 +30,13 @@ func (_m *PhotoRepository) DeleteById(_a0 context.Context, _a1 string) error {
 	return r0
 }
 
-// FindAll provides a mock function with given fields: _a0, _a1
-func (_m *PhotoRepository) FindAll(_a0 context.Context, _a1 *[]domain.Photo) error {
-	ret := _m.Called(_a0, _a1)
+// FindAll provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoRepository) FindAll(_a0 context.Context, _a1 *[]domain.Photo, _a2 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo) error); ok {
-		r0 = rf(_a0, _a1)
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -56,6 +58,20 @@ func (_m *PhotoRepository) FindById(_a0 context.Context, _a1 *domain.Photo, _a2
 	return r0
 }
 
+// FindProcessing provides a mock function with given fields: _a0, _a1
+func (_m *PhotoRepository) FindProcessing(_a0 context.Context, _a1 *[]domain.Photo) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Photo) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: _a0, _a1
 func (_m *PhotoRepository) Save(_a0 context.Context, _a1 *domain.Photo) error {
 	ret := _m.Called(_a0, _a1)
@@ -70,6 +86,20 @@ func (_m *PhotoRepository) Save(_a0 context.Context, _a1 *domain.Photo) error {
 	return r0
 }
 
+// SaveVariants provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *PhotoRepository) SaveVariants(_a0 context.Context, _a1 string, _a2 []domain.PhotoVariant, _a3 string) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []domain.PhotoVariant, string) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: _a0, _a1, _a2
 func (_m *PhotoRepository) Update(_a0 context.Context, _a1 domain.Photo, _a2 string) (domain.Photo, error) {
 	ret := _m.Called(_a0, _a1, _a2)
@@ -94,6 +124,34 @@ func (_m *PhotoRepository) Update(_a0 context.Context, _a1 domain.Photo, _a2 str
 	return r0, r1
 }
 
+// UpdateCommentPolicy provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoRepository) UpdateCommentPolicy(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePinnedComment provides a mock function with given fields: _a0, _a1, _a2
+func (_m *PhotoRepository) UpdatePinnedComment(_a0 context.Context, _a1 string, _a2 *string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewPhotoRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
 )
 
 // SocialMediaRepository is an autogenerated mock type for the SocialMediaRepository type
@@ -28,13 +30,13 @@ func (_m *SocialMediaRepository) DeleteById(_a0 context.Context, _a1 string) err
 	return r0
 }
 
-// FindAllByUser provides a mock function with given fields: _a0, _a1, _a2
-func (_m *SocialMediaRepository) FindAllByUser(_a0 context.Context, _a1 *[]domain.SocialMedia, _a2 string) error {
-	ret := _m.Called(_a0, _a1, _a2)
+// FindAllByUser provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *SocialMediaRepository) FindAllByUser(_a0 context.Context, _a1 *[]domain.SocialMedia, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.SocialMedia, string) error); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.SocialMedia, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -52,6 +52,30 @@ func (_m *UserRepository) FindByEmail(_a0 context.Context, _a1 *domain.User) (do
 	return r0, r1
 }
 
+// FindById provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) FindById(_a0 context.Context, _a1 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindByUsername provides a mock function with given fields: _a0, _a1
 func (_m *UserRepository) FindByUsername(_a0 context.Context, _a1 *domain.User) (domain.User, error) {
 	ret := _m.Called(_a0, _a1)
@@ -76,6 +100,30 @@ func (_m *UserRepository) FindByUsername(_a0 context.Context, _a1 *domain.User)
 	return r0, r1
 }
 
+// FindProfile provides a mock function with given fields: _a0, _a1
+func (_m *UserRepository) FindProfile(_a0 context.Context, _a1 string) (domain.UserProfile, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.UserProfile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.UserProfile, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.UserProfile); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.UserProfile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Login provides a mock function with given fields: _a0, _a1
 func (_m *UserRepository) Login(_a0 context.Context, _a1 *domain.User) error {
 	ret := _m.Called(_a0, _a1)
@@ -128,6 +176,68 @@ func (_m *UserRepository) Update(_a0 context.Context, _a1 domain.User) (domain.U
 	return r0, r1
 }
 
+// UpdatePassword provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *UserRepository) UpdatePassword(_a0 context.Context, _a1 string, _a2 string, _a3 string) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRole provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) UpdateRole(_a0 context.Context, _a1 string, _a2 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.User, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyEmail provides a mock function with given fields: _a0, _a1, _a2
+func (_m *UserRepository) VerifyEmail(_a0 context.Context, _a1 string, _a2 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.User, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewUserRepository interface {
 	mock.TestingT
 	Cleanup(func())
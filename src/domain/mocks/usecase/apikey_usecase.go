@@ -0,0 +1,127 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIKeyUseCase is an autogenerated mock type for the APIKeyUseCase type
+type APIKeyUseCase struct {
+	mock.Mock
+}
+
+// Authenticate provides a mock function with given fields: _a0, _a1
+func (_m *APIKeyUseCase) Authenticate(_a0 context.Context, _a1 string) (domain.APIKey, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.APIKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.APIKey, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.APIKey); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.APIKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: _a0, _a1, _a2
+func (_m *APIKeyUseCase) Create(_a0 context.Context, _a1 string, _a2 domain.CreateAPIKey) (string, domain.APIKey, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 string
+	var r1 domain.APIKey
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.CreateAPIKey) (string, domain.APIKey, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.CreateAPIKey) string); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.CreateAPIKey) domain.APIKey); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Get(1).(domain.APIKey)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, string, domain.CreateAPIKey) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindAllByUser provides a mock function with given fields: _a0, _a1, _a2
+func (_m *APIKeyUseCase) FindAllByUser(_a0 context.Context, _a1 *[]domain.APIKey, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.APIKey, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Revoke provides a mock function with given fields: _a0, _a1, _a2
+func (_m *APIKeyUseCase) Revoke(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAllByUser provides a mock function with given fields: _a0, _a1
+func (_m *APIKeyUseCase) RevokeAllByUser(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewAPIKeyUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewAPIKeyUseCase creates a new instance of APIKeyUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewAPIKeyUseCase(t mockConstructorTestingTNewAPIKeyUseCase) *APIKeyUseCase {
+	mock := &APIKeyUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
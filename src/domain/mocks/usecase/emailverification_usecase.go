@@ -0,0 +1,82 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationUseCase is an autogenerated mock type for the EmailVerificationUseCase type
+type EmailVerificationUseCase struct {
+	mock.Mock
+}
+
+// Resend provides a mock function with given fields: _a0, _a1
+func (_m *EmailVerificationUseCase) Resend(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Send provides a mock function with given fields: _a0, _a1
+func (_m *EmailVerificationUseCase) Send(_a0 context.Context, _a1 domain.User) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Verify provides a mock function with given fields: _a0, _a1
+func (_m *EmailVerificationUseCase) Verify(_a0 context.Context, _a1 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewEmailVerificationUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewEmailVerificationUseCase creates a new instance of EmailVerificationUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewEmailVerificationUseCase(t mockConstructorTestingTNewEmailVerificationUseCase) *EmailVerificationUseCase {
+	mock := &EmailVerificationUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,98 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// FollowUseCase is an autogenerated mock type for the FollowUseCase type
+type FollowUseCase struct {
+	mock.Mock
+}
+
+// FindFollowers provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *FollowUseCase) FindFollowers(_a0 context.Context, _a1 *[]domain.Follow, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Follow, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindFollowing provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *FollowUseCase) FindFollowing(_a0 context.Context, _a1 *[]domain.Follow, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Follow, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Follow provides a mock function with given fields: _a0, _a1, _a2
+func (_m *FollowUseCase) Follow(_a0 context.Context, _a1 string, _a2 string) (domain.Follow, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.Follow
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.Follow, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.Follow); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.Follow)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unfollow provides a mock function with given fields: _a0, _a1, _a2
+func (_m *FollowUseCase) Unfollow(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewFollowUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewFollowUseCase creates a new instance of FollowUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewFollowUseCase(t mockConstructorTestingTNewFollowUseCase) *FollowUseCase {
+	mock := &FollowUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
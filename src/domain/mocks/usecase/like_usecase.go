@@ -0,0 +1,100 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	pagination "github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// LikeUseCase is an autogenerated mock type for the LikeUseCase type
+type LikeUseCase struct {
+	mock.Mock
+}
+
+// FindByPhoto provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *LikeUseCase) FindByPhoto(_a0 context.Context, _a1 *[]domain.Like, _a2 string, _a3 pagination.Params) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Like, string, pagination.Params) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindLikedPhotoIDs provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LikeUseCase) FindLikedPhotoIDs(_a0 context.Context, _a1 string, _a2 []string) (map[string]bool, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 map[string]bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (map[string]bool, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) map[string]bool); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]bool)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Like provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LikeUseCase) Like(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unlike provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LikeUseCase) Unlike(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLikeUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLikeUseCase creates a new instance of LikeUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLikeUseCase(t mockConstructorTestingTNewLikeUseCase) *LikeUseCase {
+	mock := &LikeUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
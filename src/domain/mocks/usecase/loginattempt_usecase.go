@@ -0,0 +1,122 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// LoginAttemptUseCase is an autogenerated mock type for the LoginAttemptUseCase type
+type LoginAttemptUseCase struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginAttemptUseCase) Check(_a0 context.Context, _a1 string, _a2 string) (time.Duration, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (time.Duration, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) time.Duration); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Fail provides a mock function with given fields: _a0, _a1, _a2
+func (_m *LoginAttemptUseCase) Fail(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Status provides a mock function with given fields: _a0, _a1
+func (_m *LoginAttemptUseCase) Status(_a0 context.Context, _a1 string) (domain.LoginLockStatus, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.LoginLockStatus
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.LoginLockStatus, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.LoginLockStatus); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.LoginLockStatus)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Succeed provides a mock function with given fields: _a0, _a1
+func (_m *LoginAttemptUseCase) Succeed(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Unlock provides a mock function with given fields: _a0, _a1
+func (_m *LoginAttemptUseCase) Unlock(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewLoginAttemptUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewLoginAttemptUseCase creates a new instance of LoginAttemptUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewLoginAttemptUseCase(t mockConstructorTestingTNewLoginAttemptUseCase) *LoginAttemptUseCase {
+	mock := &LoginAttemptUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,166 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MFAUseCase is an autogenerated mock type for the MFAUseCase type
+type MFAUseCase struct {
+	mock.Mock
+}
+
+// Challenge provides a mock function with given fields: _a0, _a1
+func (_m *MFAUseCase) Challenge(_a0 context.Context, _a1 domain.User) (domain.MFAChallenge, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.MFAChallenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) (domain.MFAChallenge, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.MFAChallenge); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.MFAChallenge)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Confirm provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MFAUseCase) Confirm(_a0 context.Context, _a1 string, _a2 string) ([]string, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) ([]string, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) []string); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Disable provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MFAUseCase) Disable(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Enroll provides a mock function with given fields: _a0, _a1
+func (_m *MFAUseCase) Enroll(_a0 context.Context, _a1 domain.User) (domain.MFAEnrollment, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.MFAEnrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) (domain.MFAEnrollment, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) domain.MFAEnrollment); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.MFAEnrollment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IsEnabled provides a mock function with given fields: _a0, _a1
+func (_m *MFAUseCase) IsEnabled(_a0 context.Context, _a1 string) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Verify provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MFAUseCase) Verify(_a0 context.Context, _a1 string, _a2 string) (domain.User, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (domain.User, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) domain.User); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+type mockConstructorTestingTNewMFAUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewMFAUseCase creates a new instance of MFAUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewMFAUseCase(t mockConstructorTestingTNewMFAUseCase) *MFAUseCase {
+	mock := &MFAUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
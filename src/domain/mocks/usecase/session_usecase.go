@@ -0,0 +1,58 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionUseCase is an autogenerated mock type for the SessionUseCase type
+type SessionUseCase struct {
+	mock.Mock
+}
+
+// FindAllActiveByUser provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SessionUseCase) FindAllActiveByUser(_a0 context.Context, _a1 *[]domain.Session, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *[]domain.Session, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Revoke provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SessionUseCase) Revoke(_a0 context.Context, _a1 string, _a2 string) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewSessionUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewSessionUseCase creates a new instance of SessionUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewSessionUseCase(t mockConstructorTestingTNewSessionUseCase) *SessionUseCase {
+	mock := &SessionUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
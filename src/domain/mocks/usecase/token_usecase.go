@@ -0,0 +1,108 @@
+// Code generated by mockery v2.20.0. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/gusrylmubarok/mygram-backend/src/domain"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// TokenUseCase is an autogenerated mock type for the TokenUseCase type
+type TokenUseCase struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: _a0, _a1, _a2
+func (_m *TokenUseCase) Issue(_a0 context.Context, _a1 domain.User, _a2 domain.SessionClient) (domain.Token, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 domain.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, domain.SessionClient) (domain.Token, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, domain.SessionClient) domain.Token); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Get(0).(domain.Token)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.User, domain.SessionClient) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Refresh provides a mock function with given fields: _a0, _a1
+func (_m *TokenUseCase) Refresh(_a0 context.Context, _a1 string) (domain.Token, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 domain.Token
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.Token, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.Token); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(domain.Token)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revoke provides a mock function with given fields: _a0, _a1, _a2, _a3, _a4
+func (_m *TokenUseCase) Revoke(_a0 context.Context, _a1 string, _a2 string, _a3 time.Time, _a4 string) error {
+	ret := _m.Called(_a0, _a1, _a2, _a3, _a4)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Time, string) error); ok {
+		r0 = rf(_a0, _a1, _a2, _a3, _a4)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAll provides a mock function with given fields: _a0, _a1
+func (_m *TokenUseCase) RevokeAll(_a0 context.Context, _a1 string) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+type mockConstructorTestingTNewTokenUseCase interface {
+	mock.TestingT
+	Cleanup(func())
+}
+
+// NewTokenUseCase creates a new instance of TokenUseCase. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+func NewTokenUseCase(t mockConstructorTestingTNewTokenUseCase) *TokenUseCase {
+	mock := &TokenUseCase{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+// NotificationTypeMention is the type of the notification of a user mentioned
+// in a caption or a comment
+const NotificationTypeMention = "mention"
+
+// Notification tells the user that the actor did something about them, like
+// mentioning them on the photo, in the comment when CommentID is set.
+type Notification struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);not null;index" json:"user_id"`
+	ActorID   string     `gorm:"type:VARCHAR(50);not null" json:"actor_id"`
+	Type      string     `gorm:"type:VARCHAR(20);not null" json:"type" example:"mention"`
+	PhotoID   string     `gorm:"type:VARCHAR(50);not null" json:"photo_id"`
+	CommentID *string    `gorm:"type:VARCHAR(50)" json:"comment_id"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Actor     *User      `gorm:"foreignKey:ActorID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"actor,omitempty"`
+	Photo     *Photo     `gorm:"foreignKey:PhotoID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+	Comment   *Comment   `gorm:"foreignKey:CommentID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type NotificationRepository interface {
+	SaveAll(context.Context, []Notification) error
+	MarkRead(context.Context, string, string) error
+	FindAllByUser(context.Context, *[]Notification, string, pagination.Params) error
+}
+
+type NotificationUseCase interface {
+	MarkRead(context.Context, string, string) error
+	FindAllByUser(context.Context, *[]Notification, string, pagination.Params) error
+}
+
+// Represents for response fetched notifications
+type FetchedNotifications struct {
+	Status  string         `json:"status" example:"success"`
+	Message string         `json:"message" example:"message you if the process has been successful"`
+	Data    []Notification `json:"data"`
+	pagination.Meta
+}
+
+// Represents for response read notification
+type ReadNotification struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var ErrInvalidPasswordResetToken = errors.New("the password reset token is invalid or has expired")
+
+// PasswordReset represents a single use password reset token, only the hash
+// of the token is stored.
+type PasswordReset struct {
+	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID    string     `gorm:"type:VARCHAR(50);index;not null" json:"user_id"`
+	TokenHash string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	ExpiresAt *time.Time `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+type PasswordResetRepository interface {
+	Save(context.Context, *PasswordReset) error
+	FindByHash(context.Context, *PasswordReset, string) error
+	Consume(context.Context, PasswordReset, string) error
+}
+
+type PasswordResetUseCase interface {
+	Forgot(context.Context, string) error
+	Reset(context.Context, string, string) error
+}
+
+// Represents for request forgot password
+type ForgotPassword struct {
+	Email string `json:"email" valid:"email,required" example:"johndoe@example.com"`
+}
+
+// Represents for request reset password
+type ResetPassword struct {
+	Token    string `json:"token" valid:"required" example:"the token from the reset mail"`
+	Password string `json:"password" valid:"required,minstringlength(6)" example:"newsecret"`
+}
+
+// Represents for response forgot and reset password
+type PasswordResetResponse struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
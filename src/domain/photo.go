@@ -2,22 +2,43 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"io"
+	"strconv"
 	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	"gorm.io/gorm"
 )
 
 type Photo struct {
-	ID        string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	Title     string     `gorm:"type:VARCHAR(50);not null" valid:"required" form:"title" json:"title" example:"A Photo Title"`
-	Caption   string     `form:"caption" json:"caption"`
-	PhotoUrl  string     `gorm:"not null" valid:"required" form:"photo_url" json:"photo_url" example:"https://www.example.com/image.jpg"`
-	UserID    string     `gorm:"type:VARCHAR(50);not null" json:"user_id"`
-	User      *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"user,omitempty"`
-	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt *time.Time `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
-	Comment   *Comment   `json:"-"`
+	ID              string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	Title           string         `gorm:"type:VARCHAR(50);not null" valid:"required" form:"title" json:"title" example:"A Photo Title"`
+	Caption         string         `form:"caption" json:"caption"`
+	PhotoUrl        string         `gorm:"not null" valid:"required" form:"photo_url" json:"photo_url" example:"https://www.example.com/image.jpg"`
+	StorageKey      string         `gorm:"type:VARCHAR(255)" json:"-"`
+	MimeType        string         `gorm:"type:VARCHAR(50)" json:"mime_type,omitempty"`
+	ByteSize        int64          `json:"byte_size,omitempty"`
+	Width           int            `json:"width,omitempty"`
+	Height          int            `json:"height,omitempty"`
+	CameraModel     string         `gorm:"type:VARCHAR(100)" json:"camera_model,omitempty"`
+	ExposureTime    string         `gorm:"type:VARCHAR(20)" json:"exposure_time,omitempty"`
+	FNumber         float64        `json:"f_number,omitempty"`
+	ISO             int            `json:"iso,omitempty"`
+	TakenAt         *time.Time     `json:"taken_at,omitempty"`
+	LikeCount       int            `gorm:"not null;default:0" json:"like_count"`
+	CommentPolicy   string         `gorm:"type:VARCHAR(20);not null;default:everyone" json:"comment_policy"`
+	PinnedCommentID *string        `gorm:"type:VARCHAR(50)" json:"pinned_comment_id"`
+	Status          string         `gorm:"type:VARCHAR(20);not null;default:ready;index" json:"status"`
+	Variants        []PhotoVariant `gorm:"foreignKey:PhotoID" json:"variants,omitempty"`
+	Hashtags        []Hashtag      `gorm:"many2many:photo_hashtags;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"hashtags,omitempty"`
+	Mentions        []Mention      `gorm:"foreignKey:PhotoID" json:"mentions,omitempty"`
+	UserID          string         `gorm:"type:VARCHAR(50);not null" json:"user_id"`
+	User            *User          `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"user,omitempty"`
+	CreatedAt       *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt       *time.Time     `gorm:"not null;autoCreateTime" json:"updated_at,omitempty"`
+	Comment         *Comment       `json:"-"`
 }
 
 func (photo *Photo) BeforeCreate(db *gorm.DB) (err error) {
@@ -35,20 +56,119 @@ func (photo *Photo) BeforeUpdate(db *gorm.DB) (err error) {
 	return
 }
 
+const (
+	// PhotoStatusProcessing is the status of an uploaded photo until its
+	// variants are generated
+	PhotoStatusProcessing = "processing"
+	PhotoStatusReady      = "ready"
+	// PhotoStatusFailed is the status of an uploaded photo whose variants
+	// couldn't be generated, the original is still served
+	PhotoStatusFailed = "failed"
+)
+
+const (
+	CommentPolicyEveryone = "everyone"
+	// CommentPolicyFollowers only lets the followers of the owner of the
+	// photo, and the owner, comment on it
+	CommentPolicyFollowers = "followers"
+	CommentPolicyOff       = "off"
+)
+
+// VariantURLs maps the width of each variant of the photo, in pixels, to its
+// URL.
+func (photo Photo) VariantURLs() map[string]string {
+	if len(photo.Variants) == 0 {
+		return nil
+	}
+
+	urls := make(map[string]string, len(photo.Variants))
+	for _, variant := range photo.Variants {
+		urls[strconv.Itoa(variant.Width)] = variant.URL
+	}
+
+	return urls
+}
+
+// HashtagNames returns the names of the hashtags of the caption of the photo.
+func (photo Photo) HashtagNames() []string {
+	if len(photo.Hashtags) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(photo.Hashtags))
+	for _, hashtag := range photo.Hashtags {
+		names = append(names, hashtag.Name)
+	}
+
+	return names
+}
+
+// CameraMetadata returns the camera fields the user opted in to keep of the
+// uploaded photo, nil if there are none. The rest of the metadata of an image
+// is never stored.
+func (photo Photo) CameraMetadata() *GetPhotoMetadata {
+	if photo.CameraModel == "" && photo.ExposureTime == "" && photo.FNumber == 0 && photo.ISO == 0 && photo.TakenAt == nil {
+		return nil
+	}
+
+	return &GetPhotoMetadata{
+		CameraModel:  photo.CameraModel,
+		ExposureTime: photo.ExposureTime,
+		FNumber:      photo.FNumber,
+		ISO:          photo.ISO,
+		TakenAt:      photo.TakenAt,
+	}
+}
+
+// PhotoVariant represents a resized copy of an uploaded photo, generated in
+// the background after the upload.
+type PhotoVariant struct {
+	PhotoID    string `gorm:"primaryKey;type:VARCHAR(50)" json:"-"`
+	Width      int    `gorm:"primaryKey;autoIncrement:false" json:"width"`
+	Height     int    `gorm:"not null" json:"height"`
+	StorageKey string `gorm:"type:VARCHAR(255);not null" json:"-"`
+	URL        string `gorm:"not null" json:"url"`
+	Photo      *Photo `gorm:"foreignKey:PhotoID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+var (
+	ErrUnsupportedImage     = errors.New("the photo must be a JPEG, PNG or GIF image")
+	ErrImageTooLarge        = errors.New("the photo is too large")
+	ErrInvalidCommentPolicy = errors.New("the comment policy must be one of everyone, followers or off")
+	ErrCommentsOff          = errors.New("comments are turned off on this photo")
+	ErrCommentsFollowsOnly  = errors.New("only the followers of the owner can comment on this photo")
+	ErrPhotoUrlUploaded     = errors.New("the photo url of an uploaded photo can't be changed")
+	// ErrProcessingQueueFull is returned when an uploaded photo can't be
+	// queued for its variants, it stays processing until the next start
+	ErrProcessingQueueFull = errors.New("the photo processing queue is full")
+)
+
 type PhotoRepository interface {
 	Save(context.Context, *Photo) error
 	Update(context.Context, Photo, string) (Photo, error)
 	DeleteById(context.Context, string) error
-	FindAll(context.Context, *[]Photo) error
+	FindAll(context.Context, *[]Photo, pagination.Params) error
 	FindById(context.Context, *Photo, string) error
+	FindProcessing(context.Context, *[]Photo) error
+	SaveVariants(context.Context, string, []PhotoVariant, string) error
+	UpdateCommentPolicy(context.Context, string, string) error
+	UpdatePinnedComment(context.Context, string, *string) error
+}
+
+// PhotoProcessor generates the variants of uploaded photos out of the
+// request that uploaded them.
+type PhotoProcessor interface {
+	Enqueue(context.Context, Photo) error
 }
 
 type PhotoUseCase interface {
 	Save(context.Context, *Photo) error
+	Upload(context.Context, *Photo, io.Reader, bool) error
 	Update(context.Context, Photo, string) (Photo, error)
 	DeleteById(context.Context, string) error
-	FindAll(context.Context, *[]Photo) error
+	FindAll(context.Context, *[]Photo, pagination.Params) error
 	FindById(context.Context, *Photo, string) error
+	UpdateCommentPolicy(context.Context, string, string) (Photo, error)
 }
 
 // Represents for request add photo
@@ -63,6 +183,8 @@ type AddedDataPhoto struct {
 	ID        string     `json:"id"`
 	Title     string     `json:"title" form:"title" example:"A Photo Title"`
 	Caption   string     `json:"caption" form:"caption" example:"A caption"`
+	Hashtags  []string   `json:"hashtags" example:"sunset,beach"`
+	Mentions  []Mention  `json:"mentions"`
 	PhotoUrl  string     `json:"photo_url"  form:"photo_url" example:"https://www.example.com/image.jpg"`
 	User      *GetUser   `json:"user"`
 	CreatedAt *time.Time `json:"created_at" example:"create time should be here"`
@@ -75,6 +197,47 @@ type AddedPhoto struct {
 	Data    AddedDataPhoto `json:"data"`
 }
 
+// Represents for multipart request upload photo, the image is the photo file
+type UploadPhoto struct {
+	Title        string `form:"title" example:"A Photo Title"`
+	Caption      string `form:"caption" example:"A caption"`
+	KeepMetadata bool   `form:"keep_metadata" example:"false"`
+}
+
+// Represents for the camera metadata kept of a photo
+type GetPhotoMetadata struct {
+	CameraModel  string     `json:"camera_model,omitempty" example:"Pixel 7"`
+	ExposureTime string     `json:"exposure_time,omitempty" example:"1/125"`
+	FNumber      float64    `json:"f_number,omitempty" example:"1.85"`
+	ISO          int        `json:"iso,omitempty" example:"100"`
+	TakenAt      *time.Time `json:"taken_at,omitempty"`
+}
+
+// Represents for uploaded photo
+type UploadedDataPhoto struct {
+	ID        string            `json:"id"`
+	Title     string            `json:"title" example:"A Photo Title"`
+	Caption   string            `json:"caption" example:"A caption"`
+	Hashtags  []string          `json:"hashtags" example:"sunset,beach"`
+	Mentions  []Mention         `json:"mentions"`
+	PhotoUrl  string            `json:"photo_url" example:"http://localhost:8080/public/uploads/photos/user-123/abc.jpg"`
+	MimeType  string            `json:"mime_type" example:"image/jpeg"`
+	ByteSize  int64             `json:"byte_size" example:"245760"`
+	Width     int               `json:"width" example:"1080"`
+	Height    int               `json:"height" example:"1350"`
+	Status    string            `json:"status" example:"processing"`
+	Metadata  *GetPhotoMetadata `json:"metadata,omitempty"`
+	User      *GetUser          `json:"user"`
+	CreatedAt *time.Time        `json:"created_at" example:"create time should be here"`
+}
+
+// Represents for response uploaded photo
+type UploadedPhoto struct {
+	Status  string            `json:"status" example:"success"`
+	Message string            `json:"message" example:"message you if the process has been successful"`
+	Data    UploadedDataPhoto `json:"data"`
+}
+
 // Represents for request update photo
 type UpdatePhoto struct {
 	Title    string `json:"title" example:"A new title"`
@@ -88,11 +251,31 @@ type UpdatedDataPhoto struct {
 	ID        string     `json:"id"`
 	Title     string     `json:"title"`
 	Caption   string     `json:"caption"`
+	Hashtags  []string   `json:"hashtags"`
+	Mentions  []Mention  `json:"mentions"`
 	PhotoUrl  string     `json:"photo_url"`
 	User      *GetUser   `json:"user"`
 	UpdatedAt *time.Time `json:"updated_at"`
 }
 
+// Represents for request update the comment policy of a photo
+type UpdateCommentPolicy struct {
+	CommentPolicy string `json:"comment_policy" example:"followers"`
+}
+
+// Represents for the comment settings of a photo
+type CommentSettings struct {
+	CommentPolicy   string  `json:"comment_policy" example:"followers"`
+	PinnedCommentID *string `json:"pinned_comment_id" example:"comment-123"`
+}
+
+// Represents for response updated comment settings
+type UpdatedCommentSettings struct {
+	Status  string          `json:"status" example:"success"`
+	Message string          `json:"message" example:"message you if the process has been successful"`
+	Data    CommentSettings `json:"data"`
+}
+
 // Represents for updated photo
 type UpdatedPhoto struct {
 	Status  string           `json:"status" example:"success"`
@@ -116,19 +299,29 @@ type GetPhoto struct {
 
 // RepresentGetDetailPhoto
 type GetDetailPhoto struct {
-	ID        string     `json:"id"`
-	Title     string     `json:"title,"`
-	Caption   string     `json:"caption"`
-	PhotoUrl  string     `json:"photo_url"`
-	User      *GetUser   `json:"user"`
-	CreatedAt *time.Time `json:"created_at"`
-	UpdatedAt *time.Time `json:"updated_at"`
+	ID              string            `json:"id"`
+	Title           string            `json:"title,"`
+	Caption         string            `json:"caption"`
+	PhotoUrl        string            `json:"photo_url"`
+	Status          string            `json:"status" example:"ready"`
+	Variants        map[string]string `json:"variants,omitempty"`
+	Hashtags        []string          `json:"hashtags,omitempty" example:"sunset,beach"`
+	Mentions        []Mention         `json:"mentions,omitempty"`
+	Metadata        *GetPhotoMetadata `json:"metadata,omitempty"`
+	LikeCount       int               `json:"like_count" example:"42"`
+	Liked           bool              `json:"liked" example:"false"`
+	CommentPolicy   string            `json:"comment_policy" example:"everyone"`
+	PinnedCommentID *string           `json:"pinned_comment_id" example:"comment-123"`
+	User            *GetUser          `json:"user"`
+	CreatedAt       *time.Time        `json:"created_at"`
+	UpdatedAt       *time.Time        `json:"updated_at"`
 }
 
 type GetAllPhotos struct {
 	Status  string            `json:"status" example:"success"`
 	Message string            `json:"message" example:"message you if the process has been successful"`
 	Data    []*GetDetailPhoto `json:"data"`
+	pagination.Meta
 }
 
 type GetByIdPhoto struct {
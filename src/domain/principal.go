@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Ways a principal can be authenticated.
+const (
+	AuthMethodAccessToken = "access_token"
+	AuthMethodAPIKey      = "api_key"
+)
+
+var ErrUnauthenticated = errors.New("sign in to proceed")
+
+// Principal represents the authenticated caller of a request, it is put in
+// the context of the request by middleware.Authentication.
+type Principal struct {
+	UserID string
+	Email  string
+	Roles  []string
+	// Scopes only limits principals authenticated with an API key, an access
+	// token may use every scope.
+	Scopes []string
+	// TokenID is the jti of the access token or the id of the API key.
+	TokenID string
+	// SessionID is the session of the access token, empty for API keys.
+	SessionID  string
+	AuthMethod string
+	// ExpiresAt is zero for API keys that never expire.
+	ExpiresAt time.Time
+}
+
+// HasRole reports whether the principal has one of the roles.
+func (principal Principal) HasRole(roles ...string) bool {
+	for _, role := range roles {
+		for _, granted := range principal.Roles {
+			if granted == role {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// HasScope reports whether the principal may use the scope.
+func (principal Principal) HasScope(scope string) bool {
+	if principal.AuthMethod != AuthMethodAPIKey {
+		return true
+	}
+
+	for _, granted := range principal.Scopes {
+		if granted == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+type principalContextKey struct{}
+
+// ContextWithPrincipal returns a copy of ctx carrying the principal.
+func ContextWithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal ctx carries, ok is false for
+// requests that weren't authenticated.
+func PrincipalFromContext(ctx context.Context) (principal Principal, ok bool) {
+	principal, ok = ctx.Value(principalContextKey{}).(Principal)
+
+	return principal, ok
+}
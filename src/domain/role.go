@@ -0,0 +1,34 @@
+package domain
+
+import "errors"
+
+// Roles of a user, carried in the role claim of the access token.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+var ErrInvalidRole = errors.New("the role must be one of user, moderator or admin")
+
+// IsValidRole reports whether role is one of the roles a user can have.
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleModerator, RoleAdmin:
+		return true
+	}
+
+	return false
+}
+
+// Represents for request update role
+type UpdateRole struct {
+	Role string `json:"role" example:"moderator"`
+}
+
+// Represents for response updated role
+type UpdatedRole struct {
+	Status  string  `json:"status" example:"success"`
+	Message string  `json:"message" example:"message you if the process has been successful"`
+	Data    GetUser `json:"data"`
+}
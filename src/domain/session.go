@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Session represents a login of a user on a device. Every refresh token of
+// the login shares the id of the session as its FamilyID and every access
+// token carries it as the sid claim.
+type Session struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);index;not null" json:"user_id"`
+	UserAgent  string     `gorm:"type:VARCHAR(255)" json:"user_agent"`
+	IP         string     `gorm:"type:VARCHAR(45)" json:"ip"`
+	LastSeenAt *time.Time `gorm:"not null" json:"last_seen_at"`
+	ExpiresAt  *time.Time `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// SessionClient represents the device a user signs in from.
+type SessionClient struct {
+	UserAgent string
+	IP        string
+}
+
+type SessionRepository interface {
+	Save(context.Context, *Session) error
+	FindAllActiveByUser(context.Context, *[]Session, string) error
+	Touch(context.Context, string, time.Time, time.Time) error
+	Revoke(context.Context, string, string) error
+	RevokeAllByUser(context.Context, string) error
+}
+
+type SessionUseCase interface {
+	FindAllActiveByUser(context.Context, *[]Session, string) error
+	Revoke(context.Context, string, string) error
+}
+
+// Represents for session
+type GetSession struct {
+	ID         string     `json:"id" example:"here is the generated session id"`
+	UserAgent  string     `json:"user_agent" example:"Mozilla/5.0 (X11; Linux x86_64)"`
+	IP         string     `json:"ip" example:"203.0.113.7"`
+	Current    bool       `json:"current" example:"true"`
+	CreatedAt  *time.Time `json:"created_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+}
+
+// Represents for response fetched sessions
+type FetchedSessions struct {
+	Status  string       `json:"status" example:"success"`
+	Message string       `json:"message" example:"message you if the process has been successful"`
+	Data    []GetSession `json:"data"`
+}
+
+// Represents for response revoked session
+type RevokedSession struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+}
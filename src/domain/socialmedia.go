@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	"gorm.io/gorm"
 )
 
@@ -37,7 +38,7 @@ type SocialMediaUseCase interface {
 	Save(context.Context, *SocialMedia) error
 	Update(context.Context, SocialMedia, string) (SocialMedia, error)
 	DeleteById(context.Context, string) error
-	FindAllByUser(context.Context, *[]SocialMedia, string) error
+	FindAllByUser(context.Context, *[]SocialMedia, string, pagination.Params) error
 	FindById(context.Context, *SocialMedia, string) error
 }
 
@@ -45,7 +46,7 @@ type SocialMediaRepository interface {
 	Save(context.Context, *SocialMedia) error
 	Update(context.Context, SocialMedia, string) (SocialMedia, error)
 	DeleteById(context.Context, string) error
-	FindAllByUser(context.Context, *[]SocialMedia, string) error
+	FindAllByUser(context.Context, *[]SocialMedia, string, pagination.Params) error
 	FindById(context.Context, *SocialMedia, string) error
 }
 
@@ -100,6 +101,13 @@ type GetDataSocialMedia struct {
 	SocialMedias interface{} `json:"social_medias"`
 }
 
+// Represents for response fetched social medias of a user
+type GetAllSocialMedias struct {
+	Status string             `json:"status" example:"success"`
+	Data   GetDataSocialMedia `json:"data"`
+	pagination.Meta
+}
+
 type ResponseDataFetchedSocialMedia struct {
 	Status  string       `json:"status" example:"success"`
 	Message string       `json:"message" example:"message you if the process has been successful"`
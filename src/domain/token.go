@@ -0,0 +1,127 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("the refresh token is invalid or has expired")
+	ErrRefreshTokenReused  = errors.New("the refresh token has already been used, please sign in again")
+	ErrTokenRevoked        = errors.New("the token has been revoked, please sign in again")
+)
+
+// RefreshToken represents a persisted refresh token. Only the hash of the token
+// is stored, every token created by rotating another one shares its FamilyID.
+type RefreshToken struct {
+	ID         string     `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	UserID     string     `gorm:"type:VARCHAR(50);index;not null" json:"user_id"`
+	FamilyID   string     `gorm:"type:VARCHAR(50);index;not null" json:"family_id"`
+	TokenHash  string     `gorm:"type:VARCHAR(64);uniqueIndex;not null" json:"-"`
+	ReplacedBy string     `gorm:"type:VARCHAR(50)" json:"replaced_by,omitempty"`
+	ExpiresAt  *time.Time `gorm:"not null" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	User       *User      `gorm:"foreignKey:UserID;constraint:onUpdate:CASCADE,onDelete:CASCADE" json:"-"`
+}
+
+// RevokedToken represents an access token rejected before its expiry, it is
+// kept only until the token would have expired anyway.
+type RevokedToken struct {
+	JTI       string     `gorm:"primaryKey;type:VARCHAR(50)" json:"jti"`
+	ExpiresAt *time.Time `gorm:"index;not null" json:"expires_at"`
+	CreatedAt *time.Time `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+}
+
+// UserTokenVersion represents the current token version of a user. Access
+// tokens carrying a lower version are rejected.
+type UserTokenVersion struct {
+	UserID    string     `gorm:"primaryKey;type:VARCHAR(50)" json:"user_id"`
+	Version   uint       `gorm:"not null;default:0" json:"version"`
+	UpdatedAt *time.Time `gorm:"not null;autoUpdateTime" json:"updated_at,omitempty"`
+}
+
+type RefreshTokenRepository interface {
+	Save(context.Context, *RefreshToken) error
+	FindByHash(context.Context, *RefreshToken, string) error
+	Rotate(context.Context, RefreshToken, *RefreshToken) error
+	RevokeFamily(context.Context, string) error
+	RevokeAllByUser(context.Context, string) error
+}
+
+// RevocationStore keeps track of access tokens which must be rejected before
+// they expire, either a single token by its jti, every token of a session by
+// the id of the session or every token of a user by bumping the user's token
+// version.
+type RevocationStore interface {
+	Revoke(context.Context, string, time.Time) error
+	IsRevoked(context.Context, string) (bool, error)
+	TokenVersion(context.Context, string) (uint, error)
+	BumpTokenVersion(context.Context, string) (uint, error)
+}
+
+// Types of the tokens the TokenSigner signs, kept in the typ claim so a token
+// issued for one purpose is never accepted for another.
+const (
+	TokenTypeAccess            = "access"
+	TokenTypeEmailVerification = "email_verification"
+	TokenTypeMFAChallenge      = "mfa_challenge"
+)
+
+// TokenClaims are the claims of a signed token.
+type TokenClaims map[string]interface{}
+
+// TokenSigner signs the tokens of the api with a type and verifies them, a
+// token only parses with the type it was signed with.
+type TokenSigner interface {
+	Sign(string, TokenClaims, time.Duration) (string, error)
+	Parse(string, string) (TokenClaims, error)
+}
+
+type TokenUseCase interface {
+	Issue(context.Context, User, SessionClient) (Token, error)
+	Refresh(context.Context, string) (Token, error)
+	Revoke(context.Context, string, string, time.Time, string) error
+	RevokeAll(context.Context, string) error
+}
+
+// Represents for request refresh token
+type RefreshUserToken struct {
+	RefreshToken string `json:"refresh_token" example:"the refresh token from login"`
+}
+
+// Represents for response refreshed token
+type RefreshedToken struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+	Data    Token  `json:"data"`
+}
+
+// Represents for request logout
+type LogoutUser struct {
+	RefreshToken string `json:"refresh_token" example:"the refresh token from login"`
+}
+
+// Represents for response logged out user
+type LoggedOutUser struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"you have been logged out"`
+}
+
+// Represents for a public key of the json web key set
+type JWK struct {
+	KeyType   string `json:"kty" example:"OKP"`
+	KeyID     string `json:"kid" example:"2023-04-01"`
+	Algorithm string `json:"alg" example:"EdDSA"`
+	Use       string `json:"use" example:"sig"`
+	Curve     string `json:"crv,omitempty" example:"Ed25519"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// Represents for response json web key set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
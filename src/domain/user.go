@@ -11,23 +11,38 @@ import (
 
 // User represents entity for a user
 type User struct {
-	ID           string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
-	Username     string         `gorm:"type:VARCHAR(50);index:idx_username;unique;not null" valid:"required" form:"username" json:"username" example:"johndoe"`
-	Email        string         `gorm:"type:VARCHAR(50);index:idx_email;unique;not null" valid:"email,required" form:"email" json:"email" example:"johndoe@example.com"`
-	Password     string         `gorm:"not null" valid:"required,minstringlength(6)" form:"password" json:"password,omitempty" example:"secret"`
-	Age          uint           `gorm:"not null" valid:"required,range(8|63)" form:"age" json:"age,omitempty" example:"8"`
-	CreatedAt    *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
-	UpdatedAt    *time.Time     `gorm:"not null;autocreateTime" json:"updated_at,omitempty"`
-	Photos       *[]Photo       `json:"-"`
-	SocialMedias *[]SocialMedia `json:"-"`
+	ID              string         `gorm:"primaryKey;type:VARCHAR(50)" json:"id"`
+	Username        string         `gorm:"type:VARCHAR(50);index:idx_username;unique;not null" valid:"required" form:"username" json:"username" example:"johndoe"`
+	Email           string         `gorm:"type:VARCHAR(50);index:idx_email;unique;not null" valid:"email,required" form:"email" json:"email" example:"johndoe@example.com"`
+	Password        string         `gorm:"not null" valid:"required,minstringlength(6)" form:"password" json:"password,omitempty" example:"secret"`
+	Age             uint           `gorm:"not null" valid:"required,range(8|63)" form:"age" json:"age,omitempty" example:"8"`
+	PendingEmail    string         `gorm:"type:VARCHAR(50)" valid:"email,optional" json:"pending_email,omitempty" example:"newjohndoe@example.com"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at,omitempty"`
+	Role            string         `gorm:"type:VARCHAR(20);not null;default:user" valid:"in(user|moderator|admin),optional" json:"role,omitempty" example:"user"`
+	DisplayName     string         `gorm:"type:VARCHAR(50)" valid:"stringlength(1|50),optional" json:"display_name,omitempty" example:"John Doe"`
+	Bio             string         `gorm:"type:VARCHAR(160)" valid:"stringlength(1|160),optional" json:"bio,omitempty" example:"Taking photos of cats"`
+	AvatarURL       string         `valid:"url,optional" json:"avatar_url,omitempty" example:"https://www.example.com/avatar.jpg"`
+	Website         string         `valid:"url,optional" json:"website,omitempty" example:"https://johndoe.example.com"`
+	FollowerCount   int64          `gorm:"not null;default:0" json:"-"`
+	FollowingCount  int64          `gorm:"not null;default:0" json:"-"`
+	CreatedAt       *time.Time     `gorm:"not null;autoCreateTime" json:"created_at,omitempty"`
+	UpdatedAt       *time.Time     `gorm:"not null;autocreateTime" json:"updated_at,omitempty"`
+	Photos          *[]Photo       `json:"-"`
+	SocialMedias    *[]SocialMedia `json:"-"`
 }
 
 func (user *User) BeforeCreate(db *gorm.DB) (err error) {
+	if user.Role == "" {
+		user.Role = RoleUser
+	}
+
 	if _, err := govalidator.ValidateStruct(user); err != nil {
 		return err
 	}
 
-	user.Password = helpers.Hash(user.Password)
+	if user.Password, err = helpers.Hash(user.Password); err != nil {
+		return err
+	}
 
 	return
 }
@@ -47,6 +62,11 @@ type UserUseCase interface {
 	DeleteById(context.Context, string) error
 	FindByEmail(context.Context, *User) (User, error)
 	FindByUsername(context.Context, *User) (User, error)
+	FindById(context.Context, string) (User, error)
+	VerifyEmail(context.Context, string, string) (User, error)
+	UpdatePassword(context.Context, string, string, string) error
+	UpdateRole(context.Context, string, string) (User, error)
+	FindProfile(context.Context, string) (UserProfile, error)
 }
 
 type UserRepository interface {
@@ -56,6 +76,11 @@ type UserRepository interface {
 	DeleteById(context.Context, string) error
 	FindByEmail(context.Context, *User) (User, error)
 	FindByUsername(context.Context, *User) (User, error)
+	FindById(context.Context, string) (User, error)
+	VerifyEmail(context.Context, string, string) (User, error)
+	UpdatePassword(context.Context, string, string, string) error
+	UpdateRole(context.Context, string, string) (User, error)
+	FindProfile(context.Context, string) (UserProfile, error)
 }
 
 // Represents for register user
@@ -81,7 +106,11 @@ type LoginUser struct {
 
 // Represents for jwt user
 type Token struct {
-	Token string `json:"token" example:"the token generated here"`
+	Token                 string `json:"token" example:"the token generated here"`
+	TokenType             string `json:"token_type" example:"Bearer"`
+	ExpiresIn             int64  `json:"expires_in" example:"900"`
+	RefreshToken          string `json:"refresh_token" example:"the refresh token generated here"`
+	RefreshTokenExpiresIn int64  `json:"refresh_token_expires_in" example:"2592000"`
 }
 
 // Represents for loggedin user
@@ -91,20 +120,29 @@ type LoggedInUser struct {
 	Data    Token  `json:"data"`
 }
 
-// Represents for update user
+// Represents for update user, empty fields are left unchanged
 type UpdateUser struct {
-	Email    string `json:"email" example:"newjohndoe@example.com"`
-	Username string `json:"username" example:"newjohndoe"`
-	Age      uint   `json:"age" example:"8"`
+	Email       string `json:"email" example:"newjohndoe@example.com"`
+	Username    string `json:"username" example:"newjohndoe"`
+	Age         uint   `json:"age" example:"8"`
+	DisplayName string `json:"display_name" valid:"stringlength(1|50),optional" example:"John Doe"`
+	Bio         string `json:"bio" valid:"stringlength(1|160),optional" example:"Taking photos of cats"`
+	AvatarURL   string `json:"avatar_url" valid:"url,optional" example:"https://www.example.com/avatar.jpg"`
+	Website     string `json:"website" valid:"url,optional" example:"https://johndoe.example.com"`
 }
 
 // Represents for updated user
 type UpdatedDataUser struct {
-	ID        string     `json:"id" example:"here is the generated user id"`
-	Email     string     `json:"email" example:"newjohndoe@example.com"`
-	Username  string     `json:"username" example:"newjohndoe"`
-	Age       uint       `json:"age" example:"8"`
-	UpdatedAt *time.Time `json:"updated_at" example:"update time should be here"`
+	ID           string     `json:"id" example:"here is the generated user id"`
+	Email        string     `json:"email" example:"johndoe@example.com"`
+	PendingEmail string     `json:"pending_email,omitempty" example:"newjohndoe@example.com"`
+	Username     string     `json:"username" example:"newjohndoe"`
+	Age          uint       `json:"age" example:"8"`
+	DisplayName  string     `json:"display_name,omitempty" example:"John Doe"`
+	Bio          string     `json:"bio,omitempty" example:"Taking photos of cats"`
+	AvatarURL    string     `json:"avatar_url,omitempty" example:"https://www.example.com/avatar.jpg"`
+	Website      string     `json:"website,omitempty" example:"https://johndoe.example.com"`
+	UpdatedAt    *time.Time `json:"updated_at" example:"update time should be here"`
 }
 
 // Represents for response updated user
@@ -114,6 +152,19 @@ type UpdatedUser struct {
 	Data    UpdatedDataUser `json:"data"`
 }
 
+// Represents for change password user
+type ChangePassword struct {
+	CurrentPassword string `json:"current_password" valid:"required" example:"secret"`
+	NewPassword     string `json:"new_password" valid:"required,minstringlength(6)" example:"newsecret"`
+}
+
+// Represents for response changed password user
+type ChangedPassword struct {
+	Status  string `json:"status" example:"success"`
+	Message string `json:"message" example:"message you if the process has been successful"`
+	Data    Token  `json:"data"`
+}
+
 // Represents for response deleted user
 type DeletedUser struct {
 	Status  string `json:"status" example:"success"`
@@ -125,4 +176,27 @@ type GetUser struct {
 	ID       string `json:"id"`
 	Username string `json:"username" example:"newjohndoe"`
 	Email    string `json:"email" example:"newjohndoe@example.com"`
+	Role     string `json:"role,omitempty" example:"user"`
+}
+
+// UserProfile represents the public projection of a user, it never holds the
+// email or the age.
+type UserProfile struct {
+	ID             string     `json:"id" example:"here is the generated user id"`
+	Username       string     `json:"username" example:"johndoe"`
+	DisplayName    string     `json:"display_name,omitempty" example:"John Doe"`
+	Bio            string     `json:"bio,omitempty" example:"Taking photos of cats"`
+	AvatarURL      string     `json:"avatar_url,omitempty" example:"https://www.example.com/avatar.jpg"`
+	Website        string     `json:"website,omitempty" example:"https://johndoe.example.com"`
+	PhotoCount     int64      `json:"photo_count" example:"12"`
+	FollowerCount  int64      `json:"follower_count" example:"34"`
+	FollowingCount int64      `json:"following_count" example:"56"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+}
+
+// Represents for response fetched user profile
+type FetchedUserProfile struct {
+	Status  string      `json:"status" example:"success"`
+	Message string      `json:"message" example:"message you if the process has been successful"`
+	Data    UserProfile `json:"data"`
 }
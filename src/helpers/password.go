@@ -0,0 +1,222 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var errUnknownPasswordHash = errors.New("unknown password hash format")
+
+// PasswordHasher hashes passwords for one scheme. Hashes are self describing,
+// they carry the scheme and its parameters so a hash keeps verifying after the
+// current scheme or parameters change.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(hashedPassword string, password string) (bool, error)
+	// NeedsRehash tells whether the hash was made with other parameters than
+	// the ones of the hasher.
+	NeedsRehash(hashedPassword string) bool
+	Identifies(hashedPassword string) bool
+}
+
+// Argon2idParams are the cost parameters of argon2id, Memory is in KiB.
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultArgon2idParams follows the OWASP recommendation for argon2id.
+var DefaultArgon2idParams = Argon2idParams{
+	Memory:      19 * 1024,
+	Iterations:  2,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+var (
+	passwordHasherMu sync.RWMutex
+	passwordHasher   PasswordHasher = NewArgon2idHasher(DefaultArgon2idParams)
+
+	// legacyPasswordHashers only verify, their hashes are replaced on the next login
+	legacyPasswordHashers = []PasswordHasher{bcryptHasher{}}
+)
+
+// SetPasswordHasher sets the hasher new passwords are hashed with.
+func SetPasswordHasher(hasher PasswordHasher) {
+	passwordHasherMu.Lock()
+	defer passwordHasherMu.Unlock()
+
+	passwordHasher = hasher
+}
+
+func currentPasswordHasher() PasswordHasher {
+	passwordHasherMu.RLock()
+	defer passwordHasherMu.RUnlock()
+
+	return passwordHasher
+}
+
+// Hash hashes the password with the current hasher.
+func Hash(password string) (string, error) {
+	return currentPasswordHasher().Hash(password)
+}
+
+// Compare tells whether password matches the hash, whichever supported scheme
+// the hash was made with.
+func Compare(hashedPassword, password []byte) bool {
+	hasher, err := passwordHasherOf(string(hashedPassword))
+
+	if err != nil {
+		return false
+	}
+
+	isValid, err := hasher.Verify(string(hashedPassword), string(password))
+
+	return err == nil && isValid
+}
+
+// NeedsRehash tells whether the hash should be replaced by one of the current
+// hasher, which is the case for every legacy scheme.
+func NeedsRehash(hashedPassword string) bool {
+	hasher := currentPasswordHasher()
+
+	return !hasher.Identifies(hashedPassword) || hasher.NeedsRehash(hashedPassword)
+}
+
+func passwordHasherOf(hashedPassword string) (PasswordHasher, error) {
+	if hasher := currentPasswordHasher(); hasher.Identifies(hashedPassword) {
+		return hasher, nil
+	}
+
+	for _, hasher := range legacyPasswordHashers {
+		if hasher.Identifies(hashedPassword) {
+			return hasher, nil
+		}
+	}
+
+	return nil, errUnknownPasswordHash
+}
+
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+func NewArgon2idHasher(params Argon2idParams) PasswordHasher {
+	return argon2idHasher{params}
+}
+
+// Hash returns the hash in the PHC string format,
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>.
+func (hasher argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, hasher.params.SaltLength)
+
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, hasher.params.Iterations, hasher.params.Memory, hasher.params.Parallelism, hasher.params.KeyLength)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, hasher.params.Memory, hasher.params.Iterations, hasher.params.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func (hasher argon2idHasher) Verify(hashedPassword string, password string) (bool, error) {
+	params, salt, key, err := decodeArgon2idHash(hashedPassword)
+
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+func (hasher argon2idHasher) NeedsRehash(hashedPassword string) bool {
+	params, _, _, err := decodeArgon2idHash(hashedPassword)
+
+	return err != nil || params != hasher.params
+}
+
+func (hasher argon2idHasher) Identifies(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$argon2id$")
+}
+
+func decodeArgon2idHash(hashedPassword string) (params Argon2idParams, salt []byte, key []byte, err error) {
+	var version int
+
+	parts := strings.Split(hashedPassword, "$")
+
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	if _, err = fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	if _, err = fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil {
+		return params, nil, nil, errUnknownPasswordHash
+	}
+
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
+
+// bcryptHasher verifies the hashes made before argon2id was introduced.
+type bcryptHasher struct{}
+
+func (hasher bcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+func (hasher bcryptHasher) Verify(hashedPassword string, password string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (hasher bcryptHasher) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+
+	return err != nil || cost < bcrypt.DefaultCost
+}
+
+func (hasher bcryptHasher) Identifies(hashedPassword string) bool {
+	return strings.HasPrefix(hashedPassword, "$2a$") || strings.HasPrefix(hashedPassword, "$2b$") || strings.HasPrefix(hashedPassword, "$2y$")
+}
@@ -0,0 +1,22 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// GenerateSecureToken returns a random url-safe token suitable for opaque
+// credentials such as refresh tokens.
+func GenerateSecureToken() (string, error) {
+	return gonanoid.New(48)
+}
+
+// HashToken returns the hex encoded sha256 digest of an opaque token, this is
+// the only form in which such tokens are persisted.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(sum[:])
+}
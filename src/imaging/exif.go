@@ -0,0 +1,244 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	tagModel              = 0x0110
+	tagOrientation        = 0x0112
+	tagExifIFD            = 0x8769
+	tagExposureTime       = 0x829A
+	tagFNumber            = 0x829D
+	tagISO                = 0x8827
+	tagDateTimeOriginal   = 0x9003
+	tagOffsetTimeOriginal = 0x9011
+)
+
+// Metadata is the EXIF of a photo that tells nothing of where it was taken
+// nor of who took it.
+type Metadata struct {
+	CameraModel  string
+	ExposureTime string
+	FNumber      float64
+	ISO          int
+	TakenAt      *time.Time
+}
+
+// Orientation reads the EXIF orientation of a JPEG image, from 1 (upright)
+// to 8. Images without one, or with one that can't be read, are upright.
+func Orientation(data []byte) int {
+	exif, ok := readExif(data)
+	if !ok {
+		return 1
+	}
+
+	orientation, ok := exif.uint(exif.ifd0, tagOrientation)
+	if !ok || orientation < 1 || orientation > 8 {
+		return 1
+	}
+
+	return int(orientation)
+}
+
+// Rotated tells if the image of the orientation is displayed with its width
+// and height swapped.
+func Rotated(orientation int) bool {
+	return orientation >= 5 && orientation <= 8
+}
+
+// ReadMetadata reads the Metadata of a JPEG image, the fields it doesn't have
+// are left empty.
+func ReadMetadata(data []byte) (metadata Metadata) {
+	exif, ok := readExif(data)
+	if !ok {
+		return metadata
+	}
+
+	metadata.CameraModel = exif.string(exif.ifd0, tagModel)
+
+	offset, ok := exif.uint(exif.ifd0, tagExifIFD)
+	if !ok {
+		return metadata
+	}
+
+	sub := exif.entries(int(offset))
+
+	if numerator, denominator, ok := exif.rational(sub, tagExposureTime); ok && denominator != 0 {
+		if numerator < denominator && numerator != 0 {
+			metadata.ExposureTime = fmt.Sprintf("1/%d", (denominator+numerator/2)/numerator)
+		} else {
+			metadata.ExposureTime = fmt.Sprintf("%g", float64(numerator)/float64(denominator))
+		}
+	}
+
+	if numerator, denominator, ok := exif.rational(sub, tagFNumber); ok && denominator != 0 {
+		metadata.FNumber = float64(numerator) / float64(denominator)
+	}
+
+	if iso, ok := exif.uint(sub, tagISO); ok {
+		metadata.ISO = int(iso)
+	}
+
+	// the time is local to the camera, taken as UTC without an offset
+	if value := exif.string(sub, tagDateTimeOriginal); value != "" {
+		if offset := exif.string(sub, tagOffsetTimeOriginal); offset != "" {
+			value += offset
+		} else {
+			value += "+00:00"
+		}
+
+		if takenAt, err := time.Parse("2006:01:02 15:04:05-07:00", value); err == nil {
+			takenAt = takenAt.UTC()
+			metadata.TakenAt = &takenAt
+		}
+	}
+
+	return metadata
+}
+
+// exif reads the IFDs of the TIFF structure of an APP1 Exif segment.
+type exif struct {
+	data  []byte
+	order binary.ByteOrder
+	ifd0  map[uint16][]byte
+}
+
+// readExif finds and reads the APP1 Exif segment of a JPEG image.
+func readExif(data []byte) (exif exif, ok bool) {
+	exif.data = exifSegment(data)
+	if exif.data == nil {
+		return exif, false
+	}
+
+	switch {
+	case bytes.HasPrefix(exif.data, []byte("II*\x00")):
+		exif.order = binary.LittleEndian
+	case bytes.HasPrefix(exif.data, []byte("MM\x00*")):
+		exif.order = binary.BigEndian
+	default:
+		return exif, false
+	}
+
+	exif.ifd0 = exif.entries(int(exif.order.Uint32(exif.data[4:8])))
+
+	return exif, true
+}
+
+// entries reads the IFD at the offset into the values of its tags, the
+// entries out of bounds are skipped.
+func (exif exif) entries(offset int) map[uint16][]byte {
+	entries := map[uint16][]byte{}
+
+	if offset < 8 || offset+2 > len(exif.data) {
+		return entries
+	}
+
+	count := int(exif.order.Uint16(exif.data[offset:]))
+	for i := 0; i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > len(exif.data) {
+			break
+		}
+
+		size := typeSize(exif.order.Uint16(exif.data[entry+2:])) * int(exif.order.Uint32(exif.data[entry+4:]))
+		if size <= 0 {
+			continue
+		}
+
+		// values of up to 4 bytes are kept in the entry, others at an offset
+		start := entry + 8
+		if size > 4 {
+			start = int(exif.order.Uint32(exif.data[entry+8:]))
+		}
+
+		if start < 0 || start+size > len(exif.data) {
+			continue
+		}
+
+		entries[exif.order.Uint16(exif.data[entry:])] = exif.data[start : start+size]
+	}
+
+	return entries
+}
+
+func (exif exif) uint(entries map[uint16][]byte, tag uint16) (uint32, bool) {
+	value := entries[tag]
+
+	switch len(value) {
+	case 2:
+		return uint32(exif.order.Uint16(value)), true
+	case 4:
+		return exif.order.Uint32(value), true
+	}
+
+	return 0, false
+}
+
+func (exif exif) rational(entries map[uint16][]byte, tag uint16) (uint32, uint32, bool) {
+	value := entries[tag]
+	if len(value) != 8 {
+		return 0, 0, false
+	}
+
+	return exif.order.Uint32(value), exif.order.Uint32(value[4:]), true
+}
+
+func (exif exif) string(entries map[uint16][]byte, tag uint16) string {
+	return strings.TrimSpace(strings.TrimRight(string(entries[tag]), "\x00"))
+}
+
+// typeSize is the size of a value of the TIFF type, or 0 for the unknown
+// ones.
+func typeSize(kind uint16) int {
+	switch kind {
+	case 1, 2, 6, 7:
+		return 1
+	case 3, 8:
+		return 2
+	case 4, 9, 11:
+		return 4
+	case 5, 10, 12:
+		return 8
+	}
+
+	return 0
+}
+
+// exifSegment returns the TIFF structure of the APP1 Exif segment of a JPEG
+// image, or nil.
+func exifSegment(data []byte) []byte {
+	if !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		return nil
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+
+		marker := data[i+1]
+		// the image data starts at the start of scan, no metadata follows
+		if marker == 0xDA || marker == 0xD9 {
+			return nil
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return nil
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) && len(segment) >= 14 {
+			return segment[6:]
+		}
+
+		i += 2 + length
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// animationBudget is how many times the pixels allowed for a still image an
+// animation is decoded into at most, all of its frames together.
+const animationBudget = 4
+
+var (
+	// ErrTooManyPixels is returned for an image decoded into more pixels than
+	// allowed, a small file can hold a huge one.
+	ErrTooManyPixels = errors.New("imaging: the image has too many pixels")
+
+	errMalformedGIF = errors.New("imaging: malformed gif")
+)
+
+// Strip decodes the JPEG, PNG or GIF image and encodes it again, which leaves
+// out every metadata it carried: EXIF with its GPS coordinates and serials,
+// XMP, comments, PNG text chunks... JPEG images are turned upright first as
+// their orientation tag goes with the rest. The width and height are the
+// ones of the image returned. An image of more than maxPixels, or a GIF
+// whose frames would be decoded into more than animationBudget times as
+// many, isn't decoded at all.
+func Strip(data []byte, maxPixels int) (stripped []byte, width int, height int, err error) {
+	var buffer bytes.Buffer
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if config.Width*config.Height > maxPixels {
+		return nil, 0, 0, ErrTooManyPixels
+	}
+
+	switch format {
+	case "gif":
+		// each frame is decoded into a whole screen at most, which the frames
+		// are counted for before
+		frames, err := countFrames(data)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		if frames*config.Width*config.Height > animationBudget*maxPixels {
+			return nil, 0, 0, ErrTooManyPixels
+		}
+
+		// every frame is kept, an animation stays one
+		animation, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		if err = gif.EncodeAll(&buffer, animation); err != nil {
+			return nil, 0, 0, err
+		}
+
+		return buffer.Bytes(), animation.Config.Width, animation.Config.Height, nil
+	case "jpeg", "png":
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		if format == "jpeg" {
+			img = Orient(img, Orientation(data))
+			err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: 90})
+		} else {
+			err = png.Encode(&buffer, img)
+		}
+
+		if err != nil {
+			return nil, 0, 0, err
+		}
+
+		return buffer.Bytes(), img.Bounds().Dx(), img.Bounds().Dy(), nil
+	}
+
+	return nil, 0, 0, image.ErrFormat
+}
+
+// countFrames counts the frames of the GIF image by walking its blocks,
+// without decoding any of them.
+func countFrames(data []byte) (frames int, err error) {
+	// the header and the logical screen descriptor, maybe followed by the
+	// global color table
+	i := 13
+	if len(data) < i {
+		return 0, errMalformedGIF
+	}
+
+	if data[10]&0x80 != 0 {
+		i += 3 << (data[10]&0x07 + 1)
+	}
+
+	for i < len(data) {
+		switch data[i] {
+		case 0x21:
+			// an extension, its label then its sub-blocks
+			i += 2
+		case 0x2C:
+			// an image descriptor, maybe followed by a local color table, then
+			// the minimum code size of its data and the sub-blocks of it
+			if i+10 > len(data) {
+				return 0, errMalformedGIF
+			}
+
+			if data[i+9]&0x80 != 0 {
+				i += 3 << (data[i+9]&0x07 + 1)
+			}
+
+			i += 11
+			frames++
+		case 0x3B:
+			return frames, nil
+		default:
+			return 0, errMalformedGIF
+		}
+
+		// the sub-blocks, each led by its size, end with an empty one
+		for {
+			if i >= len(data) {
+				return 0, errMalformedGIF
+			}
+
+			size := int(data[i])
+			i += size + 1
+
+			if size == 0 {
+				break
+			}
+		}
+	}
+
+	return 0, errMalformedGIF
+}
@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+// fileMailer writes every mail as an .eml file of dir instead of sending it,
+// useful in development to follow links without a mail server.
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir string, from string) *fileMailer {
+	return &fileMailer{dir, from}
+}
+
+func (mailer *fileMailer) Send(ctx context.Context, mail domain.Mail) (err error) {
+	if err = os.MkdirAll(mailer.dir, 0o755); err != nil {
+		return err
+	}
+
+	ID, _ := gonanoid.New(8)
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405"), ID)
+
+	return os.WriteFile(filepath.Join(mailer.dir, name), message(mailer.from, mail), 0o644)
+}
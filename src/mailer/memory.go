@@ -0,0 +1,35 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+// MemoryMailer keeps every sent mail in memory so tests can inspect them.
+type MemoryMailer struct {
+	mu    sync.Mutex
+	mails []domain.Mail
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (mailer *MemoryMailer) Send(ctx context.Context, mail domain.Mail) error {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+
+	mailer.mails = append(mailer.mails, mail)
+
+	return nil
+}
+
+// Outbox returns a copy of every mail sent so far.
+func (mailer *MemoryMailer) Outbox() []domain.Mail {
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+
+	return append([]domain.Mail{}, mailer.mails...)
+}
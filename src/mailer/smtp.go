@@ -0,0 +1,100 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+type smtpMailer struct {
+	host     string
+	port     string
+	username string
+	password string
+	from     string
+}
+
+func NewSMTPMailer(host string, port string, username string, password string, from string) *smtpMailer {
+	return &smtpMailer{host, port, username, password, from}
+}
+
+func (mailer *smtpMailer) Send(ctx context.Context, mail domain.Mail) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var (
+		dialer net.Dialer
+		conn   net.Conn
+		client *smtp.Client
+	)
+
+	if conn, err = dialer.DialContext(ctx, "tcp", net.JoinHostPort(mailer.host, mailer.port)); err != nil {
+		return err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if client, err = smtp.NewClient(conn, mailer.host); err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: mailer.host}); err != nil {
+			return err
+		}
+	}
+
+	if mailer.username != "" {
+		if err = client.Auth(smtp.PlainAuth("", mailer.username, mailer.password, mailer.host)); err != nil {
+			return err
+		}
+	}
+
+	if err = client.Mail(mailer.from); err != nil {
+		return err
+	}
+
+	if err = client.Rcpt(mail.To); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = writer.Write(message(mailer.from, mail)); err != nil {
+		return err
+	}
+
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func message(from string, mail domain.Mail) []byte {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "From: %s\r\n", from)
+	fmt.Fprintf(&builder, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&builder, "Subject: %s\r\n", mail.Subject)
+	fmt.Fprintf(&builder, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n\r\n")
+	builder.WriteString(strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+
+	return []byte(builder.String())
+}
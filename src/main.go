@@ -1,26 +1,78 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gusrylmubarok/mygram-backend/src/config"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/mailer"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/signing"
+	"github.com/gusrylmubarok/mygram-backend/src/storage"
 	"github.com/joho/godotenv"
 
 	docs "github.com/gusrylmubarok/mygram-backend/docs"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
+	apiKeyDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/apikey/delivery/http"
+	apiKeyRepository "github.com/gusrylmubarok/mygram-backend/src/modules/apikey/repository/postgres"
+	apiKeyUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/apikey/usecase"
+	auditLogRepository "github.com/gusrylmubarok/mygram-backend/src/modules/auditlog/repository/postgres"
+	auditLogUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/auditlog/usecase"
+	blockDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/block/delivery/http"
+	blockRepository "github.com/gusrylmubarok/mygram-backend/src/modules/block/repository/postgres"
+	blockUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/block/usecase"
 	commentDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/comment/delivery/http"
 	commentRepository "github.com/gusrylmubarok/mygram-backend/src/modules/comment/repository/postgres"
 	commentUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/comment/usecase"
+	emailVerificationDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/emailverification/delivery/http"
+	emailVerificationUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/emailverification/usecase"
+	feedDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/feed/delivery/http"
+	feedRepository "github.com/gusrylmubarok/mygram-backend/src/modules/feed/repository/postgres"
+	feedUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/feed/usecase"
+	followDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/follow/delivery/http"
+	followRepository "github.com/gusrylmubarok/mygram-backend/src/modules/follow/repository/postgres"
+	followUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/follow/usecase"
+	hashtagDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/hashtag/delivery/http"
+	hashtagRepository "github.com/gusrylmubarok/mygram-backend/src/modules/hashtag/repository/postgres"
+	hashtagUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/hashtag/usecase"
+	likeDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/like/delivery/http"
+	likeRepository "github.com/gusrylmubarok/mygram-backend/src/modules/like/repository/postgres"
+	likeUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/like/usecase"
+	loginAttemptDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/loginattempt/delivery/http"
+	loginAttemptRepository "github.com/gusrylmubarok/mygram-backend/src/modules/loginattempt/repository/postgres"
+	loginAttemptUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/loginattempt/usecase"
+	mentionUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/mention/usecase"
+	mfaDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/mfa/delivery/http"
+	mfaRepository "github.com/gusrylmubarok/mygram-backend/src/modules/mfa/repository/postgres"
+	mfaUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/mfa/usecase"
+	notificationDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/notification/delivery/http"
+	notificationRepository "github.com/gusrylmubarok/mygram-backend/src/modules/notification/repository/postgres"
+	notificationUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/notification/usecase"
+	passwordResetDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/passwordreset/delivery/http"
+	passwordResetRepository "github.com/gusrylmubarok/mygram-backend/src/modules/passwordreset/repository/postgres"
+	passwordResetUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/passwordreset/usecase"
 	photoDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/photo/delivery/http"
 	photoRepository "github.com/gusrylmubarok/mygram-backend/src/modules/photo/repository/postgres"
 	photoUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/photo/usecase"
+	photoWorker "github.com/gusrylmubarok/mygram-backend/src/modules/photo/worker"
+	sessionDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/session/delivery/http"
+	sessionRepository "github.com/gusrylmubarok/mygram-backend/src/modules/session/repository/postgres"
+	sessionUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/session/usecase"
 	socialMediaDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/socialmedia/delivery/http"
 	socialMediaRepository "github.com/gusrylmubarok/mygram-backend/src/modules/socialmedia/repository/postgres"
 	socialMediaUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/socialmedia/usecase"
+	tokenDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/token/delivery/http"
+	tokenMemoryRepository "github.com/gusrylmubarok/mygram-backend/src/modules/token/repository/memory"
+	tokenRepository "github.com/gusrylmubarok/mygram-backend/src/modules/token/repository/postgres"
+	tokenUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/token/usecase"
 	userDelivery "github.com/gusrylmubarok/mygram-backend/src/modules/user/delivery/http"
 	userRepository "github.com/gusrylmubarok/mygram-backend/src/modules/user/repository/postgres"
 	userUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/user/usecase"
@@ -42,6 +94,11 @@ import (
 // @name                        Authorization
 // @description					Description for what is this security definition being used
 
+// @securityDefinitions.apikey  ApiKey
+// @in                          header
+// @name                        Authorization
+// @description					A personal api key as "ApiKey <key>", only for the endpoints of its scopes
+
 func main() {
 	if err := godotenv.Load(); err != nil {
 		log.Fatal("Error loading .env file: ", err)
@@ -66,17 +123,162 @@ func main() {
 	})
 	routers.Static("/public", "./public")
 
+	passwordConfig := config.LoadPasswordConfig()
+	helpers.SetPasswordHasher(helpers.NewArgon2idHasher(passwordConfig.Argon2id))
+
+	tokenConfig := config.LoadTokenConfig()
+
+	keyRing, err := signing.LoadKeyRing(tokenConfig.KeysDir, tokenConfig.SigningKeyID)
+	if err != nil {
+		log.Fatal("Error loading signing keys: ", err)
+	}
+	tokenSigner := signing.NewSigner(keyRing, tokenConfig.Issuer, tokenConfig.Audience)
+	middleware.SetTokenSigner(tokenSigner)
+	tokenDelivery.NewJWKSHandler(routers, keyRing)
+
+	// keys are rotated by changing the files of JWT_KEYS_DIR and sending SIGHUP
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			if err := keyRing.Reload(tokenConfig.KeysDir, tokenConfig.SigningKeyID); err != nil {
+				log.Println("Error reloading signing keys: ", err)
+				continue
+			}
+			log.Println("Signing keys reloaded")
+		}
+	}()
+
+	var revocationStore domain.RevocationStore = tokenRepository.NewRevocationStore(db)
+	if tokenConfig.RevocationStore == "memory" {
+		revocationStore = tokenMemoryRepository.NewRevocationStore()
+	}
+	middleware.SetRevocationStore(revocationStore)
+
+	refreshTokenRepository := tokenRepository.NewRefreshTokenRepository(db)
+	sessionRepository := sessionRepository.NewSessionRepository(db)
+	tokenUseCase := tokenUseCase.NewTokenUseCase(refreshTokenRepository, sessionRepository, revocationStore, tokenSigner, tokenConfig.AccessTokenTTL, tokenConfig.RefreshTokenTTL)
+	sessionUseCase := sessionUseCase.NewSessionUseCase(sessionRepository, refreshTokenRepository, revocationStore, tokenConfig.AccessTokenTTL)
+	sessionDelivery.NewSessionHandler(routers, sessionUseCase)
+
+	appConfig := config.LoadAppConfig()
+	mailConfig := config.LoadMailConfig()
+	loginConfig := config.LoadLoginConfig()
+
+	var mail domain.Mailer
+	switch mailConfig.Driver {
+	case "smtp":
+		mail = mailer.NewSMTPMailer(mailConfig.Host, mailConfig.Port, mailConfig.Username, mailConfig.Password, mailConfig.From)
+	case "memory":
+		mail = mailer.NewMemoryMailer()
+	default:
+		mail = mailer.NewFileMailer(mailConfig.Dir, mailConfig.From)
+	}
+
 	userRepository := userRepository.NewUserRepository(db)
 	userUseCase := userUseCase.NewUserUseCase(userRepository)
-	userDelivery.NewUserHandler(routers, userUseCase)
+
+	// the users of ADMIN_USER_IDS are made admins on start, that's how the first admin is created
+	// and like a role changed by an admin, their tokens are revoked to carry the new one
+	for _, id := range appConfig.AdminUserIDs {
+		user, err := userUseCase.FindById(context.Background(), id)
+
+		if err != nil {
+			log.Println("Error granting the admin role: ", err)
+			continue
+		}
+
+		if user.Role == domain.RoleAdmin {
+			continue
+		}
+
+		if _, err := userUseCase.UpdateRole(context.Background(), id, domain.RoleAdmin); err != nil {
+			log.Println("Error granting the admin role: ", err)
+			continue
+		}
+
+		if err := tokenUseCase.RevokeAll(context.Background(), id); err != nil {
+			log.Println("Error revoking the tokens of a new admin: ", err)
+		}
+	}
+
+	auditLogRepository := auditLogRepository.NewAuditLogRepository(db)
+	middleware.SetAuditLog(auditLogUseCase.NewAuditLogUseCase(auditLogRepository))
+
+	apiKeyRepository := apiKeyRepository.NewAPIKeyRepository(db)
+	apiKeyUseCase := apiKeyUseCase.NewAPIKeyUseCase(apiKeyRepository)
+	middleware.SetAPIKeys(apiKeyUseCase)
+	apiKeyDelivery.NewAPIKeyHandler(routers, apiKeyUseCase)
+
+	emailVerificationUseCase := emailVerificationUseCase.NewEmailVerificationUseCase(userRepository, mail, tokenSigner, appConfig.EmailVerificationURL, tokenConfig.EmailVerificationTTL)
+	mfaRepository := mfaRepository.NewMFARepository(db)
+	loginThrottleRepository := loginAttemptRepository.NewLoginThrottleRepository(db)
+	mfaUseCase := mfaUseCase.NewMFAUseCase(mfaRepository, userRepository, loginThrottleRepository, revocationStore, tokenSigner, appConfig.Name, tokenConfig.MFAChallengeTTL, tokenConfig.MFAMaxAttempts, tokenConfig.MFALockDuration)
+	loginAttemptUseCase := loginAttemptUseCase.NewLoginAttemptUseCase(loginThrottleRepository, loginConfig.Account, loginConfig.IP)
+	userDelivery.NewUserHandler(routers, userUseCase, tokenUseCase, emailVerificationUseCase, mfaUseCase, loginAttemptUseCase, apiKeyUseCase)
+	loginAttemptDelivery.NewLoginAttemptHandler(routers, loginAttemptUseCase, userUseCase)
+	mfaDelivery.NewMFAHandler(routers, mfaUseCase, tokenUseCase, loginAttemptUseCase)
+	emailVerificationDelivery.NewEmailVerificationHandler(routers, emailVerificationUseCase)
+
+	feedConfig := config.LoadFeedConfig()
+
+	var feedStrategy domain.FeedStrategy = feedRepository.NewFanOutOnRead(db)
+	if feedConfig.Strategy == "write" {
+		feedStrategy = feedRepository.NewFanOutOnWrite(db, feedConfig.BackfillLimit)
+	}
+
+	followRepository := followRepository.NewFollowRepository(db)
+	followUseCase := followUseCase.NewFollowUseCase(followRepository, userRepository, feedStrategy)
+	followDelivery.NewFollowHandler(routers, followUseCase)
+
+	blockRepository := blockRepository.NewBlockRepository(db)
+	blockDelivery.NewBlockHandler(routers, blockUseCase.NewBlockUseCase(blockRepository, userRepository))
+
+	notificationRepository := notificationRepository.NewNotificationRepository(db)
+	notificationDelivery.NewNotificationHandler(routers, notificationUseCase.NewNotificationUseCase(notificationRepository))
+
+	// the mentions of blocked users are dropped and the others notified
+	mentionUseCase := mentionUseCase.NewMentionUseCase(userRepository, blockRepository, notificationRepository)
+
+	passwordResetRepository := passwordResetRepository.NewPasswordResetRepository(db)
+	passwordResetUseCase := passwordResetUseCase.NewPasswordResetUseCase(passwordResetRepository, userRepository, tokenUseCase, apiKeyUseCase, mail, appConfig.PasswordResetURL, tokenConfig.PasswordResetTTL)
+	passwordResetDelivery.NewPasswordResetHandler(routers, passwordResetUseCase)
+
+	storageConfig := config.LoadStorageConfig()
+
+	var blobStore domain.BlobStore
+	switch storageConfig.Driver {
+	case "s3":
+		blobStore = storage.NewS3Store(storageConfig.S3Endpoint, storageConfig.S3Region, storageConfig.S3Bucket, storageConfig.S3AccessKey, storageConfig.S3SecretKey, storageConfig.S3PublicURL)
+	case "memory":
+		blobStore = storage.NewMemoryStore()
+	default:
+		blobStore = storage.NewLocalStore(storageConfig.Dir, storageConfig.PublicURL)
+	}
+
+	photoConfig := config.LoadPhotoConfig()
 
 	photoRepository := photoRepository.NewPhotoRepository(db)
-	photoUseCase := photoUseCase.NewPhotoUseCase(photoRepository)
-	photoDelivery.NewPhotoHandler(routers, photoUseCase)
+	photoWorker := photoWorker.NewVariantWorker(photoRepository, blobStore, photoConfig.VariantWidths, photoConfig.QueueSize)
+	photoWorker.Start(context.Background(), photoConfig.Workers)
+	photoUseCase := photoUseCase.NewPhotoUseCase(photoRepository, feedStrategy, blobStore, photoWorker, mentionUseCase, storageConfig.MaxUploadBytes)
+
+	likeRepository := likeRepository.NewLikeRepository(db)
+	likeUseCase := likeUseCase.NewLikeUseCase(likeRepository, photoRepository)
+	likeDelivery.NewLikeHandler(routers, likeUseCase)
+
+	photoDelivery.NewPhotoHandler(routers, photoUseCase, userUseCase, likeUseCase, storageConfig.MaxUploadBytes)
+	feedDelivery.NewFeedHandler(routers, feedUseCase.NewFeedUseCase(feedStrategy), likeUseCase)
+
+	hashtagRepository := hashtagRepository.NewHashtagRepository(db)
+	hashtagUseCase := hashtagUseCase.NewHashtagUseCase(hashtagRepository)
+	hashtagDelivery.NewHashtagHandler(routers, hashtagUseCase, likeUseCase)
+
+	commentConfig := config.LoadCommentConfig()
 
 	commentRepository := commentRepository.NewCommentRepository(db)
-	commentUseCase := commentUseCase.NewCommentUseCase(commentRepository)
-	commentDelivery.NewCommentHandler(routers, commentUseCase, photoUseCase)
+	commentUseCase := commentUseCase.NewCommentUseCase(commentRepository, photoRepository, followRepository, mentionUseCase, commentConfig.MaxDepth, commentConfig.InlineReplies)
+	commentDelivery.NewCommentHandler(routers, commentUseCase, photoUseCase, userUseCase)
 
 	socialMediaRepository := socialMediaRepository.NewSocialMediaRepository(db)
 	socialMediaUseCase := socialMediaUseCase.NewSocialMediaUseCase(socialMediaRepository)
@@ -2,14 +2,54 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	"github.com/gusrylmubarok/mygram-backend/src/helpers"
 )
 
+var (
+	tokenSigner     domain.TokenSigner
+	revocationStore domain.RevocationStore
+	apiKeyUseCase   domain.APIKeyUseCase
+)
+
+// SetTokenSigner sets the signer Authentication verifies access tokens with.
+func SetTokenSigner(signer domain.TokenSigner) {
+	tokenSigner = signer
+}
+
+// SetRevocationStore sets the store Authentication checks every token against.
+func SetRevocationStore(store domain.RevocationStore) {
+	revocationStore = store
+}
+
+// SetAPIKeys sets the use case Authentication checks API keys with, without
+// it only access tokens are accepted.
+func SetAPIKeys(useCase domain.APIKeyUseCase) {
+	apiKeyUseCase = useCase
+}
+
+// Authentication accepts an access token as "Authorization: Bearer <token>"
+// or an API key as "Authorization: ApiKey <key>" and puts the principal in
+// the context of the request, read it with CurrentPrincipal.
 func Authentication() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		verifyToken, err := VerifyToken(ctx)
+		var (
+			principal domain.Principal
+			err       = domain.ErrUnauthenticated
+		)
+
+		header := ctx.Request.Header.Get("Authorization")
+
+		switch {
+		case strings.HasPrefix(header, "Bearer "):
+			principal, err = authenticateAccessToken(ctx, strings.TrimPrefix(header, "Bearer "))
+		case strings.HasPrefix(header, "ApiKey "):
+			principal, err = authenticateAPIKey(ctx, strings.TrimPrefix(header, "ApiKey "))
+		}
 
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
@@ -20,7 +60,134 @@ func Authentication() gin.HandlerFunc {
 			return
 		}
 
-		ctx.Set("userData", verifyToken)
+		ctx.Request = ctx.Request.WithContext(domain.ContextWithPrincipal(ctx.Request.Context(), principal))
 		ctx.Next()
 	}
 }
+
+// CurrentPrincipal returns the principal Authentication put in the context
+// of the request, the zero Principal when there is none.
+func CurrentPrincipal(ctx *gin.Context) domain.Principal {
+	principal, _ := domain.PrincipalFromContext(ctx.Request.Context())
+
+	return principal
+}
+
+func authenticateAccessToken(ctx *gin.Context, token string) (principal domain.Principal, err error) {
+	var claims domain.TokenClaims
+
+	if tokenSigner == nil {
+		return principal, domain.ErrUnauthenticated
+	}
+
+	if claims, err = tokenSigner.Parse(domain.TokenTypeAccess, token); err != nil {
+		return principal, domain.ErrUnauthenticated
+	}
+
+	userID, _ := claims["id"].(string)
+	email, _ := claims["email"].(string)
+	role, _ := claims["role"].(string)
+	jti, _ := claims["jti"].(string)
+	sid, _ := claims["sid"].(string)
+	exp, _ := claims["exp"].(float64)
+
+	if userID == "" {
+		return principal, domain.ErrUnauthenticated
+	}
+
+	if err = checkRevocation(ctx, userID, claims); err != nil {
+		return principal, err
+	}
+
+	// tokens issued before roles existed carry none
+	if role == "" {
+		role = domain.RoleUser
+	}
+
+	return domain.Principal{
+		UserID:     userID,
+		Email:      email,
+		Roles:      []string{role},
+		TokenID:    jti,
+		SessionID:  sid,
+		AuthMethod: domain.AuthMethodAccessToken,
+		ExpiresAt:  time.Unix(int64(exp), 0),
+	}, nil
+}
+
+func authenticateAPIKey(ctx *gin.Context, key string) (principal domain.Principal, err error) {
+	var apiKey domain.APIKey
+
+	if apiKeyUseCase == nil {
+		return principal, domain.ErrUnauthenticated
+	}
+
+	if apiKey, err = apiKeyUseCase.Authenticate(ctx.Request.Context(), key); err != nil {
+		return principal, err
+	}
+
+	// an api key never carries the role of a moderator or an admin, a leaked
+	// one must not be able to change the content of others
+	principal = domain.Principal{
+		UserID:     apiKey.UserID,
+		Email:      apiKey.User.Email,
+		Roles:      []string{domain.RoleUser},
+		Scopes:     apiKey.ScopeList(),
+		TokenID:    apiKey.ID,
+		AuthMethod: domain.AuthMethodAPIKey,
+	}
+
+	if apiKey.ExpiresAt != nil {
+		principal.ExpiresAt = *apiKey.ExpiresAt
+	}
+
+	return principal, nil
+}
+
+func checkRevocation(ctx *gin.Context, userID string, claims domain.TokenClaims) (err error) {
+	if revocationStore == nil {
+		return
+	}
+
+	var (
+		revoked bool
+		current uint
+	)
+
+	jti, _ := claims["jti"].(string)
+	sid, _ := claims["sid"].(string)
+	version, _ := claims["ver"].(float64)
+
+	if revoked, err = revocationStore.IsRevoked(ctx.Request.Context(), jti); err != nil {
+		return err
+	}
+
+	// a revoked session is revoked in the store under its id
+	if !revoked && sid != "" {
+		if revoked, err = revocationStore.IsRevoked(ctx.Request.Context(), sid); err != nil {
+			return err
+		}
+	}
+
+	if revoked {
+		return domain.ErrTokenRevoked
+	}
+
+	if current, err = revocationStore.TokenVersion(ctx.Request.Context(), userID); err != nil {
+		return err
+	}
+
+	if uint(version) < current {
+		return domain.ErrTokenRevoked
+	}
+
+	return
+}
+
+// Client returns the device the request was made from, to start a session on.
+func Client(ctx *gin.Context) domain.SessionClient {
+	return domain.SessionClient{
+		UserAgent: ctx.Request.UserAgent(),
+		IP:        ctx.ClientIP(),
+	}
+}
@@ -5,11 +5,54 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	"github.com/gusrylmubarok/mygram-backend/src/helpers"
 )
 
+// RequireRole only lets users with one of the roles through.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if CurrentPrincipal(ctx).HasRole(roles...) {
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+			Status:  "unauthorized",
+			Message: "you don't have permission to access this resource",
+		})
+	}
+}
+
+// RequireScope only lets requests made with an API key through when the key
+// was granted the scope, requests made with an access token always pass.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if CurrentPrincipal(ctx).HasScope(scope) {
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+			Status:  "unauthorized",
+			Message: fmt.Sprintf("the api key hasn't been granted the %s scope", scope),
+		})
+	}
+}
+
+// SessionOnly only lets requests made with an access token through, an API
+// key can't manage the account it belongs to.
+func SessionOnly() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if CurrentPrincipal(ctx).AuthMethod != domain.AuthMethodAccessToken {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "unauthorized",
+				Message: "sign in with a password to access this resource",
+			})
+
+			return
+		}
+	}
+}
+
 func AuthorizationSocialMedia(socialMediaUseCase domain.SocialMediaUseCase) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		var (
@@ -18,8 +61,6 @@ func AuthorizationSocialMedia(socialMediaUseCase domain.SocialMediaUseCase) gin.
 		)
 
 		socialMediaID := ctx.Param("socialMediaId")
-		userData := ctx.MustGet("userData").(jwt.MapClaims)
-		userID := string(userData["id"].(string))
 
 		if err = socialMediaUseCase.FindById(ctx.Request.Context(), &socialMedia, socialMediaID); err != nil {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
@@ -30,7 +71,7 @@ func AuthorizationSocialMedia(socialMediaUseCase domain.SocialMediaUseCase) gin.
 			return
 		}
 
-		if socialMedia.UserID != userID {
+		if !authorizeOwner(ctx, "socialmedia", socialMediaID, socialMedia.UserID) {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
 				Status:  "unauthorized",
 				Message: "you don't have permission to view or edit this social media",
@@ -49,8 +90,6 @@ func AuthorizationPhoto(photoUseCase domain.PhotoUseCase) gin.HandlerFunc {
 		)
 
 		photoID := ctx.Param("photoId")
-		userData := ctx.MustGet("userData").(jwt.MapClaims)
-		userID := string(userData["id"].(string))
 
 		if err = photoUseCase.FindById(ctx.Request.Context(), &photo, photoID); err != nil {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
@@ -61,7 +100,7 @@ func AuthorizationPhoto(photoUseCase domain.PhotoUseCase) gin.HandlerFunc {
 			return
 		}
 
-		if photo.UserID != userID {
+		if !authorizeOwner(ctx, "photo", photoID, photo.UserID) {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
 				Status:  "unauthorized",
 				Message: "you don't have permission to view or edit this photo",
@@ -80,8 +119,6 @@ func AuthorizationComment(commentUseCase domain.CommentUseCase) gin.HandlerFunc
 		)
 
 		commentID := ctx.Param("commentId")
-		userData := ctx.MustGet("userData").(jwt.MapClaims)
-		userID := string(userData["id"].(string))
 
 		if err = commentUseCase.FindById(ctx.Request.Context(), &comment, commentID); err != nil {
 			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
@@ -92,7 +129,7 @@ func AuthorizationComment(commentUseCase domain.CommentUseCase) gin.HandlerFunc
 			return
 		}
 
-		if comment.UserID != userID {
+		if !authorizeOwner(ctx, "comment", commentID, comment.UserID) {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
 				Status:  "unauthorized",
 				Message: "you don't have permission to view or edit this comment",
@@ -102,3 +139,72 @@ func AuthorizationComment(commentUseCase domain.CommentUseCase) gin.HandlerFunc
 		}
 	}
 }
+
+// AuthorizationCommentModeration lets the owner of the photo the comment is
+// on through, and the author of the comment when allowAuthor.
+func AuthorizationCommentModeration(commentUseCase domain.CommentUseCase, allowAuthor bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var (
+			comment domain.Comment
+			err     error
+		)
+
+		commentID := ctx.Param("commentId")
+
+		if err = commentUseCase.FindById(ctx.Request.Context(), &comment, commentID); err != nil || comment.ID == "" || comment.Photo == nil {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("comment with id %s doesn't exist", commentID),
+			})
+
+			return
+		}
+
+		if comment.Photo.UserID == CurrentPrincipal(ctx).UserID {
+			return
+		}
+
+		// past the owner of the photo, the author if allowed and the moderators
+		ownerID := comment.Photo.UserID
+		if allowAuthor {
+			ownerID = comment.UserID
+		}
+
+		if !authorizeOwner(ctx, "comment", commentID, ownerID) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthorized",
+				Message: "you don't have permission to moderate this comment",
+			})
+
+			return
+		}
+	}
+}
+
+// VerifiedEmail only lets users who confirmed their email address through.
+func VerifiedEmail(userUseCase domain.UserUseCase) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var (
+			user domain.User
+			err  error
+		)
+
+		if user, err = userUseCase.FindById(ctx.Request.Context(), CurrentPrincipal(ctx).UserID); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: "sign in to proceed",
+			})
+
+			return
+		}
+
+		if user.EmailVerifiedAt == nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "unverified",
+				Message: domain.ErrEmailNotVerified.Error(),
+			})
+
+			return
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+var auditLogUseCase domain.AuditLogUseCase
+
+// SetAuditLog sets where the overrides of moderators and admins are recorded.
+func SetAuditLog(useCase domain.AuditLogUseCase) {
+	auditLogUseCase = useCase
+}
+
+// authorizeOwner reports whether the principal may edit or delete a resource
+// of ownerID. Owners always may, moderators and admins may act on the
+// resources of others, which is recorded once the handler succeeded.
+func authorizeOwner(ctx *gin.Context, resourceType string, resourceID string, ownerID string) bool {
+	principal := CurrentPrincipal(ctx)
+
+	if ownerID == principal.UserID {
+		return true
+	}
+
+	if !principal.HasRole(domain.RoleModerator, domain.RoleAdmin) {
+		return false
+	}
+
+	ctx.Next()
+
+	if ctx.IsAborted() || ctx.Writer.Status() >= http.StatusBadRequest || auditLogUseCase == nil {
+		return true
+	}
+
+	action := strings.ToLower(ctx.Request.Method)
+
+	switch ctx.Request.Method {
+	case http.MethodPut:
+		action = "update"
+	case http.MethodDelete:
+		action = "delete"
+	}
+
+	if err := auditLogUseCase.Record(ctx.Request.Context(), &domain.AuditLog{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		OwnerID:      ownerID,
+	}); err != nil {
+		log.Println("Error recording audit log: ", err)
+	}
+
+	return true
+}
@@ -0,0 +1,172 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"gorm.io/gorm"
+)
+
+type apiKeyHandler struct {
+	apiKeyUseCase domain.APIKeyUseCase
+}
+
+func NewAPIKeyHandler(routers *gin.Engine, apiKeyUseCase domain.APIKeyUseCase) *apiKeyHandler {
+	handler := &apiKeyHandler{apiKeyUseCase}
+
+	router := routers.Group("/api/v1/user/apikey")
+	{
+		router.Use(middleware.Authentication(), middleware.SessionOnly())
+		router.POST("", handler.Create)
+		router.GET("", handler.GetAll)
+		router.DELETE("/:apiKeyId", handler.Revoke)
+	}
+
+	return handler
+}
+
+// Create godoc
+// @Summary			Create an api key
+// @Description		Create a personal api key with the given scopes, used as "Authorization: ApiKey <key>". The key is only shown in this response
+// @Tags			apikey
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.CreateAPIKey	true	"Create API Key"
+// @Success			201		{object}		domain.CreatedAPIKey
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/apikey		[post]
+func (handler *apiKeyHandler) Create(ctx *gin.Context) {
+	var (
+		input  domain.CreateAPIKey
+		apiKey domain.APIKey
+		key    string
+		err    error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if key, apiKey, err = handler.apiKeyUseCase.Create(ctx.Request.Context(), userID, input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, domain.CreatedAPIKey{
+		Status:  "success",
+		Message: "the api key has been created, store it now as it won't be shown again",
+		Data: domain.CreatedDataAPIKey{
+			GetAPIKey: toGetAPIKey(apiKey),
+			Key:       key,
+		},
+	})
+}
+
+// GetAll godoc
+// @Summary			Get all api keys
+// @Description		Get every api key of the authentication user, including revoked and expired ones
+// @Tags			apikey
+// @Produce			json
+// @Success			200		{object}		domain.FetchedAPIKeys
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/apikey		[get]
+func (handler *apiKeyHandler) GetAll(ctx *gin.Context) {
+	var (
+		apiKeys []domain.APIKey
+		err     error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = handler.apiKeyUseCase.FindAllByUser(ctx.Request.Context(), &apiKeys, userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fetchedAPIKeys := make([]domain.GetAPIKey, 0, len(apiKeys))
+
+	for _, apiKey := range apiKeys {
+		fetchedAPIKeys = append(fetchedAPIKeys, toGetAPIKey(apiKey))
+	}
+
+	ctx.JSON(http.StatusOK, domain.FetchedAPIKeys{
+		Status:  "success",
+		Message: "api keys have been fetched",
+		Data:    fetchedAPIKeys,
+	})
+}
+
+// Revoke godoc
+// @Summary			Revoke an api key
+// @Description		Revoke an api key of the authentication user, it can't be used anymore
+// @Tags			apikey
+// @Produce			json
+// @Param			apiKeyId	path		string	true	"API Key ID"
+// @Success			200		{object}		domain.RevokedAPIKey
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Failure			404		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/apikey/{apiKeyId}		[delete]
+func (handler *apiKeyHandler) Revoke(ctx *gin.Context) {
+	userID := middleware.CurrentPrincipal(ctx).UserID
+	apiKeyID := ctx.Param("apiKeyId")
+
+	if err := handler.apiKeyUseCase.Revoke(ctx.Request.Context(), apiKeyID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("api key with id %s doesn't exist or is already revoked", apiKeyID),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.RevokedAPIKey{
+		Status:  "success",
+		Message: "the api key has been revoked",
+	})
+}
+
+func toGetAPIKey(apiKey domain.APIKey) domain.GetAPIKey {
+	return domain.GetAPIKey{
+		ID:         apiKey.ID,
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     apiKey.ScopeList(),
+		LastUsedAt: apiKey.LastUsedAt,
+		ExpiresAt:  apiKey.ExpiresAt,
+		RevokedAt:  apiKey.RevokedAt,
+		CreatedAt:  apiKey.CreatedAt,
+	}
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *apiKeyRepository {
+	return &apiKeyRepository{db}
+}
+
+func (apiKeyRepository *apiKeyRepository) Save(ctx context.Context, apiKey *domain.APIKey) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	apiKey.ID = fmt.Sprintf("apikey-%s", ID)
+
+	if err = apiKeyRepository.db.WithContext(ctx).Create(&apiKey).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) FindAllByUser(ctx context.Context, apiKeys *[]domain.APIKey, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&apiKeys).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// FindByHash finds the key with its user, whose role and email are needed
+// to authenticate requests made with the key.
+func (apiKeyRepository *apiKeyRepository) FindByHash(ctx context.Context, apiKey *domain.APIKey, keyHash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email", "role")
+	}).First(&apiKey, "key_hash = ?", keyHash).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) Touch(ctx context.Context, id string, usedAt time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Model(&domain.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Revoke revokes the key when it belongs to the user and isn't revoked yet.
+func (apiKeyRepository *apiKeyRepository) Revoke(ctx context.Context, id string, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := apiKeyRepository.db.WithContext(ctx).Model(&domain.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		UpdateColumn("revoked_at", time.Now())
+
+	if err = result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+func (apiKeyRepository *apiKeyRepository) RevokeAllByUser(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = apiKeyRepository.db.WithContext(ctx).Model(&domain.APIKey{}).Where("user_id = ? AND revoked_at IS NULL", userID).UpdateColumn("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
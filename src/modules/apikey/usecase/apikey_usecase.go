@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+)
+
+const (
+	apiKeyPrefix       = "mgk_"
+	apiKeyPrefixLength = 12
+
+	// last used is only written this often, not on every request of the key
+	touchInterval = time.Minute
+)
+
+type apiKeyUseCase struct {
+	apiKeyRepository domain.APIKeyRepository
+}
+
+func NewAPIKeyUseCase(apiKeyRepository domain.APIKeyRepository) *apiKeyUseCase {
+	return &apiKeyUseCase{apiKeyRepository}
+}
+
+// Create stores a new key of the user and returns it, the key can't be
+// recovered afterwards.
+func (apiKeyUseCase *apiKeyUseCase) Create(ctx context.Context, userID string, input domain.CreateAPIKey) (key string, apiKey domain.APIKey, err error) {
+	var token string
+
+	if _, err = govalidator.ValidateStruct(input); err != nil {
+		return key, apiKey, err
+	}
+
+	if len(input.Scopes) == 0 {
+		return key, apiKey, domain.ErrInvalidAPIKeyScope
+	}
+
+	scopes := make([]string, 0, len(input.Scopes))
+	granted := map[string]bool{}
+
+	for _, scope := range input.Scopes {
+		if !domain.IsValidScope(scope) {
+			return key, apiKey, domain.ErrInvalidAPIKeyScope
+		}
+
+		if !granted[scope] {
+			granted[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return key, apiKey, errors.New("the expiry of the api key must be in the future")
+	}
+
+	if token, err = helpers.GenerateSecureToken(); err != nil {
+		return key, apiKey, err
+	}
+
+	key = apiKeyPrefix + token
+	apiKey = domain.APIKey{
+		UserID:    userID,
+		Name:      input.Name,
+		Prefix:    key[:apiKeyPrefixLength],
+		KeyHash:   helpers.HashToken(key),
+		Scopes:    strings.Join(scopes, " "),
+		ExpiresAt: input.ExpiresAt,
+	}
+
+	if err = apiKeyUseCase.apiKeyRepository.Save(ctx, &apiKey); err != nil {
+		return "", apiKey, err
+	}
+
+	return key, apiKey, nil
+}
+
+func (apiKeyUseCase *apiKeyUseCase) FindAllByUser(ctx context.Context, apiKeys *[]domain.APIKey, userID string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.FindAllByUser(ctx, apiKeys, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (apiKeyUseCase *apiKeyUseCase) Revoke(ctx context.Context, id string, userID string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.Revoke(ctx, id, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+// RevokeAllByUser revokes every key of the user, the keys act for the user so
+// they go along with the sessions when the password changes.
+func (apiKeyUseCase *apiKeyUseCase) RevokeAllByUser(ctx context.Context, userID string) (err error) {
+	if err = apiKeyUseCase.apiKeyRepository.RevokeAllByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Authenticate returns the key with its user when the key is neither revoked
+// nor expired, and records that it was used.
+func (apiKeyUseCase *apiKeyUseCase) Authenticate(ctx context.Context, key string) (apiKey domain.APIKey, err error) {
+	now := time.Now()
+
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return apiKey, domain.ErrInvalidAPIKey
+	}
+
+	if err = apiKeyUseCase.apiKeyRepository.FindByHash(ctx, &apiKey, helpers.HashToken(key)); err != nil {
+		return apiKey, domain.ErrInvalidAPIKey
+	}
+
+	if apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && !now.Before(*apiKey.ExpiresAt)) || apiKey.User == nil {
+		return apiKey, domain.ErrInvalidAPIKey
+	}
+
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= touchInterval {
+		if err = apiKeyUseCase.apiKeyRepository.Touch(ctx, apiKey.ID, now); err != nil {
+			return apiKey, err
+		}
+
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type auditLogRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) *auditLogRepository {
+	return &auditLogRepository{db}
+}
+
+func (auditLogRepository *auditLogRepository) Save(ctx context.Context, auditLog *domain.AuditLog) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	auditLog.ID = fmt.Sprintf("auditlog-%s", ID)
+
+	if err = auditLogRepository.db.WithContext(ctx).Create(&auditLog).Error; err != nil {
+		return err
+	}
+
+	return
+}
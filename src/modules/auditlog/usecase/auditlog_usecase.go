@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+type auditLogUseCase struct {
+	auditLogRepository domain.AuditLogRepository
+}
+
+func NewAuditLogUseCase(auditLogRepository domain.AuditLogRepository) *auditLogUseCase {
+	return &auditLogUseCase{auditLogRepository}
+}
+
+// Record stores the audit log with the principal of the request as the actor.
+func (auditLogUseCase *auditLogUseCase) Record(ctx context.Context, auditLog *domain.AuditLog) (err error) {
+	principal, ok := domain.PrincipalFromContext(ctx)
+
+	if !ok {
+		return domain.ErrUnauthenticated
+	}
+
+	auditLog.ActorID = principal.UserID
+	auditLog.ActorRole = strings.Join(principal.Roles, ",")
+
+	if err = auditLogUseCase.auditLogRepository.Save(ctx, auditLog); err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,107 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"gorm.io/gorm"
+)
+
+type blockHandler struct {
+	blockUseCase domain.BlockUseCase
+}
+
+func NewBlockHandler(routers *gin.Engine, blockUseCase domain.BlockUseCase) *blockHandler {
+	handler := &blockHandler{blockUseCase}
+
+	router := routers.Group("/api/v1/users/:username")
+	{
+		router.POST("/block", middleware.Authentication(), middleware.SessionOnly(), handler.Block)
+		router.DELETE("/block", middleware.Authentication(), middleware.SessionOnly(), handler.Unblock)
+	}
+
+	return handler
+}
+
+// Block godoc
+// @Summary			Block a user
+// @Description		Make the authentication user block the user with the username, neither of them can mention the other then
+// @Tags			block
+// @Produce			json
+// @Param			username	path			string	true	"Username"
+// @Success			201			{object}		domain.Blocked
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Failure			409			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/users/{username}/block		[post]
+func (handler *blockHandler) Block(ctx *gin.Context) {
+	username := ctx.Param("username")
+
+	if _, err := handler.blockUseCase.Block(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, username); err != nil {
+		handler.abort(ctx, username, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, domain.Blocked{
+		Status:  "success",
+		Message: fmt.Sprintf("you now block %s", username),
+	})
+}
+
+// Unblock godoc
+// @Summary			Unblock a user
+// @Description		Make the authentication user stop blocking the user with the username
+// @Tags			block
+// @Produce			json
+// @Param			username	path			string	true	"Username"
+// @Success			200			{object}		domain.Unblocked
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/users/{username}/block		[delete]
+func (handler *blockHandler) Unblock(ctx *gin.Context) {
+	username := ctx.Param("username")
+
+	if err := handler.blockUseCase.Unblock(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, username); err != nil {
+		handler.abort(ctx, username, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.Unblocked{
+		Status:  "success",
+		Message: fmt.Sprintf("you no longer block %s", username),
+	})
+}
+
+func (handler *blockHandler) abort(ctx *gin.Context, username string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: fmt.Sprintf("user with username %s doesn't exist", username),
+		})
+	case errors.Is(err, domain.ErrNotBlocked):
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrAlreadyBlocked):
+		ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+	default:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+	}
+}
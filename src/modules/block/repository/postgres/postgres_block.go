@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type blockRepository struct {
+	db *gorm.DB
+}
+
+func NewBlockRepository(db *gorm.DB) *blockRepository {
+	return &blockRepository{db}
+}
+
+func (blockRepository *blockRepository) Save(ctx context.Context, block *domain.Block) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	block.ID = fmt.Sprintf("block-%s", ID)
+
+	if err = blockRepository.db.WithContext(ctx).Create(&block).Error; err != nil {
+		if strings.Contains(err.Error(), "idx_blocks_blocker_blocked") {
+			return domain.ErrAlreadyBlocked
+		}
+		return err
+	}
+
+	return
+}
+
+// Delete removes the block of blockerID on blockedID.
+func (blockRepository *blockRepository) Delete(ctx context.Context, blockerID string, blockedID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := blockRepository.db.WithContext(ctx).Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&domain.Block{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrNotBlocked
+	}
+
+	return
+}
+
+// FindBlockedUserIDs tells which of the users block the user or are blocked
+// by them, in a single query.
+func (blockRepository *blockRepository) FindBlockedUserIDs(ctx context.Context, userID string, userIDs []string) (blocked map[string]bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	blocked = make(map[string]bool, len(userIDs))
+
+	if len(userIDs) == 0 {
+		return blocked, nil
+	}
+
+	var blocks []domain.Block
+
+	if err = blockRepository.db.WithContext(ctx).Where("blocker_id = ? AND blocked_id IN ?", userID, userIDs).Or("blocked_id = ? AND blocker_id IN ?", userID, userIDs).Find(&blocks).Error; err != nil {
+		return blocked, err
+	}
+
+	for _, block := range blocks {
+		if block.BlockerID == userID {
+			blocked[block.BlockedID] = true
+		} else {
+			blocked[block.BlockerID] = true
+		}
+	}
+
+	return blocked, nil
+}
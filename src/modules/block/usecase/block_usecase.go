@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+type blockUseCase struct {
+	blockRepository domain.BlockRepository
+	userRepository  domain.UserRepository
+}
+
+func NewBlockUseCase(blockRepository domain.BlockRepository, userRepository domain.UserRepository) *blockUseCase {
+	return &blockUseCase{blockRepository, userRepository}
+}
+
+// Block makes the user of blockerID block the user with the username.
+func (blockUseCase *blockUseCase) Block(ctx context.Context, blockerID string, username string) (block domain.Block, err error) {
+	var user domain.User
+
+	if user, err = blockUseCase.userRepository.FindByUsername(ctx, &domain.User{Username: username}); err != nil {
+		return block, err
+	}
+
+	if user.ID == blockerID {
+		return block, domain.ErrSelfBlock
+	}
+
+	block = domain.Block{BlockerID: blockerID, BlockedID: user.ID}
+
+	if err = blockUseCase.blockRepository.Save(ctx, &block); err != nil {
+		return block, err
+	}
+
+	return block, nil
+}
+
+func (blockUseCase *blockUseCase) Unblock(ctx context.Context, blockerID string, username string) (err error) {
+	var user domain.User
+
+	if user, err = blockUseCase.userRepository.FindByUsername(ctx, &domain.User{Username: username}); err != nil {
+		return err
+	}
+
+	if user.ID == blockerID {
+		return domain.ErrSelfBlock
+	}
+
+	if err = blockUseCase.blockRepository.Delete(ctx, blockerID, user.ID); err != nil {
+		return err
+	}
+
+	return
+}
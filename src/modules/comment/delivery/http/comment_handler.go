@@ -1,40 +1,49 @@
 package delivery
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	"github.com/gusrylmubarok/mygram-backend/src/helpers"
 	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
 )
 
 type commentHandler struct {
 	commentUseCase domain.CommentUseCase
 	photoUseCase   domain.PhotoUseCase
+	userUseCase    domain.UserUseCase
 }
 
-func NewCommentHandler(routers *gin.Engine, commentUseCase domain.CommentUseCase, photoUseCase domain.PhotoUseCase) {
-	handler := &commentHandler{commentUseCase, photoUseCase}
+func NewCommentHandler(routers *gin.Engine, commentUseCase domain.CommentUseCase, photoUseCase domain.PhotoUseCase, userUseCase domain.UserUseCase) {
+	handler := &commentHandler{commentUseCase, photoUseCase, userUseCase}
 
 	router := routers.Group("/api/v1/comment")
 	{
 		router.Use(middleware.Authentication())
-		router.POST("", handler.CreateComment)
-		router.PUT("/:commentId", middleware.AuthorizationComment(handler.commentUseCase), handler.UpdateComment)
-		router.DELETE("/:commentId", middleware.AuthorizationComment(handler.commentUseCase), handler.DeleteById)
-		router.GET("/by-user/:userId", handler.GetAllByUser)
-		router.GET("/by-photo/:photoId", handler.GetAllByPhoto)
-		router.GET("/:commentId", handler.GetOne)
+		router.POST("", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.VerifiedEmail(handler.userUseCase), handler.CreateComment)
+		router.PUT("/:commentId", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.AuthorizationComment(handler.commentUseCase), handler.UpdateComment)
+		router.DELETE("/:commentId", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.AuthorizationCommentModeration(handler.commentUseCase, true), handler.DeleteById)
+		router.PUT("/:commentId/hide", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.AuthorizationCommentModeration(handler.commentUseCase, false), handler.Hide)
+		router.DELETE("/:commentId/hide", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.AuthorizationCommentModeration(handler.commentUseCase, false), handler.Show)
+		router.PUT("/:commentId/pin", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.AuthorizationCommentModeration(handler.commentUseCase, false), handler.Pin)
+		router.DELETE("/:commentId/pin", middleware.RequireScope(domain.ScopeCommentsWrite), middleware.AuthorizationCommentModeration(handler.commentUseCase, false), handler.Unpin)
+		router.GET("/by-user/:userId", middleware.RequireScope(domain.ScopeCommentsRead), handler.GetAllByUser)
+		router.GET("/by-photo/:photoId", middleware.RequireScope(domain.ScopeCommentsRead), handler.GetAllByPhoto)
+		router.GET("/:commentId", middleware.RequireScope(domain.ScopeCommentsRead), handler.GetOne)
+		router.GET("/:commentId/replies", middleware.RequireScope(domain.ScopeCommentsRead), handler.GetReplies)
 
 	}
 }
 
 // CreateComment godoc
 // @Summary			Add a comment
-// @Description		create and store a comment with authentication user
+// @Description		create and store a comment with authentication user, a reply to the comment with parent_id if given, as the comment policy of the photo allows
 // @Tags        	comment
 // @Accept      	json
 // @Produce     	json
@@ -42,7 +51,10 @@ func NewCommentHandler(routers *gin.Engine, commentUseCase domain.CommentUseCase
 // @Success     	201		{object}  		domain.AddedComment
 // @Failure     	400		{object}		helpers.ResponseMessage
 // @Failure     	401		{object}		helpers.ResponseMessage
+// @Failure     	403		{object}		helpers.ResponseMessage
+// @Failure     	404		{object}		helpers.ResponseMessage
 // @Security    	Bearer
+// @Security    	ApiKey
 // @Router      	/comment	[post]
 func (handler *commentHandler) CreateComment(ctx *gin.Context) {
 	var (
@@ -52,8 +64,7 @@ func (handler *commentHandler) CreateComment(ctx *gin.Context) {
 		err     error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err = ctx.ShouldBindJSON(&input); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -64,20 +75,45 @@ func (handler *commentHandler) CreateComment(ctx *gin.Context) {
 		return
 	}
 
-	if err = handler.photoUseCase.FindById(ctx.Request.Context(), &photo, input.PhotoID); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
-			Status:  "fail",
-			Message: fmt.Sprintf("photo with id %s doesn't exist", input.PhotoID),
-		})
+	// a reply may leave out the photo, it's the one of the comment replied to
+	if input.PhotoID != "" || input.ParentID == "" {
+		if err = handler.photoUseCase.FindById(ctx.Request.Context(), &photo, input.PhotoID); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("photo with id %s doesn't exist", input.PhotoID),
+			})
 
-		return
+			return
+		}
 	}
 
 	comment.Message = input.Message
 	comment.PhotoID = input.PhotoID
 	comment.UserID = userID
 
+	if input.ParentID != "" {
+		comment.ParentID = &input.ParentID
+	}
+
 	if err = handler.commentUseCase.Save(ctx.Request.Context(), &comment); err != nil {
+		if errors.Is(err, domain.ErrCommentsOff) || errors.Is(err, domain.ErrCommentsFollowsOnly) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+
+			return
+		}
+
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("comment with id %s doesn't exist", input.ParentID),
+			})
+
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -89,8 +125,10 @@ func (handler *commentHandler) CreateComment(ctx *gin.Context) {
 	ctx.JSON(http.StatusCreated, domain.AddedComment{
 		Status: "success",
 		Data: domain.AddedDataComment{
-			ID:      comment.ID,
-			Message: comment.Message,
+			ID:       comment.ID,
+			ParentID: comment.ParentID,
+			Message:  comment.Message,
+			Mentions: comment.Mentions,
 			User: &domain.GetUser{
 				ID:       comment.User.ID,
 				Email:    comment.User.Email,
@@ -120,6 +158,7 @@ func (handler *commentHandler) CreateComment(ctx *gin.Context) {
 // @Failure     	401		{object}		helpers.ResponseMessage
 // @Failure     	404		{object}		helpers.ResponseMessage
 // @Security    	Bearer
+// @Security    	ApiKey
 // @Router      	/comment/{commentId}	[put]
 func (handler *commentHandler) UpdateComment(ctx *gin.Context) {
 	var (
@@ -128,9 +167,6 @@ func (handler *commentHandler) UpdateComment(ctx *gin.Context) {
 		err     error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
-
 	if err = ctx.ShouldBindJSON(&input); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
@@ -139,8 +175,8 @@ func (handler *commentHandler) UpdateComment(ctx *gin.Context) {
 		return
 	}
 
+	// the owner is kept, a moderator may be the one editing
 	comment.Message = input.Message
-	comment.UserID = userID
 
 	commentID := ctx.Param("commentId")
 
@@ -155,8 +191,9 @@ func (handler *commentHandler) UpdateComment(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, domain.UpdatedComment{
 		Status: "success",
 		Data: domain.UpdatedDataComment{
-			ID:      comment.ID,
-			Message: comment.Message,
+			ID:       comment.ID,
+			Message:  comment.Message,
+			Mentions: comment.Mentions,
 			User: &domain.GetUser{
 				ID:       comment.User.ID,
 				Email:    comment.User.Email,
@@ -175,7 +212,7 @@ func (handler *commentHandler) UpdateComment(ctx *gin.Context) {
 
 // Delete godoc
 // @Summary		Delete a comment
-// @Description	Delete a comment by id with authentication user
+// @Description	Delete a comment by id, by its author or the owner of the photo, a comment with replies is blanked and kept for them
 // @Tags        comment
 // @Accept      json
 // @Produce     json
@@ -185,6 +222,7 @@ func (handler *commentHandler) UpdateComment(ctx *gin.Context) {
 // @Failure     401	{object}			helpers.ResponseMessage
 // @Failure     404	{object}			helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /comment/{commentId}	[delete]
 func (handler *commentHandler) DeleteById(ctx *gin.Context) {
 	commentID := ctx.Param("commentId")
@@ -205,24 +243,28 @@ func (handler *commentHandler) DeleteById(ctx *gin.Context) {
 
 // GetAllByUser godoc
 // @Summary			Get all comments
-// @Description		Get all comments with authentication user
+// @Description		Get a page of the comments of a user, on a photo with photo_id
 // @Tags        	comment
 // @Accept      	json
 // @Produce     	json
+// @Param			userId		path		string	true	"User ID"
+// @Param			photo_id	query		string	false	"Only the comments on the photo"
+// @Param			cursor		query		string	false	"next_cursor of the previous page"
+// @Param			limit		query		int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query		string	false	"-created_at for the most recent first, by default, or created_at"
 // @Success     	200	{object}	domain.GetAllComments
 // @Failure     	400	{object}	helpers.ResponseMessage
 // @Failure     	401	{object}	helpers.ResponseMessage
 // @Security    	Bearer
-// @Router      	/comment/by-user/{photoId}	[get]
+// @Security    	ApiKey
+// @Router      	/comment/by-user/{userId}	[get]
 func (handler *commentHandler) GetAllByUser(ctx *gin.Context) {
-	var (
-		comments []domain.Comment
-		err      error
-	)
+	var comments []domain.Comment
 
 	userID := ctx.Param("userId")
 
-	if err = handler.commentUseCase.FindAllByUser(ctx.Request.Context(), &comments, userID); err != nil {
+	params, err := pagination.Parse(ctx.Request.URL.Query(), "photo_id")
+	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -230,41 +272,52 @@ func (handler *commentHandler) GetAllByUser(ctx *gin.Context) {
 		return
 	}
 
-	if len(comments) < 1 {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+	if err = handler.commentUseCase.FindAllByUser(ctx.Request.Context(), &comments, userID, middleware.CurrentPrincipal(ctx).UserID, params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
-			Message: "comments is empty",
+			Message: err.Error(),
 		})
 		return
 	}
 
+	comments, meta := pagination.Trim(comments, params, commentKey)
+
 	ctx.JSON(http.StatusOK, domain.GetAllComments{
 		Status:  "success",
 		Message: "get all comments by user",
-		Data:    comments,
+		Data:    append([]domain.Comment{}, comments...),
+		Meta:    meta,
 	})
 }
 
 // Find By Photo godoc
 // @Summary			Get all by photo comments
-// @Description		Get all comments by photo with authentication user
+// @Description		Get a page of the top-level comments on a photo, of a user with user_id, with their first replies and the pinned comment on the first page
 // @Tags        	comment
 // @Accept      	json
 // @Produce     	json
-// @Success     	200	{object}	domain.GetAllComments
+// @Param			photoId		path		string	true	"Photo ID"
+// @Param			user_id		query		string	false	"Only the comments of the user"
+// @Param			cursor		query		string	false	"next_cursor of the previous page"
+// @Param			limit		query		int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query		string	false	"-created_at for the most recent first, by default, or created_at"
+// @Success     	200	{object}	domain.GetPhotoComments
 // @Failure     	400	{object}	helpers.ResponseMessage
 // @Failure     	401	{object}	helpers.ResponseMessage
 // @Security    	Bearer
+// @Security    	ApiKey
 // @Router      	/comment/by-photo/{photoId}     [get]
 func (handler *commentHandler) GetAllByPhoto(ctx *gin.Context) {
 	var (
 		comments []domain.Comment
-		err      error
+		pinned   domain.Comment
 	)
 
 	photoID := ctx.Param("photoId")
+	viewerID := middleware.CurrentPrincipal(ctx).UserID
 
-	if err = handler.commentUseCase.FindAllByPhoto(ctx.Request.Context(), &comments, photoID); err != nil {
+	params, err := pagination.Parse(ctx.Request.URL.Query(), "user_id")
+	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -272,21 +325,107 @@ func (handler *commentHandler) GetAllByPhoto(ctx *gin.Context) {
 		return
 	}
 
-	if len(comments) < 1 {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+	if err = handler.commentUseCase.FindAllByPhoto(ctx.Request.Context(), &comments, photoID, viewerID, params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
-			Message: "comments is empty",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, domain.GetAllComments{
+	comments, meta := pagination.Trim(comments, params, commentKey)
+
+	response := domain.GetPhotoComments{
 		Status:  "success",
 		Message: "get all comments by photo",
-		Data:    comments,
+		Data:    append([]domain.Comment{}, comments...),
+		Meta:    meta,
+	}
+
+	// the pinned comment leads the first page, it's left out of the others
+	if params.After == nil {
+		if err = handler.commentUseCase.FindPinned(ctx.Request.Context(), &pinned, photoID, viewerID); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if pinned.ID != "" {
+			response.Pinned = &pinned
+		}
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// GetReplies godoc
+// @Summary			Get the replies of a comment
+// @Description		Get a page of the replies to a comment
+// @Tags        	comment
+// @Accept      	json
+// @Produce     	json
+// @Param			commentId	path		string	true	"Comment ID"
+// @Param			cursor		query		string	false	"next_cursor of the previous page"
+// @Param			limit		query		int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query		string	false	"created_at for the oldest first, by default, or -created_at"
+// @Success     	200	{object}	domain.GetAllComments
+// @Failure     	400	{object}	helpers.ResponseMessage
+// @Failure     	401	{object}	helpers.ResponseMessage
+// @Failure     	404	{object}	helpers.ResponseMessage
+// @Security    	Bearer
+// @Security    	ApiKey
+// @Router      	/comment/{commentId}/replies     [get]
+func (handler *commentHandler) GetReplies(ctx *gin.Context) {
+	var replies []domain.Comment
+
+	commentID := ctx.Param("commentId")
+
+	params, err := pagination.Parse(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// a conversation reads from its start
+	if ctx.Query("sort") == "" {
+		params.Ascending = true
+	}
+
+	if err = handler.commentUseCase.FindReplies(ctx.Request.Context(), &replies, commentID, middleware.CurrentPrincipal(ctx).UserID, params); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("comment with id %s doesn't exist", commentID),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	replies, meta := pagination.Trim(replies, params, commentKey)
+
+	ctx.JSON(http.StatusOK, domain.GetAllComments{
+		Status:  "success",
+		Message: "get all replies by comment",
+		Data:    append([]domain.Comment{}, replies...),
+		Meta:    meta,
 	})
 }
 
+func commentKey(comment domain.Comment) (*time.Time, string) {
+	return comment.CreatedAt, comment.ID
+}
+
 // Find By Id godoc
 // @Summary			Get all by photo comments
 // @Description		Get all comments by photo with authentication user
@@ -296,7 +435,9 @@ func (handler *commentHandler) GetAllByPhoto(ctx *gin.Context) {
 // @Success     	200	{object}	domain.GetAComment
 // @Failure     	400	{object}	helpers.ResponseMessage
 // @Failure     	401	{object}	helpers.ResponseMessage
+// @Failure     	404	{object}	helpers.ResponseMessage
 // @Security    	Bearer
+// @Security    	ApiKey
 // @Router      	/comment/{commentId}     [get]
 func (handler *commentHandler) GetOne(ctx *gin.Context) {
 	var (
@@ -306,7 +447,15 @@ func (handler *commentHandler) GetOne(ctx *gin.Context) {
 
 	commentID := ctx.Param("commentId")
 
-	if err = handler.commentUseCase.FindById(ctx.Request.Context(), &comment, commentID); err != nil {
+	if err = handler.commentUseCase.FindVisible(ctx.Request.Context(), &comment, commentID, middleware.CurrentPrincipal(ctx).UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("comment with id %s doesn't exist", commentID),
+			})
+			return
+		}
+
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -320,3 +469,116 @@ func (handler *commentHandler) GetOne(ctx *gin.Context) {
 		Data:    comment,
 	})
 }
+
+// Hide godoc
+// @Summary		Hide a comment
+// @Description	Hide a comment on a photo of the authentication user from everyone else, a pinned comment is unpinned
+// @Tags        comment
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path		string	true	"Comment ID"
+// @Success     200	{object}	domain.ModeratedComment
+// @Failure     400	{object}	helpers.ResponseMessage
+// @Failure     401	{object}	helpers.ResponseMessage
+// @Failure     404	{object}	helpers.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comment/{commentId}/hide	[put]
+func (handler *commentHandler) Hide(ctx *gin.Context) {
+	handler.hide(ctx, true, "the comment has been hidden")
+}
+
+// Show godoc
+// @Summary		Show a hidden comment
+// @Description	Show a comment hidden on a photo of the authentication user again
+// @Tags        comment
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path		string	true	"Comment ID"
+// @Success     200	{object}	domain.ModeratedComment
+// @Failure     400	{object}	helpers.ResponseMessage
+// @Failure     401	{object}	helpers.ResponseMessage
+// @Failure     404	{object}	helpers.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comment/{commentId}/hide	[delete]
+func (handler *commentHandler) Show(ctx *gin.Context) {
+	handler.hide(ctx, false, "the comment is shown again")
+}
+
+func (handler *commentHandler) hide(ctx *gin.Context, hidden bool, message string) {
+	comment, err := handler.commentUseCase.Hide(ctx.Request.Context(), ctx.Param("commentId"), hidden)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.ModeratedComment{
+		Status:  "success",
+		Message: message,
+		Data:    comment,
+	})
+}
+
+// Pin godoc
+// @Summary		Pin a comment
+// @Description	Pin a top-level comment on a photo of the authentication user, in place of the one pinned before
+// @Tags        comment
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path		string	true	"Comment ID"
+// @Success     200	{object}	domain.ModeratedComment
+// @Failure     400	{object}	helpers.ResponseMessage
+// @Failure     401	{object}	helpers.ResponseMessage
+// @Failure     404	{object}	helpers.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comment/{commentId}/pin	[put]
+func (handler *commentHandler) Pin(ctx *gin.Context) {
+	comment, err := handler.commentUseCase.Pin(ctx.Request.Context(), ctx.Param("commentId"))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.ModeratedComment{
+		Status:  "success",
+		Message: "the comment has been pinned",
+		Data:    comment,
+	})
+}
+
+// Unpin godoc
+// @Summary		Unpin a comment
+// @Description	Unpin the comment pinned on a photo of the authentication user
+// @Tags        comment
+// @Accept      json
+// @Produce     json
+// @Param       commentId	path		string	true	"Comment ID"
+// @Success     200	{object}	helpers.ResponseMessage
+// @Failure     400	{object}	helpers.ResponseMessage
+// @Failure     401	{object}	helpers.ResponseMessage
+// @Failure     404	{object}	helpers.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /comment/{commentId}/pin	[delete]
+func (handler *commentHandler) Unpin(ctx *gin.Context) {
+	if err := handler.commentUseCase.Unpin(ctx.Request.Context(), ctx.Param("commentId")); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, helpers.ResponseMessage{
+		Status:  "success",
+		Message: "the comment has been unpinned",
+	})
+}
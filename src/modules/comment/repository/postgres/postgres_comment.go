@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	gonanoid "github.com/matoous/go-nanoid/v2"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type commentRepository struct {
@@ -26,7 +28,24 @@ func (commentRepository *commentRepository) Save(ctx context.Context, comment *d
 
 	comment.ID = fmt.Sprintf("comment-%s", ID)
 
-	if err = commentRepository.db.WithContext(ctx).Create(&comment).Error; err != nil {
+	for i := range comment.Mentions {
+		ID, _ := gonanoid.New(16)
+
+		comment.Mentions[i].ID = fmt.Sprintf("mention-%s", ID)
+	}
+
+	// the mentions are created along
+	if err = commentRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Create(&comment).Error; err != nil {
+			return err
+		}
+
+		if comment.ParentID == nil {
+			return
+		}
+
+		return tx.Model(&domain.Comment{}).Where("id = ?", *comment.ParentID).UpdateColumn("reply_count", gorm.Expr("reply_count + 1")).Error
+	}); err != nil {
 		return err
 	}
 
@@ -34,7 +53,7 @@ func (commentRepository *commentRepository) Save(ctx context.Context, comment *d
 		return db.Select("id", "username", "email")
 	}).Preload("Photo", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "title", "caption", "photo_url", "user_id")
-	}).First(&comment).Error; err != nil {
+	}).Preload("Mentions").First(&comment).Error; err != nil {
 		return err
 	}
 
@@ -49,7 +68,33 @@ func (commentRepository *commentRepository) Update(ctx context.Context, c domain
 		return comment, err
 	}
 
-	if err = commentRepository.db.WithContext(ctx).Model(&comment).Updates(c).Error; err != nil {
+	if err = commentRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Model(&comment).Omit("Mentions").Updates(c).Error; err != nil {
+			return err
+		}
+
+		// an empty message is left as it is by Updates, and so are its mentions
+		if c.Message == "" {
+			return
+		}
+
+		if err = tx.Where("comment_id = ?", comment.ID).Delete(&domain.Mention{}).Error; err != nil {
+			return err
+		}
+
+		if len(c.Mentions) == 0 {
+			return
+		}
+
+		for i := range c.Mentions {
+			ID, _ := gonanoid.New(16)
+
+			c.Mentions[i].ID = fmt.Sprintf("mention-%s", ID)
+			c.Mentions[i].CommentID = &comment.ID
+		}
+
+		return tx.Create(&c.Mentions).Error
+	}); err != nil {
 		return comment, err
 	}
 
@@ -57,29 +102,93 @@ func (commentRepository *commentRepository) Update(ctx context.Context, c domain
 		return db.Select("id", "username", "email")
 	}).Preload("Photo", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "title", "caption", "photo_url", "user_id")
-	}).First(&comment).Error; err != nil {
+	}).Preload("Mentions").First(&comment).Error; err != nil {
 		return comment, err
 	}
 
 	return comment, nil
 }
 
-func (commentRepository *commentRepository) DeleteById(ctx context.Context, id string) (err error) {
+// UpdateHidden hides the comment, or shows it again when hiddenAt is nil. A
+// hidden comment is unpinned.
+func (commentRepository *commentRepository) UpdateHidden(ctx context.Context, id string, hiddenAt *time.Time) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	if err = commentRepository.db.WithContext(ctx).First(&domain.Comment{}, &id).Error; err != nil {
+	if err = commentRepository.db.WithContext(ctx).First(&domain.Comment{}, "id = ?", id).Error; err != nil {
 		return err
 	}
 
-	if err = commentRepository.db.WithContext(ctx).Delete(&domain.Comment{}, &id).Error; err != nil {
-		return err
-	}
+	return commentRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Model(&domain.Comment{}).Where("id = ?", id).UpdateColumn("hidden_at", hiddenAt).Error; err != nil {
+			return err
+		}
 
-	return
+		if hiddenAt == nil {
+			return
+		}
+
+		return tx.Model(&domain.Photo{}).Where("pinned_comment_id = ?", id).UpdateColumn("pinned_comment_id", nil).Error
+	})
 }
 
-func (commentRepository *commentRepository) FindAllByUser(ctx context.Context, comments *[]domain.Comment, userID string) (err error) {
+func (commentRepository *commentRepository) DeleteById(ctx context.Context, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return commentRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		var comment domain.Comment
+
+		// the row is locked so that no reply is saved between the count and the delete
+		if err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&comment, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Model(&domain.Photo{}).Where("pinned_comment_id = ?", id).UpdateColumn("pinned_comment_id", nil).Error; err != nil {
+			return err
+		}
+
+		// a comment with replies is only blanked so that the conversation
+		// stays, without the mentions of its message
+		if comment.ReplyCount > 0 {
+			if err = tx.Where("comment_id = ?", id).Delete(&domain.Mention{}).Error; err != nil {
+				return err
+			}
+
+			return tx.Model(&comment).UpdateColumns(map[string]interface{}{
+				"message":    "",
+				"deleted_at": time.Now(),
+			}).Error
+		}
+
+		if err = tx.Delete(&domain.Comment{}, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		// the parents lose a reply, and a deleted parent left with none goes too
+		for parentID := comment.ParentID; parentID != nil; {
+			var parent domain.Comment
+
+			if err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&parent, "id = ?", *parentID).Error; err != nil {
+				return err
+			}
+
+			if !parent.Deleted() || parent.ReplyCount > 1 {
+				return tx.Model(&parent).UpdateColumn("reply_count", gorm.Expr("reply_count - 1")).Error
+			}
+
+			if err = tx.Delete(&domain.Comment{}, "id = ?", parent.ID).Error; err != nil {
+				return err
+			}
+
+			parentID = parent.ParentID
+		}
+
+		return
+	})
+}
+
+func (commentRepository *commentRepository) FindAllByUser(ctx context.Context, comments *[]domain.Comment, userID string, filter domain.CommentFilter, params pagination.Params) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -87,22 +196,59 @@ func (commentRepository *commentRepository) FindAllByUser(ctx context.Context, c
 		return db.Select("id", "username", "email", "created_at", "updated_at")
 	}).Preload("Photo", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "title", "caption", "photo_url", "user_id")
-	}).Find(&comments).Error; err != nil {
+	}).Preload("Mentions").Scopes(filterScope(filter), pagination.Scope(params, "created_at", "id")).Find(&comments).Error; err != nil {
 		return err
 	}
 
 	return
 }
 
-func (commentRepository *commentRepository) FindAllByPhoto(ctx context.Context, comments *[]domain.Comment, photoID string) (err error) {
+func (commentRepository *commentRepository) FindAllByPhoto(ctx context.Context, comments *[]domain.Comment, photoID string, filter domain.CommentFilter, params pagination.Params) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	if err = commentRepository.db.WithContext(ctx).Where("photo_id = ?", photoID).Preload("User", func(db *gorm.DB) *gorm.DB {
+	if err = commentRepository.db.WithContext(ctx).Where("photo_id = ? AND parent_id IS NULL", photoID).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "username", "email", "created_at", "updated_at")
 	}).Preload("Photo", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "title", "caption", "photo_url", "user_id")
-	}).Find(&comments).Error; err != nil {
+	}).Preload("Mentions").Scopes(filterScope(filter), pagination.Scope(params, "created_at", "id")).Find(&comments).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (commentRepository *commentRepository) FindAllByParent(ctx context.Context, comments *[]domain.Comment, parentID string, filter domain.CommentFilter, params pagination.Params) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = commentRepository.db.WithContext(ctx).Where("parent_id = ?", parentID).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email", "created_at", "updated_at")
+	}).Preload("Mentions").Scopes(filterScope(filter), pagination.Scope(params, "created_at", "id")).Find(&comments).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// FindFirstReplies finds the oldest replies of each of the comments, at most
+// limit a comment, in a single query.
+func (commentRepository *commentRepository) FindFirstReplies(ctx context.Context, replies *[]domain.Comment, parentIDs []string, filter domain.CommentFilter, limit int) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if len(parentIDs) == 0 || limit <= 0 {
+		return
+	}
+
+	ranked := commentRepository.db.Model(&domain.Comment{}).
+		Select("comments.*, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY created_at, id) AS reply_rank").
+		Where("parent_id IN ?", parentIDs).
+		Scopes(filterScope(filter))
+
+	if err = commentRepository.db.WithContext(ctx).Table("(?) AS comments", ranked).Where("reply_rank <= ?", limit).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email", "created_at", "updated_at")
+	}).Preload("Mentions").Order("created_at, id").Find(&replies).Error; err != nil {
 		return err
 	}
 
@@ -117,9 +263,25 @@ func (commentRepository *commentRepository) FindById(ctx context.Context, commen
 		return db.Select("id", "username", "email")
 	}).Preload("Photo", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "title", "caption", "photo_url", "user_id")
-	}).Find(&comments).Error; err != nil {
+	}).Preload("Mentions").Find(&comments).Error; err != nil {
 		return err
 	}
 
 	return
 }
+
+func filterScope(filter domain.CommentFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !filter.WithHidden && filter.PhotoOwnerID != "" {
+			db = db.Where("(hidden_at IS NULL OR photo_id IN (SELECT id FROM photos WHERE user_id = ?))", filter.PhotoOwnerID)
+		} else if !filter.WithHidden {
+			db = db.Where("hidden_at IS NULL")
+		}
+
+		if filter.ExceptID != "" {
+			db = db.Where("id <> ?", filter.ExceptID)
+		}
+
+		return db
+	}
+}
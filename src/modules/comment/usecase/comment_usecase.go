@@ -2,31 +2,126 @@ package usecase
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
 )
 
 type commentUseCase struct {
 	commentRepository domain.CommentRepository
+	photoRepository   domain.PhotoRepository
+	followRepository  domain.FollowRepository
+	mentionUseCase    domain.MentionUseCase
+	maxDepth          int
+	inlineReplies     int
 }
 
-func NewCommentUseCase(commentRepository domain.CommentRepository) *commentUseCase {
-	return &commentUseCase{commentRepository}
+func NewCommentUseCase(commentRepository domain.CommentRepository, photoRepository domain.PhotoRepository, followRepository domain.FollowRepository, mentionUseCase domain.MentionUseCase, maxDepth int, inlineReplies int) *commentUseCase {
+	return &commentUseCase{commentRepository, photoRepository, followRepository, mentionUseCase, maxDepth, inlineReplies}
 }
 
 func (commentUseCase *commentUseCase) Save(ctx context.Context, comment *domain.Comment) (err error) {
+	if comment.ParentID != nil {
+		var parent domain.Comment
+
+		if err = commentUseCase.commentRepository.FindById(ctx, &parent, *comment.ParentID); err != nil {
+			return err
+		}
+
+		if parent.ID == "" {
+			return gorm.ErrRecordNotFound
+		}
+
+		if parent.Deleted() {
+			return domain.ErrCommentDeleted
+		}
+
+		// the replies of a hidden comment are hidden along with it
+		if parent.Hidden() {
+			return domain.ErrCommentHidden
+		}
+
+		if parent.Depth+1 > commentUseCase.maxDepth {
+			return domain.ErrReplyTooDeep
+		}
+
+		if comment.PhotoID == "" {
+			comment.PhotoID = parent.PhotoID
+		}
+
+		if comment.PhotoID != parent.PhotoID {
+			return domain.ErrReplyPhotoMismatch
+		}
+
+		comment.Depth = parent.Depth + 1
+	}
+
+	var photo domain.Photo
+
+	if err = commentUseCase.photoRepository.FindById(ctx, &photo, comment.PhotoID); err != nil {
+		return err
+	}
+
+	switch photo.CommentPolicy {
+	case domain.CommentPolicyOff:
+		return domain.ErrCommentsOff
+	case domain.CommentPolicyFollowers:
+		if comment.UserID != photo.UserID {
+			following, err := commentUseCase.followRepository.IsFollowing(ctx, comment.UserID, photo.UserID)
+			if err != nil {
+				return err
+			}
+
+			if !following {
+				return domain.ErrCommentsFollowsOnly
+			}
+		}
+	}
+
+	comment.Mentions = commentUseCase.mentionsOf(ctx, comment.UserID, comment.PhotoID, comment.Message)
+
 	if err = commentUseCase.commentRepository.Save(ctx, comment); err != nil {
 		return err
 	}
 
+	// the comment is stored, the mentioned users missing a notification is no
+	// reason to fail it
+	if err := commentUseCase.mentionUseCase.Notify(ctx, comment.UserID, comment.Mentions, nil); err != nil {
+		log.Println("Error notifying the users mentioned in the comment: ", err)
+	}
+
 	return
 }
 
 func (commentUseCase *commentUseCase) Update(ctx context.Context, c domain.Comment, id string) (comment domain.Comment, err error) {
+	if err = commentUseCase.commentRepository.FindById(ctx, &comment, id); err != nil {
+		return comment, err
+	}
+
+	if comment.Deleted() {
+		return comment, domain.ErrCommentDeleted
+	}
+
+	previous := comment.Mentions
+
+	if c.Message != "" {
+		c.Mentions = commentUseCase.mentionsOf(ctx, comment.UserID, comment.PhotoID, c.Message)
+	}
+
 	if comment, err = commentUseCase.commentRepository.Update(ctx, c, id); err != nil {
 		return comment, err
 	}
 
+	// only the users newly mentioned are notified of an edit
+	if c.Message != "" {
+		if err := commentUseCase.mentionUseCase.Notify(ctx, comment.UserID, comment.Mentions, previous); err != nil {
+			log.Println("Error notifying the users mentioned in the comment: ", err)
+		}
+	}
+
 	return comment, nil
 }
 
@@ -38,16 +133,162 @@ func (commentUseCase *commentUseCase) DeleteById(ctx context.Context, id string)
 	return
 }
 
-func (commentUseCase *commentUseCase) FindAllByUser(ctx context.Context, comments *[]domain.Comment, userID string) (err error) {
-	if err = commentUseCase.commentRepository.FindAllByUser(ctx, comments, userID); err != nil {
+// Hide hides the comment from everyone but the owner of the photo, or shows
+// it again.
+func (commentUseCase *commentUseCase) Hide(ctx context.Context, id string, hidden bool) (comment domain.Comment, err error) {
+	var hiddenAt *time.Time
+
+	if hidden {
+		now := time.Now()
+		hiddenAt = &now
+	}
+
+	if err = commentUseCase.commentRepository.UpdateHidden(ctx, id, hiddenAt); err != nil {
+		return comment, err
+	}
+
+	if err = commentUseCase.commentRepository.FindById(ctx, &comment, id); err != nil {
+		return comment, err
+	}
+
+	return comment, nil
+}
+
+// Pin pins the comment on its photo, in place of the one pinned before.
+func (commentUseCase *commentUseCase) Pin(ctx context.Context, id string) (comment domain.Comment, err error) {
+	if err = commentUseCase.commentRepository.FindById(ctx, &comment, id); err != nil {
+		return comment, err
+	}
+
+	if comment.ID == "" {
+		return comment, gorm.ErrRecordNotFound
+	}
+
+	if comment.ParentID != nil || comment.Deleted() || comment.Hidden() {
+		return comment, domain.ErrCommentNotPinnable
+	}
+
+	if err = commentUseCase.photoRepository.UpdatePinnedComment(ctx, comment.PhotoID, &comment.ID); err != nil {
+		return comment, err
+	}
+
+	return comment, nil
+}
+
+// Unpin unpins the comment, nothing changes if it isn't the pinned one.
+func (commentUseCase *commentUseCase) Unpin(ctx context.Context, id string) (err error) {
+	var (
+		comment domain.Comment
+		photo   domain.Photo
+	)
+
+	if err = commentUseCase.commentRepository.FindById(ctx, &comment, id); err != nil {
+		return err
+	}
+
+	if comment.ID == "" {
+		return gorm.ErrRecordNotFound
+	}
+
+	if err = commentUseCase.photoRepository.FindById(ctx, &photo, comment.PhotoID); err != nil {
+		return err
+	}
+
+	if photo.PinnedCommentID == nil || *photo.PinnedCommentID != id {
+		return
+	}
+
+	if err = commentUseCase.photoRepository.UpdatePinnedComment(ctx, photo.ID, nil); err != nil {
 		return err
 	}
 
 	return
 }
 
-func (commentUseCase *commentUseCase) FindAllByPhoto(ctx context.Context, comment *[]domain.Comment, id string) (err error) {
-	if err = commentUseCase.commentRepository.FindAllByPhoto(ctx, comment, id); err != nil {
+// FindAllByUser finds the comments of the user, the hidden ones only for the
+// owner of their photo as everywhere else.
+func (commentUseCase *commentUseCase) FindAllByUser(ctx context.Context, comments *[]domain.Comment, userID string, viewerID string, params pagination.Params) (err error) {
+	filter := domain.CommentFilter{PhotoOwnerID: viewerID}
+
+	if err = commentUseCase.commentRepository.FindAllByUser(ctx, comments, userID, filter, params); err != nil {
+		return err
+	}
+
+	return
+}
+
+// FindAllByPhoto finds the top-level comments on the photo, but the pinned
+// one, with their first replies. The hidden comments are only found for the
+// owner of the photo.
+func (commentUseCase *commentUseCase) FindAllByPhoto(ctx context.Context, comments *[]domain.Comment, photoID string, viewerID string, params pagination.Params) (err error) {
+	var photo domain.Photo
+
+	if err = commentUseCase.photoRepository.FindById(ctx, &photo, photoID); err != nil {
+		return err
+	}
+
+	filter := domain.CommentFilter{WithHidden: photo.UserID == viewerID}
+	if photo.PinnedCommentID != nil {
+		filter.ExceptID = *photo.PinnedCommentID
+	}
+
+	if err = commentUseCase.commentRepository.FindAllByPhoto(ctx, comments, photoID, filter, params); err != nil {
+		return err
+	}
+
+	filter.ExceptID = ""
+
+	if err = commentUseCase.inline(ctx, *comments, filter); err != nil {
+		return err
+	}
+
+	return
+}
+
+// FindPinned finds the comment pinned on the photo, with its first replies,
+// the comment is left empty if none is.
+func (commentUseCase *commentUseCase) FindPinned(ctx context.Context, comment *domain.Comment, photoID string, viewerID string) (err error) {
+	var photo domain.Photo
+
+	if err = commentUseCase.photoRepository.FindById(ctx, &photo, photoID); err != nil {
+		return err
+	}
+
+	if photo.PinnedCommentID == nil {
+		return
+	}
+
+	if err = commentUseCase.commentRepository.FindById(ctx, comment, *photo.PinnedCommentID); err != nil {
+		return err
+	}
+
+	pinned := []domain.Comment{*comment}
+
+	if err = commentUseCase.inline(ctx, pinned, domain.CommentFilter{WithHidden: photo.UserID == viewerID}); err != nil {
+		return err
+	}
+
+	*comment = pinned[0]
+
+	return
+}
+
+// FindReplies finds the replies to the comment, the hidden ones only for the
+// owner of the photo.
+func (commentUseCase *commentUseCase) FindReplies(ctx context.Context, replies *[]domain.Comment, id string, viewerID string, params pagination.Params) (err error) {
+	var parent domain.Comment
+
+	if err = commentUseCase.commentRepository.FindById(ctx, &parent, id); err != nil {
+		return err
+	}
+
+	if !visible(parent, viewerID) {
+		return gorm.ErrRecordNotFound
+	}
+
+	filter := domain.CommentFilter{WithHidden: parent.Photo != nil && parent.Photo.UserID == viewerID}
+
+	if err = commentUseCase.commentRepository.FindAllByParent(ctx, replies, id, filter, params); err != nil {
 		return err
 	}
 
@@ -61,3 +302,74 @@ func (commentUseCase *commentUseCase) FindById(ctx context.Context, comment *dom
 
 	return
 }
+
+// FindVisible finds the comment as the viewer sees it, a hidden comment is
+// only found for the owner of the photo.
+func (commentUseCase *commentUseCase) FindVisible(ctx context.Context, comment *domain.Comment, id string, viewerID string) (err error) {
+	if err = commentUseCase.commentRepository.FindById(ctx, comment, id); err != nil {
+		return err
+	}
+
+	if !visible(*comment, viewerID) {
+		*comment = domain.Comment{}
+
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+// visible tells whether the comment exists for the viewer, a hidden one only
+// does for the owner of the photo.
+func visible(comment domain.Comment, viewerID string) bool {
+	if comment.ID == "" {
+		return false
+	}
+
+	return !comment.Hidden() || comment.Photo != nil && comment.Photo.UserID == viewerID
+}
+
+// inline sets the first replies of each of the comments that has some, found
+// in a single query.
+func (commentUseCase *commentUseCase) inline(ctx context.Context, comments []domain.Comment, filter domain.CommentFilter) (err error) {
+	var parentIDs []string
+
+	for _, c := range comments {
+		if c.ReplyCount > 0 {
+			parentIDs = append(parentIDs, c.ID)
+		}
+	}
+
+	if len(parentIDs) == 0 || commentUseCase.inlineReplies == 0 {
+		return
+	}
+
+	var replies []domain.Comment
+
+	if err = commentUseCase.commentRepository.FindFirstReplies(ctx, &replies, parentIDs, filter, commentUseCase.inlineReplies); err != nil {
+		return err
+	}
+
+	repliesByParent := make(map[string][]domain.Comment, len(parentIDs))
+
+	for _, reply := range replies {
+		repliesByParent[*reply.ParentID] = append(repliesByParent[*reply.ParentID], reply)
+	}
+
+	for i := range comments {
+		comments[i].Replies = repliesByParent[comments[i].ID]
+	}
+
+	return
+}
+
+// mentionsOf resolves the mentions of the message of a comment on the photo.
+func (commentUseCase *commentUseCase) mentionsOf(ctx context.Context, authorID string, photoID string, message string) []domain.Mention {
+	mentions := commentUseCase.mentionUseCase.Resolve(ctx, authorID, message)
+
+	for i := range mentions {
+		mentions[i].PhotoID = photoID
+	}
+
+	return mentions
+}
@@ -0,0 +1,107 @@
+package delivery
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+)
+
+type emailVerificationHandler struct {
+	emailVerificationUseCase domain.EmailVerificationUseCase
+}
+
+func NewEmailVerificationHandler(routers *gin.Engine, emailVerificationUseCase domain.EmailVerificationUseCase) *emailVerificationHandler {
+	handler := &emailVerificationHandler{emailVerificationUseCase}
+
+	router := routers.Group("/api/v1/user/email")
+	{
+		router.POST("/verify", handler.Verify)
+		router.POST("/verification", middleware.Authentication(), middleware.SessionOnly(), handler.Resend)
+	}
+
+	return handler
+}
+
+// Verify godoc
+// @Summary			Verify an email address
+// @Description		Confirm the email address of a verification link, a pending email replaces the current one
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.VerifyEmail	true	"Verify Email"
+// @Success			200		{object}		domain.VerifiedEmail
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			409		{object}		helpers.ResponseMessage
+// @Router			/user/email/verify		[post]
+func (handler *emailVerificationHandler) Verify(ctx *gin.Context) {
+	var (
+		input domain.VerifyEmail
+		user  domain.User
+		err   error
+	)
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if user, err = handler.emailVerificationUseCase.Verify(ctx.Request.Context(), input.Token); err != nil {
+		if strings.Contains(err.Error(), "idx_users_email") {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: "the email you entered has been used",
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.VerifiedEmail{
+		Status:  "success",
+		Message: "your email address has been verified",
+		Data: domain.GetUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+		},
+	})
+}
+
+// Resend godoc
+// @Summary			Resend the email verification
+// @Description		Send a new verification link for the pending or unverified email of the authentication user
+// @Tags			user
+// @Produce			json
+// @Success			200		{object}		domain.EmailVerificationResponse
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/email/verification		[post]
+func (handler *emailVerificationHandler) Resend(ctx *gin.Context) {
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err := handler.emailVerificationUseCase.Resend(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.EmailVerificationResponse{
+		Status:  "success",
+		Message: "a verification link has been sent if your email address is not verified yet",
+	})
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+)
+
+type emailVerificationUseCase struct {
+	userRepository domain.UserRepository
+	mailer         domain.Mailer
+	tokenSigner    domain.TokenSigner
+	verifyURL      string
+	ttl            time.Duration
+}
+
+func NewEmailVerificationUseCase(userRepository domain.UserRepository, mailer domain.Mailer, tokenSigner domain.TokenSigner, verifyURL string, ttl time.Duration) *emailVerificationUseCase {
+	return &emailVerificationUseCase{userRepository, mailer, tokenSigner, verifyURL, ttl}
+}
+
+// Send mails a verification link for the pending email of the user or, when
+// there is none, for the current email if it isn't verified yet. The link
+// holds a signed token, so nothing has to be stored to check it later.
+func (emailVerificationUseCase *emailVerificationUseCase) Send(ctx context.Context, user domain.User) (err error) {
+	var token string
+
+	email := user.PendingEmail
+
+	if email == "" {
+		if user.EmailVerifiedAt != nil {
+			return nil
+		}
+
+		email = user.Email
+	}
+
+	claims := domain.TokenClaims{
+		"id":    user.ID,
+		"email": email,
+	}
+
+	if token, err = emailVerificationUseCase.tokenSigner.Sign(domain.TokenTypeEmailVerification, claims, emailVerificationUseCase.ttl); err != nil {
+		return err
+	}
+
+	return emailVerificationUseCase.mailer.Send(ctx, domain.Mail{
+		To:      email,
+		Subject: "Verify your MyGram email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nplease confirm %s is your email address by opening the link below, it expires in %s:\n\n%s?token=%s\n\nIf you don't have a MyGram account, you can ignore this mail.\n",
+			user.Username, email, emailVerificationUseCase.ttl, emailVerificationUseCase.verifyURL, url.QueryEscape(token),
+		),
+	})
+}
+
+// Resend mails a new verification link to the user with the given id.
+func (emailVerificationUseCase *emailVerificationUseCase) Resend(ctx context.Context, userID string) (err error) {
+	var user domain.User
+
+	if user, err = emailVerificationUseCase.userRepository.FindById(ctx, userID); err != nil {
+		return err
+	}
+
+	return emailVerificationUseCase.Send(ctx, user)
+}
+
+// Verify confirms the email address the token was issued for. A link for an
+// address the user has since replaced is rejected.
+func (emailVerificationUseCase *emailVerificationUseCase) Verify(ctx context.Context, token string) (user domain.User, err error) {
+	claims, err := emailVerificationUseCase.tokenSigner.Parse(domain.TokenTypeEmailVerification, token)
+
+	if err != nil {
+		return user, domain.ErrInvalidEmailVerificationToken
+	}
+
+	userID, _ := claims["id"].(string)
+	email, _ := claims["email"].(string)
+
+	if userID == "" || email == "" {
+		return user, domain.ErrInvalidEmailVerificationToken
+	}
+
+	if user, err = emailVerificationUseCase.userRepository.VerifyEmail(ctx, userID, email); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return user, domain.ErrInvalidEmailVerificationToken
+		}
+
+		return user, err
+	}
+
+	return user, nil
+}
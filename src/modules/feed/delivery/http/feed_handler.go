@@ -0,0 +1,121 @@
+package delivery
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+type feedHandler struct {
+	feedUseCase domain.FeedUseCase
+	likeUseCase domain.LikeUseCase
+}
+
+func NewFeedHandler(routers *gin.Engine, feedUseCase domain.FeedUseCase, likeUseCase domain.LikeUseCase) *feedHandler {
+	handler := &feedHandler{feedUseCase, likeUseCase}
+
+	router := routers.Group("/api/v1/feed")
+	{
+		router.Use(middleware.Authentication())
+		router.GET("", middleware.RequireScope(domain.ScopePhotosRead), handler.Get)
+	}
+
+	return handler
+}
+
+// Get godoc
+// @Summary			Get the home feed
+// @Description		Get a page of the photos of the users the authentication user follows and their own, the most recent first
+// @Tags			feed
+// @Produce			json
+// @Param			cursor		query			string	false	"next_cursor of the previous page"
+// @Param			limit		query			int		false	"Page size, 20 by default and 100 at most"
+// @Success			200			{object}		domain.FetchedFeed
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Security		ApiKey
+// @Router			/feed		[get]
+func (handler *feedHandler) Get(ctx *gin.Context) {
+	var photos []domain.Photo
+
+	params, err := pagination.Parse(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// a feed is always read from the most recent photo
+	params.Ascending = false
+
+	if err = handler.feedUseCase.FindByUser(ctx.Request.Context(), &photos, middleware.CurrentPrincipal(ctx).UserID, params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	photos, meta := pagination.Trim(photos, params, func(photo domain.Photo) (*time.Time, string) { return photo.CreatedAt, photo.ID })
+
+	photoIDs := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		photoIDs = append(photoIDs, photo.ID)
+	}
+
+	liked, err := handler.likeUseCase.FindLikedPhotoIDs(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, photoIDs)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fetched := domain.FetchedFeed{
+		Status:  "success",
+		Message: "the feed has been successfully fetched",
+		Data:    make([]*domain.GetDetailPhoto, 0, len(photos)),
+		Meta:    meta,
+	}
+
+	for _, photo := range photos {
+		detail := &domain.GetDetailPhoto{
+			ID:              photo.ID,
+			Title:           photo.Title,
+			Caption:         photo.Caption,
+			PhotoUrl:        photo.PhotoUrl,
+			Status:          photo.Status,
+			Variants:        photo.VariantURLs(),
+			Hashtags:        photo.HashtagNames(),
+			Mentions:        photo.Mentions,
+			Metadata:        photo.CameraMetadata(),
+			LikeCount:       photo.LikeCount,
+			Liked:           liked[photo.ID],
+			CommentPolicy:   photo.CommentPolicy,
+			PinnedCommentID: photo.PinnedCommentID,
+			CreatedAt:       photo.CreatedAt,
+			UpdatedAt:       photo.UpdatedAt,
+		}
+
+		if photo.User != nil {
+			detail.User = &domain.GetUser{
+				ID:       photo.User.ID,
+				Email:    photo.User.Email,
+				Username: photo.User.Username,
+			}
+		}
+
+		fetched.Data = append(fetched.Data, detail)
+	}
+
+	ctx.JSON(http.StatusOK, fetched)
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type fanOutOnRead struct {
+	db *gorm.DB
+}
+
+// NewFanOutOnRead makes the feed strategy which queries the photos of the
+// followed users when the feed is read, writes cost nothing but reads join
+// the follows.
+func NewFanOutOnRead(db *gorm.DB) *fanOutOnRead {
+	return &fanOutOnRead{db}
+}
+
+func (fanOutOnRead *fanOutOnRead) AddPhoto(ctx context.Context, photo domain.Photo) (err error) {
+	return
+}
+
+func (fanOutOnRead *fanOutOnRead) AddFollow(ctx context.Context, followerID string, followingID string) (err error) {
+	return
+}
+
+func (fanOutOnRead *fanOutOnRead) RemoveFollow(ctx context.Context, followerID string, followingID string) (err error) {
+	return
+}
+
+func (fanOutOnRead *fanOutOnRead) FindByUser(ctx context.Context, photos *[]domain.Photo, userID string, params pagination.Params) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = fanOutOnRead.db.WithContext(ctx).
+		Where("user_id = ? OR user_id IN (?)", userID, fanOutOnRead.db.Model(&domain.Follow{}).Select("following_id").Where("follower_id = ?", userID)).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "email")
+		}).Preload("Variants").Preload("Hashtags").Preload("Mentions", "comment_id IS NULL").Scopes(pagination.Scope(params, "created_at", "id")).Find(&photos).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+type fanOutOnWrite struct {
+	db            *gorm.DB
+	backfillLimit int
+}
+
+// NewFanOutOnWrite makes the feed strategy which copies every photo into the
+// feed of the author and of each follower when it is published, reads are a
+// single indexed range but a photo costs a row per follower. Following a user
+// backfills their last backfillLimit photos.
+func NewFanOutOnWrite(db *gorm.DB, backfillLimit int) *fanOutOnWrite {
+	return &fanOutOnWrite{db, backfillLimit}
+}
+
+func (fanOutOnWrite *fanOutOnWrite) AddPhoto(ctx context.Context, photo domain.Photo) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return fanOutOnWrite.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&domain.FeedItem{
+			UserID:         photo.UserID,
+			PhotoID:        photo.ID,
+			AuthorID:       photo.UserID,
+			PhotoCreatedAt: photo.CreatedAt,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Exec(
+			`INSERT INTO feed_items (user_id, photo_id, author_id, photo_created_at)
+			SELECT follower_id, ?, ?, ? FROM follows WHERE following_id = ?
+			ON CONFLICT DO NOTHING`,
+			photo.ID, photo.UserID, photo.CreatedAt, photo.UserID,
+		).Error; err != nil {
+			return err
+		}
+
+		return
+	})
+}
+
+func (fanOutOnWrite *fanOutOnWrite) AddFollow(ctx context.Context, followerID string, followingID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = fanOutOnWrite.db.WithContext(ctx).Exec(
+		`INSERT INTO feed_items (user_id, photo_id, author_id, photo_created_at)
+		SELECT ?, id, user_id, created_at FROM photos WHERE user_id = ?
+		ORDER BY created_at DESC LIMIT ?
+		ON CONFLICT DO NOTHING`,
+		followerID, followingID, fanOutOnWrite.backfillLimit,
+	).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (fanOutOnWrite *fanOutOnWrite) RemoveFollow(ctx context.Context, followerID string, followingID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = fanOutOnWrite.db.WithContext(ctx).Where("user_id = ? AND author_id = ?", followerID, followingID).Delete(&domain.FeedItem{}).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (fanOutOnWrite *fanOutOnWrite) FindByUser(ctx context.Context, photos *[]domain.Photo, userID string, params pagination.Params) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var items []domain.FeedItem
+
+	if err = fanOutOnWrite.db.WithContext(ctx).Where("user_id = ?", userID).Preload("Photo").Preload("Photo.User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email")
+	}).Preload("Photo.Variants").Preload("Photo.Hashtags").Preload("Photo.Mentions", "comment_id IS NULL").Scopes(pagination.Scope(params, "photo_created_at", "photo_id")).Find(&items).Error; err != nil {
+		return err
+	}
+
+	*photos = make([]domain.Photo, 0, len(items))
+
+	for _, item := range items {
+		if item.Photo != nil {
+			*photos = append(*photos, *item.Photo)
+		}
+	}
+
+	return
+}
@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+type feedUseCase struct {
+	feedStrategy domain.FeedStrategy
+}
+
+func NewFeedUseCase(feedStrategy domain.FeedStrategy) *feedUseCase {
+	return &feedUseCase{feedStrategy}
+}
+
+func (feedUseCase *feedUseCase) FindByUser(ctx context.Context, photos *[]domain.Photo, userID string, params pagination.Params) (err error) {
+	if err = feedUseCase.feedStrategy.FindByUser(ctx, photos, userID, params); err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,194 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+)
+
+type followHandler struct {
+	followUseCase domain.FollowUseCase
+}
+
+func NewFollowHandler(routers *gin.Engine, followUseCase domain.FollowUseCase) *followHandler {
+	handler := &followHandler{followUseCase}
+
+	router := routers.Group("/api/v1/users/:username")
+	{
+		router.POST("/follow", middleware.Authentication(), middleware.SessionOnly(), handler.Follow)
+		router.DELETE("/follow", middleware.Authentication(), middleware.SessionOnly(), handler.Unfollow)
+		router.GET("/followers", handler.GetFollowers)
+		router.GET("/following", handler.GetFollowing)
+	}
+
+	return handler
+}
+
+// Follow godoc
+// @Summary			Follow a user
+// @Description		Make the authentication user follow the user with the username
+// @Tags			follow
+// @Produce			json
+// @Param			username	path			string	true	"Username"
+// @Success			201			{object}		domain.Followed
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Failure			409			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/users/{username}/follow		[post]
+func (handler *followHandler) Follow(ctx *gin.Context) {
+	username := ctx.Param("username")
+
+	if _, err := handler.followUseCase.Follow(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, username); err != nil {
+		handler.abort(ctx, username, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, domain.Followed{
+		Status:  "success",
+		Message: fmt.Sprintf("you now follow %s", username),
+	})
+}
+
+// Unfollow godoc
+// @Summary			Unfollow a user
+// @Description		Make the authentication user stop following the user with the username
+// @Tags			follow
+// @Produce			json
+// @Param			username	path			string	true	"Username"
+// @Success			200			{object}		domain.Unfollowed
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/users/{username}/follow		[delete]
+func (handler *followHandler) Unfollow(ctx *gin.Context) {
+	username := ctx.Param("username")
+
+	if err := handler.followUseCase.Unfollow(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, username); err != nil {
+		handler.abort(ctx, username, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.Unfollowed{
+		Status:  "success",
+		Message: fmt.Sprintf("you no longer follow %s", username),
+	})
+}
+
+// GetFollowers godoc
+// @Summary			Get the followers of a user
+// @Description		Get a page of the users following the user with the username
+// @Tags			follow
+// @Produce			json
+// @Param			username	path			string	true	"Username"
+// @Param			cursor		query			string	false	"next_cursor of the previous page"
+// @Param			limit		query			int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query			string	false	"-created_at for the most recent first, by default, or created_at"
+// @Success			200			{object}		domain.FetchedFollows
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Router			/users/{username}/followers		[get]
+func (handler *followHandler) GetFollowers(ctx *gin.Context) {
+	handler.list(ctx, handler.followUseCase.FindFollowers, func(follow domain.Follow) *domain.User { return follow.Follower })
+}
+
+// GetFollowing godoc
+// @Summary			Get the users a user follows
+// @Description		Get a page of the users followed by the user with the username
+// @Tags			follow
+// @Produce			json
+// @Param			username	path			string	true	"Username"
+// @Param			cursor		query			string	false	"next_cursor of the previous page"
+// @Param			limit		query			int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query			string	false	"-created_at for the most recent first, by default, or created_at"
+// @Success			200			{object}		domain.FetchedFollows
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Router			/users/{username}/following		[get]
+func (handler *followHandler) GetFollowing(ctx *gin.Context) {
+	handler.list(ctx, handler.followUseCase.FindFollowing, func(follow domain.Follow) *domain.User { return follow.Following })
+}
+
+type findFollows func(ctx context.Context, follows *[]domain.Follow, username string, params pagination.Params) error
+
+func (handler *followHandler) list(ctx *gin.Context, find findFollows, userOf func(domain.Follow) *domain.User) {
+	var follows []domain.Follow
+
+	username := ctx.Param("username")
+
+	params, err := pagination.Parse(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = find(ctx.Request.Context(), &follows, username, params); err != nil {
+		handler.abort(ctx, username, err)
+		return
+	}
+
+	follows, meta := pagination.Trim(follows, params, func(follow domain.Follow) (*time.Time, string) { return follow.CreatedAt, follow.ID })
+
+	fetched := domain.FetchedFollows{
+		Status:  "success",
+		Message: "the users have been successfully fetched",
+		Data:    make([]domain.GetFollowUser, 0, len(follows)),
+		Meta:    meta,
+	}
+
+	for _, follow := range follows {
+		user := userOf(follow)
+		if user == nil {
+			continue
+		}
+
+		fetched.Data = append(fetched.Data, domain.GetFollowUser{
+			ID:          user.ID,
+			Username:    user.Username,
+			DisplayName: user.DisplayName,
+			AvatarURL:   user.AvatarURL,
+			FollowedAt:  follow.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, fetched)
+}
+
+func (handler *followHandler) abort(ctx *gin.Context, username string, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: fmt.Sprintf("user with username %s doesn't exist", username),
+		})
+	case errors.Is(err, domain.ErrNotFollowing):
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+	case errors.Is(err, domain.ErrAlreadyFollowing):
+		ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+	default:
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type followRepository struct {
+	db *gorm.DB
+}
+
+func NewFollowRepository(db *gorm.DB) *followRepository {
+	return &followRepository{db}
+}
+
+// Save stores the follow and counts it on both users in one transaction.
+func (followRepository *followRepository) Save(ctx context.Context, follow *domain.Follow) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	follow.ID = fmt.Sprintf("follow-%s", ID)
+
+	return followRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Create(&follow).Error; err != nil {
+			if strings.Contains(err.Error(), "idx_follows_follower_following") {
+				return domain.ErrAlreadyFollowing
+			}
+			return err
+		}
+
+		if err = tx.Model(&domain.User{}).Where("id = ?", follow.FollowerID).UpdateColumn("following_count", gorm.Expr("following_count + 1")).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Model(&domain.User{}).Where("id = ?", follow.FollowingID).UpdateColumn("follower_count", gorm.Expr("follower_count + 1")).Error; err != nil {
+			return err
+		}
+
+		return
+	})
+}
+
+// Delete removes the follow of followerID on followingID and uncounts it on
+// both users in one transaction.
+func (followRepository *followRepository) Delete(ctx context.Context, followerID string, followingID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return followRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		result := tx.Where("follower_id = ? AND following_id = ?", followerID, followingID).Delete(&domain.Follow{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrNotFollowing
+		}
+
+		if err = tx.Model(&domain.User{}).Where("id = ?", followerID).UpdateColumn("following_count", gorm.Expr("following_count - 1")).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Model(&domain.User{}).Where("id = ?", followingID).UpdateColumn("follower_count", gorm.Expr("follower_count - 1")).Error; err != nil {
+			return err
+		}
+
+		return
+	})
+}
+
+// IsFollowing tells whether the follower follows the user.
+func (followRepository *followRepository) IsFollowing(ctx context.Context, followerID string, followingID string) (following bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var count int64
+
+	if err = followRepository.db.WithContext(ctx).Model(&domain.Follow{}).Where("follower_id = ? AND following_id = ?", followerID, followingID).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// FindFollowers finds a page of the follows on the user with their
+// follower.
+func (followRepository *followRepository) FindFollowers(ctx context.Context, follows *[]domain.Follow, userID string, params pagination.Params) (err error) {
+	return followRepository.find(ctx, follows, "following_id", "Follower", userID, params)
+}
+
+// FindFollowing finds a page of the follows of the user with the followed
+// user.
+func (followRepository *followRepository) FindFollowing(ctx context.Context, follows *[]domain.Follow, userID string, params pagination.Params) (err error) {
+	return followRepository.find(ctx, follows, "follower_id", "Following", userID, params)
+}
+
+func (followRepository *followRepository) find(ctx context.Context, follows *[]domain.Follow, column string, preload string, userID string, params pagination.Params) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = followRepository.db.WithContext(ctx).Where(column+" = ?", userID).Preload(preload, func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "avatar_url")
+	}).Scopes(pagination.Scope(params, "created_at", "id")).Find(&follows).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+type followUseCase struct {
+	followRepository domain.FollowRepository
+	userRepository   domain.UserRepository
+	feedStrategy     domain.FeedStrategy
+}
+
+func NewFollowUseCase(followRepository domain.FollowRepository, userRepository domain.UserRepository, feedStrategy domain.FeedStrategy) *followUseCase {
+	return &followUseCase{followRepository, userRepository, feedStrategy}
+}
+
+// Follow makes the user of followerID follow the user with the username.
+func (followUseCase *followUseCase) Follow(ctx context.Context, followerID string, username string) (follow domain.Follow, err error) {
+	var user domain.User
+
+	if user, err = followUseCase.userRepository.FindByUsername(ctx, &domain.User{Username: username}); err != nil {
+		return follow, err
+	}
+
+	if user.ID == followerID {
+		return follow, domain.ErrSelfFollow
+	}
+
+	follow = domain.Follow{FollowerID: followerID, FollowingID: user.ID}
+
+	if err = followUseCase.followRepository.Save(ctx, &follow); err != nil {
+		return follow, err
+	}
+
+	// the follow is stored, a feed missing some photos is no reason to fail it
+	if err := followUseCase.feedStrategy.AddFollow(ctx, followerID, user.ID); err != nil {
+		log.Println("Error adding the photos of the followed user to the feed: ", err)
+	}
+
+	return follow, nil
+}
+
+func (followUseCase *followUseCase) Unfollow(ctx context.Context, followerID string, username string) (err error) {
+	var user domain.User
+
+	if user, err = followUseCase.userRepository.FindByUsername(ctx, &domain.User{Username: username}); err != nil {
+		return err
+	}
+
+	if user.ID == followerID {
+		return domain.ErrSelfFollow
+	}
+
+	if err = followUseCase.followRepository.Delete(ctx, followerID, user.ID); err != nil {
+		return err
+	}
+
+	if err := followUseCase.feedStrategy.RemoveFollow(ctx, followerID, user.ID); err != nil {
+		log.Println("Error removing the photos of the unfollowed user from the feed: ", err)
+	}
+
+	return
+}
+
+func (followUseCase *followUseCase) FindFollowers(ctx context.Context, follows *[]domain.Follow, username string, params pagination.Params) (err error) {
+	var user domain.User
+
+	if user, err = followUseCase.userRepository.FindByUsername(ctx, &domain.User{Username: username}); err != nil {
+		return err
+	}
+
+	if err = followUseCase.followRepository.FindFollowers(ctx, follows, user.ID, params); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (followUseCase *followUseCase) FindFollowing(ctx context.Context, follows *[]domain.Follow, username string, params pagination.Params) (err error) {
+	var user domain.User
+
+	if user, err = followUseCase.userRepository.FindByUsername(ctx, &domain.User{Username: username}); err != nil {
+		return err
+	}
+
+	if err = followUseCase.followRepository.FindFollowing(ctx, follows, user.ID, params); err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,172 @@
+package delivery
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+)
+
+type hashtagHandler struct {
+	hashtagUseCase domain.HashtagUseCase
+	likeUseCase    domain.LikeUseCase
+}
+
+func NewHashtagHandler(routers *gin.Engine, hashtagUseCase domain.HashtagUseCase, likeUseCase domain.LikeUseCase) *hashtagHandler {
+	handler := &hashtagHandler{hashtagUseCase, likeUseCase}
+
+	router := routers.Group("/api/v1/tags")
+	{
+		router.Use(middleware.Authentication())
+		router.GET("", middleware.RequireScope(domain.ScopePhotosRead), handler.Search)
+		router.GET("/:tag/photos", middleware.RequireScope(domain.ScopePhotosRead), handler.GetPhotos)
+	}
+
+	return handler
+}
+
+// Search godoc
+// @Summary			Search hashtags
+// @Description		Complete a hashtag being written with the hashtags starting with it, the most used first, or get the most used hashtags without q
+// @Tags			hashtag
+// @Produce			json
+// @Param			q			query			string	false	"The start of the hashtag, with or without its #"
+// @Param			limit		query			int		false	"How many hashtags, 10 by default and 50 at most"
+// @Success			200			{object}		domain.SearchedHashtags
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Security		ApiKey
+// @Router			/tags		[get]
+func (handler *hashtagHandler) Search(ctx *gin.Context) {
+	usages := []domain.HashtagUsage{}
+
+	limit := defaultSearchLimit
+	if value := ctx.Query("limit"); value != "" {
+		var err error
+
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxSearchLimit {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("the limit must be a number from 1 to %d", maxSearchLimit),
+			})
+			return
+		}
+	}
+
+	if err := handler.hashtagUseCase.Search(ctx.Request.Context(), &usages, ctx.Query("q"), limit); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.SearchedHashtags{
+		Status:  "success",
+		Message: "the hashtags have been successfully searched",
+		Data:    usages,
+	})
+}
+
+// GetPhotos godoc
+// @Summary			Get the photos of a hashtag
+// @Description		Get a page of the photos with the hashtag in their caption, the most recent first
+// @Tags			hashtag
+// @Produce			json
+// @Param			tag			path			string	true	"Hashtag, without its #"
+// @Param			cursor		query			string	false	"next_cursor of the previous page"
+// @Param			limit		query			int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query			string	false	"-created_at for the most recent first, by default, or created_at"
+// @Success			200			{object}		domain.GetAllPhotos
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Security		ApiKey
+// @Router			/tags/{tag}/photos		[get]
+func (handler *hashtagHandler) GetPhotos(ctx *gin.Context) {
+	var photos []domain.Photo
+
+	params, err := pagination.Parse(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.hashtagUseCase.FindPhotos(ctx.Request.Context(), &photos, ctx.Param("tag"), params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	photos, meta := pagination.Trim(photos, params, func(photo domain.Photo) (*time.Time, string) { return photo.CreatedAt, photo.ID })
+
+	photoIDs := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		photoIDs = append(photoIDs, photo.ID)
+	}
+
+	liked, err := handler.likeUseCase.FindLikedPhotoIDs(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, photoIDs)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fetched := domain.GetAllPhotos{
+		Status:  "success",
+		Message: "get all photos by hashtag",
+		Data:    make([]*domain.GetDetailPhoto, 0, len(photos)),
+		Meta:    meta,
+	}
+
+	for _, photo := range photos {
+		detail := &domain.GetDetailPhoto{
+			ID:              photo.ID,
+			Title:           photo.Title,
+			Caption:         photo.Caption,
+			PhotoUrl:        photo.PhotoUrl,
+			Status:          photo.Status,
+			Variants:        photo.VariantURLs(),
+			Hashtags:        photo.HashtagNames(),
+			Mentions:        photo.Mentions,
+			Metadata:        photo.CameraMetadata(),
+			LikeCount:       photo.LikeCount,
+			Liked:           liked[photo.ID],
+			CommentPolicy:   photo.CommentPolicy,
+			PinnedCommentID: photo.PinnedCommentID,
+			CreatedAt:       photo.CreatedAt,
+			UpdatedAt:       photo.UpdatedAt,
+		}
+
+		if photo.User != nil {
+			detail.User = &domain.GetUser{
+				ID:       photo.User.ID,
+				Email:    photo.User.Email,
+				Username: photo.User.Username,
+			}
+		}
+
+		fetched.Data = append(fetched.Data, detail)
+	}
+
+	ctx.JSON(http.StatusOK, fetched)
+}
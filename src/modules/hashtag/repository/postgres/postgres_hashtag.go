@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+)
+
+type hashtagRepository struct {
+	db *gorm.DB
+}
+
+func NewHashtagRepository(db *gorm.DB) *hashtagRepository {
+	return &hashtagRepository{db}
+}
+
+func (hashtagRepository *hashtagRepository) FindPhotos(ctx context.Context, photos *[]domain.Photo, name string, params pagination.Params) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = hashtagRepository.db.WithContext(ctx).
+		Joins("JOIN photo_hashtags ON photo_hashtags.photo_id = photos.id").
+		Where("photo_hashtags.hashtag_name = ?", name).
+		Preload("User", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id", "username", "email")
+		}).Preload("Variants").Preload("Hashtags").Preload("Mentions", "comment_id IS NULL").Scopes(pagination.Scope(params, "photos.created_at", "photos.id")).Find(&photos).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Search finds the hashtags starting with the prefix, all of them if it's
+// empty, the most used first.
+func (hashtagRepository *hashtagRepository) Search(ctx context.Context, usages *[]domain.HashtagUsage, prefix string, limit int) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// _ is a hashtag character but a LIKE wildcard
+	pattern := strings.ReplaceAll(prefix, "_", `\_`) + "%"
+
+	if err = hashtagRepository.db.WithContext(ctx).Table("photo_hashtags").
+		Select("hashtag_name AS name, COUNT(*) AS photo_count").
+		Where("hashtag_name LIKE ?", pattern).
+		Group("hashtag_name").
+		Order("photo_count DESC, name").
+		Limit(limit).
+		Scan(usages).Error; err != nil {
+		return err
+	}
+
+	return
+}
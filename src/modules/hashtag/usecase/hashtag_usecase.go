@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/gusrylmubarok/mygram-backend/src/textparse"
+)
+
+type hashtagUseCase struct {
+	hashtagRepository domain.HashtagRepository
+}
+
+func NewHashtagUseCase(hashtagRepository domain.HashtagRepository) *hashtagUseCase {
+	return &hashtagUseCase{hashtagRepository}
+}
+
+// FindPhotos finds the photos with the hashtag, written with or without its
+// # and in any case.
+func (hashtagUseCase *hashtagUseCase) FindPhotos(ctx context.Context, photos *[]domain.Photo, hashtag string, params pagination.Params) (err error) {
+	name, ok := textparse.NormalizeHashtag(hashtag)
+	if !ok {
+		return domain.ErrInvalidHashtag
+	}
+
+	if err = hashtagUseCase.hashtagRepository.FindPhotos(ctx, photos, name, params); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Search finds the hashtags starting with the query, with how many photos
+// have them, to complete the hashtag being written. The most used hashtags
+// are found for an empty query.
+func (hashtagUseCase *hashtagUseCase) Search(ctx context.Context, usages *[]domain.HashtagUsage, query string, limit int) (err error) {
+	prefix, ok := textparse.NormalizeHashtagPrefix(query)
+	if !ok {
+		return domain.ErrInvalidHashtag
+	}
+
+	if err = hashtagUseCase.hashtagRepository.Search(ctx, usages, prefix, limit); err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,163 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+)
+
+type likeHandler struct {
+	likeUseCase domain.LikeUseCase
+}
+
+func NewLikeHandler(routers *gin.Engine, likeUseCase domain.LikeUseCase) *likeHandler {
+	handler := &likeHandler{likeUseCase}
+
+	router := routers.Group("/api/v1/photo/:photoId")
+	{
+		router.Use(middleware.Authentication())
+		router.POST("/like", middleware.RequireScope(domain.ScopePhotosWrite), handler.Like)
+		router.DELETE("/like", middleware.RequireScope(domain.ScopePhotosWrite), handler.Unlike)
+		router.GET("/likes", middleware.RequireScope(domain.ScopePhotosRead), handler.GetLikes)
+	}
+
+	return handler
+}
+
+// Like godoc
+// @Summary			Like a photo
+// @Description		Make the authentication user like the photo, liking it again changes nothing
+// @Tags			like
+// @Produce			json
+// @Param			photoId		path			string	true	"Photo ID"
+// @Success			200			{object}		domain.Liked
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Security		ApiKey
+// @Router			/photo/{photoId}/like		[post]
+func (handler *likeHandler) Like(ctx *gin.Context) {
+	photoID := ctx.Param("photoId")
+
+	if err := handler.likeUseCase.Like(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, photoID); err != nil {
+		handler.abort(ctx, photoID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.Liked{
+		Status:  "success",
+		Message: "you like the photo",
+	})
+}
+
+// Unlike godoc
+// @Summary			Unlike a photo
+// @Description		Make the authentication user stop liking the photo, if they did
+// @Tags			like
+// @Produce			json
+// @Param			photoId		path			string	true	"Photo ID"
+// @Success			200			{object}		domain.Unliked
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Security		ApiKey
+// @Router			/photo/{photoId}/like		[delete]
+func (handler *likeHandler) Unlike(ctx *gin.Context) {
+	photoID := ctx.Param("photoId")
+
+	if err := handler.likeUseCase.Unlike(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, photoID); err != nil {
+		handler.abort(ctx, photoID, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.Unliked{
+		Status:  "success",
+		Message: "you no longer like the photo",
+	})
+}
+
+// GetLikes godoc
+// @Summary			Get the likes of a photo
+// @Description		Get a page of the users liking the photo
+// @Tags			like
+// @Produce			json
+// @Param			photoId		path			string	true	"Photo ID"
+// @Param			cursor		query			string	false	"next_cursor of the previous page"
+// @Param			limit		query			int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query			string	false	"-created_at for the most recent first, by default, or created_at"
+// @Success			200			{object}		domain.FetchedLikes
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Security		ApiKey
+// @Router			/photo/{photoId}/likes		[get]
+func (handler *likeHandler) GetLikes(ctx *gin.Context) {
+	var likes []domain.Like
+
+	photoID := ctx.Param("photoId")
+
+	params, err := pagination.Parse(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.likeUseCase.FindByPhoto(ctx.Request.Context(), &likes, photoID, params); err != nil {
+		handler.abort(ctx, photoID, err)
+		return
+	}
+
+	likes, meta := pagination.Trim(likes, params, func(like domain.Like) (*time.Time, string) { return like.CreatedAt, like.ID })
+
+	fetched := domain.FetchedLikes{
+		Status:  "success",
+		Message: "the likes have been successfully fetched",
+		Data:    make([]domain.GetLikeUser, 0, len(likes)),
+		Meta:    meta,
+	}
+
+	for _, like := range likes {
+		if like.User == nil {
+			continue
+		}
+
+		fetched.Data = append(fetched.Data, domain.GetLikeUser{
+			ID:          like.User.ID,
+			Username:    like.User.Username,
+			DisplayName: like.User.DisplayName,
+			AvatarURL:   like.User.AvatarURL,
+			LikedAt:     like.CreatedAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, fetched)
+}
+
+func (handler *likeHandler) abort(ctx *gin.Context, photoID string, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: fmt.Sprintf("photo with id %s doesn't exist", photoID),
+		})
+		return
+	}
+
+	ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+		Status:  "fail",
+		Message: err.Error(),
+	})
+}
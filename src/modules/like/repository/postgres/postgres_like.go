@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type likeRepository struct {
+	db *gorm.DB
+}
+
+func NewLikeRepository(db *gorm.DB) *likeRepository {
+	return &likeRepository{db}
+}
+
+// Save stores the like and counts it on the photo in one transaction. Liking
+// a photo again changes nothing.
+func (likeRepository *likeRepository) Save(ctx context.Context, like *domain.Like) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	like.ID = fmt.Sprintf("like-%s", ID)
+
+	return likeRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return
+		}
+
+		if err = tx.Model(&domain.Photo{}).Where("id = ?", like.PhotoID).UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error; err != nil {
+			return err
+		}
+
+		return
+	})
+}
+
+// Delete removes the like of the user on the photo and uncounts it in one
+// transaction. Unliking a photo that isn't liked changes nothing.
+func (likeRepository *likeRepository) Delete(ctx context.Context, userID string, photoID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return likeRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		result := tx.Where("user_id = ? AND photo_id = ?", userID, photoID).Delete(&domain.Like{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return
+		}
+
+		if err = tx.Model(&domain.Photo{}).Where("id = ?", photoID).UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error; err != nil {
+			return err
+		}
+
+		return
+	})
+}
+
+// FindByPhoto finds a page of the likes on the photo with their user.
+func (likeRepository *likeRepository) FindByPhoto(ctx context.Context, likes *[]domain.Like, photoID string, params pagination.Params) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = likeRepository.db.WithContext(ctx).Where("photo_id = ?", photoID).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "avatar_url")
+	}).Scopes(pagination.Scope(params, "created_at", "id")).Find(&likes).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// FindLikedPhotoIDs tells which of the photos the user likes, in one query
+// for a whole page of photos.
+func (likeRepository *likeRepository) FindLikedPhotoIDs(ctx context.Context, userID string, photoIDs []string) (liked map[string]bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	liked = make(map[string]bool, len(photoIDs))
+
+	if userID == "" || len(photoIDs) == 0 {
+		return liked, nil
+	}
+
+	var ids []string
+
+	if err = likeRepository.db.WithContext(ctx).Model(&domain.Like{}).Where("user_id = ? AND photo_id IN ?", userID, photoIDs).Pluck("photo_id", &ids).Error; err != nil {
+		return liked, err
+	}
+
+	for _, id := range ids {
+		liked[id] = true
+	}
+
+	return liked, nil
+}
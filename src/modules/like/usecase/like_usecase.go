@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+type likeUseCase struct {
+	likeRepository  domain.LikeRepository
+	photoRepository domain.PhotoRepository
+}
+
+func NewLikeUseCase(likeRepository domain.LikeRepository, photoRepository domain.PhotoRepository) *likeUseCase {
+	return &likeUseCase{likeRepository, photoRepository}
+}
+
+// Like makes the user like the photo, liking it twice is the same as once.
+func (likeUseCase *likeUseCase) Like(ctx context.Context, userID string, photoID string) (err error) {
+	if err = likeUseCase.photoRepository.FindById(ctx, &domain.Photo{}, photoID); err != nil {
+		return err
+	}
+
+	if err = likeUseCase.likeRepository.Save(ctx, &domain.Like{UserID: userID, PhotoID: photoID}); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Unlike makes the user stop liking the photo, if they did.
+func (likeUseCase *likeUseCase) Unlike(ctx context.Context, userID string, photoID string) (err error) {
+	if err = likeUseCase.photoRepository.FindById(ctx, &domain.Photo{}, photoID); err != nil {
+		return err
+	}
+
+	if err = likeUseCase.likeRepository.Delete(ctx, userID, photoID); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (likeUseCase *likeUseCase) FindByPhoto(ctx context.Context, likes *[]domain.Like, photoID string, params pagination.Params) (err error) {
+	if err = likeUseCase.photoRepository.FindById(ctx, &domain.Photo{}, photoID); err != nil {
+		return err
+	}
+
+	if err = likeUseCase.likeRepository.FindByPhoto(ctx, likes, photoID, params); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (likeUseCase *likeUseCase) FindLikedPhotoIDs(ctx context.Context, userID string, photoIDs []string) (liked map[string]bool, err error) {
+	if liked, err = likeUseCase.likeRepository.FindLikedPhotoIDs(ctx, userID, photoIDs); err != nil {
+		return liked, err
+	}
+
+	return liked, nil
+}
@@ -0,0 +1,117 @@
+package delivery
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+)
+
+type loginAttemptHandler struct {
+	loginAttemptUseCase domain.LoginAttemptUseCase
+	userUseCase         domain.UserUseCase
+}
+
+func NewLoginAttemptHandler(routers *gin.Engine, loginAttemptUseCase domain.LoginAttemptUseCase, userUseCase domain.UserUseCase) *loginAttemptHandler {
+	handler := &loginAttemptHandler{loginAttemptUseCase, userUseCase}
+
+	router := routers.Group("/api/v1/admin/user")
+	{
+		router.Use(middleware.Authentication(), middleware.SessionOnly(), middleware.RequireRole(domain.RoleAdmin))
+		router.GET("/:userId/lock", handler.Status)
+		router.DELETE("/:userId/lock", handler.Unlock)
+	}
+
+	return handler
+}
+
+// Status godoc
+// @Summary			Get the lock status of a user
+// @Description		Get the failed logins and the lock of a user, admin only
+// @Tags			admin
+// @Produce			json
+// @Param			userId	path			string	true	"User ID"
+// @Success			200		{object}		domain.LoginLockStatusResponse
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Failure			404		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/admin/user/{userId}/lock		[get]
+func (handler *loginAttemptHandler) Status(ctx *gin.Context) {
+	var (
+		user   domain.User
+		status domain.LoginLockStatus
+		err    error
+	)
+
+	userID := ctx.Param("userId")
+
+	if user, err = handler.userUseCase.FindById(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: fmt.Sprintf("user with id %s doesn't exist", userID),
+		})
+		return
+	}
+
+	if status, err = handler.loginAttemptUseCase.Status(ctx.Request.Context(), user.Email); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.LoginLockStatusResponse{
+		Status:  "success",
+		Message: "lock status has been fetched",
+		Data:    status,
+	})
+}
+
+// Unlock godoc
+// @Summary			Unlock a user
+// @Description		Clear the failed logins and the lock of a user, admin only
+// @Tags			admin
+// @Produce			json
+// @Param			userId	path			string	true	"User ID"
+// @Success			200		{object}		domain.UnlockedUser
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Failure			404		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/admin/user/{userId}/lock		[delete]
+func (handler *loginAttemptHandler) Unlock(ctx *gin.Context) {
+	var (
+		user domain.User
+		err  error
+	)
+
+	userID := ctx.Param("userId")
+
+	if user, err = handler.userUseCase.FindById(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: fmt.Sprintf("user with id %s doesn't exist", userID),
+		})
+		return
+	}
+
+	if err = handler.loginAttemptUseCase.Unlock(ctx.Request.Context(), user.Email); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.UnlockedUser{
+		Status:  "success",
+		Message: "the user has been unlocked",
+	})
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+)
+
+type loginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepository(db *gorm.DB) *loginThrottleRepository {
+	return &loginThrottleRepository{db}
+}
+
+func (loginThrottleRepository *loginThrottleRepository) Find(ctx context.Context, loginThrottle *domain.LoginThrottle, subject string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = loginThrottleRepository.db.WithContext(ctx).First(&loginThrottle, "subject = ?", subject).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// RecordFailure counts a failed login of the subject, starting over when the
+// previous failure is older than window.
+func (loginThrottleRepository *loginThrottleRepository) RecordFailure(ctx context.Context, subject string, window time.Duration) (loginThrottle domain.LoginThrottle, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+
+	if err = loginThrottleRepository.db.WithContext(ctx).Raw(
+		`INSERT INTO login_throttles (subject, failures, last_failed_at, updated_at) VALUES (?, 1, ?, ?)
+		ON CONFLICT (subject) DO UPDATE SET
+			failures = CASE WHEN login_throttles.last_failed_at < ? THEN 1 ELSE login_throttles.failures + 1 END,
+			last_failed_at = EXCLUDED.last_failed_at,
+			updated_at = EXCLUDED.updated_at
+		RETURNING *`,
+		subject, now, now, now.Add(-window),
+	).Scan(&loginThrottle).Error; err != nil {
+		return loginThrottle, err
+	}
+
+	return loginThrottle, nil
+}
+
+func (loginThrottleRepository *loginThrottleRepository) LockUntil(ctx context.Context, subject string, until time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = loginThrottleRepository.db.WithContext(ctx).Model(&domain.LoginThrottle{}).Where("subject = ?", subject).Update("locked_until", until).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (loginThrottleRepository *loginThrottleRepository) Delete(ctx context.Context, subject string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = loginThrottleRepository.db.WithContext(ctx).Where("subject = ?", subject).Delete(&domain.LoginThrottle{}).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+)
+
+type loginAttemptUseCase struct {
+	loginThrottleRepository domain.LoginThrottleRepository
+	accountPolicy           domain.LoginThrottlePolicy
+	ipPolicy                domain.LoginThrottlePolicy
+}
+
+func NewLoginAttemptUseCase(loginThrottleRepository domain.LoginThrottleRepository, accountPolicy domain.LoginThrottlePolicy, ipPolicy domain.LoginThrottlePolicy) *loginAttemptUseCase {
+	return &loginAttemptUseCase{loginThrottleRepository, accountPolicy, ipPolicy}
+}
+
+// Check tells how long the login has to wait when the account or the client
+// is throttled. Accounts are keyed by email whether registered or not, so a
+// throttled login doesn't reveal whether the account exists.
+func (loginAttemptUseCase *loginAttemptUseCase) Check(ctx context.Context, email string, ip string) (retryAfter time.Duration, err error) {
+	now := time.Now()
+
+	for _, subject := range []string{accountSubject(email), ipSubject(ip)} {
+		var loginThrottle domain.LoginThrottle
+
+		if err = loginAttemptUseCase.loginThrottleRepository.Find(ctx, &loginThrottle, subject); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+
+			return 0, err
+		}
+
+		if loginThrottle.LockedUntil != nil && loginThrottle.LockedUntil.After(now) && loginThrottle.LockedUntil.Sub(now) > retryAfter {
+			retryAfter = loginThrottle.LockedUntil.Sub(now)
+		}
+	}
+
+	if retryAfter > 0 {
+		return retryAfter, domain.ErrTooManyLoginAttempts
+	}
+
+	return 0, nil
+}
+
+// Fail records a failed login of the account and the client.
+func (loginAttemptUseCase *loginAttemptUseCase) Fail(ctx context.Context, email string, ip string) (err error) {
+	if err = loginAttemptUseCase.fail(ctx, accountSubject(email), loginAttemptUseCase.accountPolicy); err != nil {
+		return err
+	}
+
+	return loginAttemptUseCase.fail(ctx, ipSubject(ip), loginAttemptUseCase.ipPolicy)
+}
+
+// Succeed forgets the failed logins of the account. Those of the client are
+// kept, otherwise logging in to an own account would reset them.
+func (loginAttemptUseCase *loginAttemptUseCase) Succeed(ctx context.Context, email string) (err error) {
+	return loginAttemptUseCase.loginThrottleRepository.Delete(ctx, accountSubject(email))
+}
+
+func (loginAttemptUseCase *loginAttemptUseCase) Status(ctx context.Context, email string) (status domain.LoginLockStatus, err error) {
+	var loginThrottle domain.LoginThrottle
+
+	if err = loginAttemptUseCase.loginThrottleRepository.Find(ctx, &loginThrottle, accountSubject(email)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return status, nil
+		}
+
+		return status, err
+	}
+
+	return domain.LoginLockStatus{
+		Locked:       loginThrottle.LockedUntil != nil && loginThrottle.LockedUntil.After(time.Now()),
+		Failures:     loginThrottle.Failures,
+		LastFailedAt: loginThrottle.LastFailedAt,
+		LockedUntil:  loginThrottle.LockedUntil,
+	}, nil
+}
+
+func (loginAttemptUseCase *loginAttemptUseCase) Unlock(ctx context.Context, email string) (err error) {
+	return loginAttemptUseCase.loginThrottleRepository.Delete(ctx, accountSubject(email))
+}
+
+func (loginAttemptUseCase *loginAttemptUseCase) fail(ctx context.Context, subject string, policy domain.LoginThrottlePolicy) (err error) {
+	var loginThrottle domain.LoginThrottle
+
+	if loginThrottle, err = loginAttemptUseCase.loginThrottleRepository.RecordFailure(ctx, subject, policy.Window); err != nil {
+		return err
+	}
+
+	wait := lockDuration(policy, loginThrottle.Failures)
+
+	if wait <= 0 {
+		return
+	}
+
+	return loginAttemptUseCase.loginThrottleRepository.LockUntil(ctx, subject, time.Now().Add(wait))
+}
+
+// lockDuration returns how long a subject with the number of failures has to
+// wait before its next login.
+func lockDuration(policy domain.LoginThrottlePolicy, failures uint) time.Duration {
+	if policy.LockThreshold > 0 && failures >= policy.LockThreshold {
+		return policy.LockDuration
+	}
+
+	if policy.BackoffAfter == 0 || failures < policy.BackoffAfter {
+		return 0
+	}
+
+	wait := policy.BackoffBase
+
+	for i := policy.BackoffAfter; i < failures && wait < policy.BackoffMax; i++ {
+		wait *= 2
+	}
+
+	if wait > policy.BackoffMax {
+		return policy.BackoffMax
+	}
+
+	return wait
+}
+
+func accountSubject(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipSubject(ip string) string {
+	return "ip:" + ip
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/textparse"
+	"gorm.io/gorm"
+)
+
+type mentionUseCase struct {
+	userRepository         domain.UserRepository
+	blockRepository        domain.BlockRepository
+	notificationRepository domain.NotificationRepository
+}
+
+func NewMentionUseCase(userRepository domain.UserRepository, blockRepository domain.BlockRepository, notificationRepository domain.NotificationRepository) *mentionUseCase {
+	return &mentionUseCase{userRepository, blockRepository, notificationRepository}
+}
+
+// Resolve finds the users mentioned by the author in the text. The mentions
+// of users who don't exist, or who block the author or are blocked by them,
+// are dropped, and so are all of them when that can't be told, a text is
+// never refused for its mentions.
+func (mentionUseCase *mentionUseCase) Resolve(ctx context.Context, authorID string, text string) (mentions []domain.Mention) {
+	written := textparse.Mentions(text)
+	if len(written) == 0 {
+		return nil
+	}
+
+	users := map[string]domain.User{}
+	userIDs := []string{}
+
+	for _, mention := range written {
+		if _, ok := users[mention.Username]; ok {
+			continue
+		}
+
+		user, err := mentionUseCase.userRepository.FindByUsername(ctx, &domain.User{Username: mention.Username})
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Println("Error finding a mentioned user: ", err)
+			}
+		} else {
+			userIDs = append(userIDs, user.ID)
+		}
+
+		users[mention.Username] = user
+	}
+
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	blocked, err := mentionUseCase.blockRepository.FindBlockedUserIDs(ctx, authorID, userIDs)
+	if err != nil {
+		log.Println("Error finding the blocks of the mentioned users: ", err)
+		return nil
+	}
+
+	for _, mention := range written {
+		user := users[mention.Username]
+		if user.ID == "" || blocked[user.ID] {
+			continue
+		}
+
+		mentions = append(mentions, domain.Mention{
+			UserID:   user.ID,
+			Username: user.Username,
+			Offset:   mention.Offset,
+			Length:   mention.Length,
+		})
+	}
+
+	return mentions
+}
+
+// Notify notifies each user of the mentions by the actor once, but the actor
+// and the users already in the previous mentions of the same text, so that
+// editing it only notifies the users newly mentioned.
+func (mentionUseCase *mentionUseCase) Notify(ctx context.Context, actorID string, mentions []domain.Mention, previous []domain.Mention) (err error) {
+	notified := map[string]bool{actorID: true}
+	for _, mention := range previous {
+		notified[mention.UserID] = true
+	}
+
+	var notifications []domain.Notification
+
+	for _, mention := range mentions {
+		if notified[mention.UserID] {
+			continue
+		}
+
+		notified[mention.UserID] = true
+		notifications = append(notifications, domain.Notification{
+			UserID:    mention.UserID,
+			ActorID:   actorID,
+			Type:      domain.NotificationTypeMention,
+			PhotoID:   mention.PhotoID,
+			CommentID: mention.CommentID,
+		})
+	}
+
+	if len(notifications) == 0 {
+		return
+	}
+
+	if err = mentionUseCase.notificationRepository.SaveAll(ctx, notifications); err != nil {
+		return err
+	}
+
+	return
+}
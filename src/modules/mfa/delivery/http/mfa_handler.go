@@ -0,0 +1,252 @@
+package delivery
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+)
+
+type mfaHandler struct {
+	mfaUseCase          domain.MFAUseCase
+	tokenUseCase        domain.TokenUseCase
+	loginAttemptUseCase domain.LoginAttemptUseCase
+}
+
+func NewMFAHandler(routers *gin.Engine, mfaUseCase domain.MFAUseCase, tokenUseCase domain.TokenUseCase, loginAttemptUseCase domain.LoginAttemptUseCase) *mfaHandler {
+	handler := &mfaHandler{mfaUseCase, tokenUseCase, loginAttemptUseCase}
+
+	routers.POST("/api/v1/user/login/mfa", handler.Verify)
+
+	router := routers.Group("/api/v1/user/mfa")
+	{
+		router.Use(middleware.Authentication(), middleware.SessionOnly())
+		router.POST("/enroll", handler.Enroll)
+		router.POST("/confirm", handler.Confirm)
+		router.POST("/disable", handler.Disable)
+	}
+
+	return handler
+}
+
+// Enroll godoc
+// @Summary			Enroll two-factor authentication
+// @Description		Create a TOTP secret for the authentication user, it is enabled once confirmed with a code
+// @Tags			user
+// @Produce			json
+// @Success			200		{object}		domain.EnrolledMFA
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			409		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/mfa/enroll		[post]
+func (handler *mfaHandler) Enroll(ctx *gin.Context) {
+	var (
+		enrollment domain.MFAEnrollment
+		err        error
+	)
+
+	principal := middleware.CurrentPrincipal(ctx)
+
+	if enrollment, err = handler.mfaUseCase.Enroll(ctx.Request.Context(), domain.User{ID: principal.UserID, Email: principal.Email}); err != nil {
+		if errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.EnrolledMFA{
+		Status:  "success",
+		Message: "add the secret to your authenticator app and confirm it with a code",
+		Data:    enrollment,
+	})
+}
+
+// Confirm godoc
+// @Summary			Confirm two-factor authentication
+// @Description		Enable two-factor authentication with a first code and retrieve the recovery codes
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.MFACode	true	"MFA Code"
+// @Success			200		{object}		domain.ConfirmedMFA
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			409		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/mfa/confirm		[post]
+func (handler *mfaHandler) Confirm(ctx *gin.Context) {
+	var (
+		input         domain.MFACode
+		recoveryCodes []string
+		err           error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if recoveryCodes, err = handler.mfaUseCase.Confirm(ctx.Request.Context(), userID, input.Code); err != nil {
+		if errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+			ctx.AbortWithStatusJSON(http.StatusConflict, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.ConfirmedMFA{
+		Status:  "success",
+		Message: "two-factor authentication has been enabled, keep the recovery codes somewhere safe",
+		Data:    recoveryCodes,
+	})
+}
+
+// Disable godoc
+// @Summary			Disable two-factor authentication
+// @Description		Disable two-factor authentication with a code of the authenticator app or a recovery code
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.MFACode	true	"MFA Code"
+// @Success			200		{object}		domain.DisabledMFA
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			429		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/mfa/disable		[post]
+func (handler *mfaHandler) Disable(ctx *gin.Context) {
+	var (
+		input domain.MFACode
+		err   error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.mfaUseCase.Disable(ctx.Request.Context(), userID, input.Code); err != nil {
+		if errors.Is(err, domain.ErrTooManyMFAAttempts) {
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.DisabledMFA{
+		Status:  "success",
+		Message: "two-factor authentication has been disabled",
+	})
+}
+
+// Verify godoc
+// @Summary			Complete a two-factor login
+// @Description		Exchange the challenge token of a login and a code of the authenticator app or a recovery code for a token. A challenge completes a single login, after too many wrong codes of the user the codes are locked for a while and the login has to start again
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.VerifyMFA	true	"Verify MFA"
+// @Success			200		{object}		domain.LoggedInUser
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			429		{object}		helpers.ResponseMessage
+// @Router			/user/login/mfa		[post]
+func (handler *mfaHandler) Verify(ctx *gin.Context) {
+	var (
+		input domain.VerifyMFA
+		user  domain.User
+		token domain.Token
+		err   error
+	)
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if user, err = handler.mfaUseCase.Verify(ctx.Request.Context(), input.ChallengeToken, input.Code); err != nil {
+		if errors.Is(err, domain.ErrTooManyMFAAttempts) {
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		if errors.Is(err, domain.ErrInvalidMFAChallenge) || errors.Is(err, domain.ErrInvalidMFACode) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// the failed logins of the account are forgotten once both factors passed
+	if err = handler.loginAttemptUseCase.Succeed(ctx.Request.Context(), user.Email); err != nil {
+		log.Println("Error clearing failed logins: ", err)
+	}
+
+	if token, err = handler.tokenUseCase.Issue(ctx.Request.Context(), user, middleware.Client(ctx)); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "unauthenticated",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.LoggedInUser{
+		Status:  "success",
+		Message: "user login has beed successful",
+		Data:    token,
+	})
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type mfaRepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *mfaRepository {
+	return &mfaRepository{db}
+}
+
+// Save stores a pending enrollment, replacing the previous pending one. An
+// enabled secret is never replaced.
+func (mfaRepository *mfaRepository) Save(ctx context.Context, userMFA *domain.UserMFA) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := mfaRepository.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_counter", "updated_at"}),
+		Where:     clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "user_mfas.enabled_at IS NULL"}}},
+	}).Create(&userMFA)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+
+	return
+}
+
+func (mfaRepository *mfaRepository) FindByUserID(ctx context.Context, userMFA *domain.UserMFA, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = mfaRepository.db.WithContext(ctx).First(&userMFA, "user_id = ?", userID).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Enable turns two-factor authentication on with the time step of the
+// confirmation code as the last used one, and replaces the recovery codes.
+func (mfaRepository *mfaRepository) Enable(ctx context.Context, userMFA domain.UserMFA, counter int64, recoveryCodeHashes []string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return mfaRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&domain.UserMFA{}).Where("user_id = ? AND enabled_at IS NULL", userMFA.UserID).Updates(map[string]interface{}{
+			"enabled_at":        now,
+			"last_used_counter": counter,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrMFAAlreadyEnabled
+		}
+
+		if err := tx.Where("user_id = ?", userMFA.UserID).Delete(&domain.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		recoveryCodes := make([]domain.MFARecoveryCode, 0, len(recoveryCodeHashes))
+
+		for _, codeHash := range recoveryCodeHashes {
+			ID, _ := gonanoid.New(16)
+
+			recoveryCodes = append(recoveryCodes, domain.MFARecoveryCode{
+				ID:       fmt.Sprintf("mfarecoverycode-%s", ID),
+				UserID:   userMFA.UserID,
+				CodeHash: codeHash,
+			})
+		}
+
+		return tx.Create(&recoveryCodes).Error
+	})
+}
+
+// UseCounter records the time step of an accepted code. A time step which
+// isn't newer than the last one is a replayed code and fails.
+func (mfaRepository *mfaRepository) UseCounter(ctx context.Context, userID string, counter int64) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := mfaRepository.db.WithContext(ctx).Model(&domain.UserMFA{}).Where("user_id = ? AND last_used_counter < ?", userID, counter).Update("last_used_counter", counter)
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return
+}
+
+func (mfaRepository *mfaRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := mfaRepository.db.WithContext(ctx).Model(&domain.MFARecoveryCode{}).Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).Update("used_at", time.Now())
+
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidMFACode
+	}
+
+	return
+}
+
+func (mfaRepository *mfaRepository) Delete(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return mfaRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&domain.UserMFA{}).Error
+	})
+}
@@ -0,0 +1,294 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+)
+
+type mfaUseCase struct {
+	mfaRepository           domain.MFARepository
+	userRepository          domain.UserRepository
+	loginThrottleRepository domain.LoginThrottleRepository
+	revocationStore         domain.RevocationStore
+	tokenSigner             domain.TokenSigner
+	issuer                  string
+	challengeTTL            time.Duration
+	maxAttempts             uint
+	lockDuration            time.Duration
+}
+
+func NewMFAUseCase(mfaRepository domain.MFARepository, userRepository domain.UserRepository, loginThrottleRepository domain.LoginThrottleRepository, revocationStore domain.RevocationStore, tokenSigner domain.TokenSigner, issuer string, challengeTTL time.Duration, maxAttempts uint, lockDuration time.Duration) *mfaUseCase {
+	return &mfaUseCase{mfaRepository, userRepository, loginThrottleRepository, revocationStore, tokenSigner, issuer, challengeTTL, maxAttempts, lockDuration}
+}
+
+// Enroll creates a new secret for the user, it has no effect until Confirm is
+// called with a code of it.
+func (mfaUseCase *mfaUseCase) Enroll(ctx context.Context, user domain.User) (enrollment domain.MFAEnrollment, err error) {
+	var secret string
+
+	if secret, err = helpers.GenerateTOTPSecret(); err != nil {
+		return enrollment, err
+	}
+
+	if err = mfaUseCase.mfaRepository.Save(ctx, &domain.UserMFA{UserID: user.ID, Secret: secret}); err != nil {
+		return enrollment, err
+	}
+
+	return domain.MFAEnrollment{
+		Secret:     secret,
+		OTPAuthURI: helpers.TOTPURI(mfaUseCase.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm enables two-factor authentication when the code matches the pending
+// secret and returns the recovery codes, they are never shown again.
+func (mfaUseCase *mfaUseCase) Confirm(ctx context.Context, userID string, code string) (recoveryCodes []string, err error) {
+	var userMFA domain.UserMFA
+
+	if err = mfaUseCase.mfaRepository.FindByUserID(ctx, &userMFA, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrMFANotEnrolled
+		}
+
+		return nil, err
+	}
+
+	if userMFA.EnabledAt != nil {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	counter, ok := helpers.ValidateTOTP(userMFA.Secret, code, time.Now())
+
+	if !ok {
+		return nil, domain.ErrInvalidMFACode
+	}
+
+	recoveryCodeHashes := make([]string, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		var recoveryCode string
+
+		if recoveryCode, err = gonanoid.Generate(recoveryCodeAlphabet, 10); err != nil {
+			return nil, err
+		}
+
+		recoveryCode = recoveryCode[:5] + "-" + recoveryCode[5:]
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		recoveryCodeHashes = append(recoveryCodeHashes, helpers.HashToken(recoveryCode))
+	}
+
+	if err = mfaUseCase.mfaRepository.Enable(ctx, userMFA, counter, recoveryCodeHashes); err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+// Disable turns two-factor authentication off, it takes a TOTP or recovery
+// code so a stolen session alone can't do it. Its wrong codes count along
+// with those of the logins of the user.
+func (mfaUseCase *mfaUseCase) Disable(ctx context.Context, userID string, code string) (err error) {
+	var userMFA domain.UserMFA
+
+	if err = mfaUseCase.mfaRepository.FindByUserID(ctx, &userMFA, userID); err != nil || userMFA.EnabledAt == nil {
+		return domain.ErrMFANotEnabled
+	}
+
+	if err = mfaUseCase.checkLocked(ctx, userID); err != nil {
+		return err
+	}
+
+	if err = mfaUseCase.checkCode(ctx, userMFA, code); err != nil {
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			return err
+		}
+
+		return mfaUseCase.fail(ctx, userID)
+	}
+
+	if err := mfaUseCase.loginThrottleRepository.Delete(ctx, mfaSubject(userID)); err != nil {
+		log.Println("Error deleting the wrong two-factor codes: ", err)
+	}
+
+	return mfaUseCase.mfaRepository.Delete(ctx, userID)
+}
+
+func (mfaUseCase *mfaUseCase) IsEnabled(ctx context.Context, userID string) (enabled bool, err error) {
+	var userMFA domain.UserMFA
+
+	if err = mfaUseCase.mfaRepository.FindByUserID(ctx, &userMFA, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return userMFA.EnabledAt != nil, nil
+}
+
+// Challenge issues the short lived token a login with two-factor
+// authentication returns instead of an access token.
+func (mfaUseCase *mfaUseCase) Challenge(ctx context.Context, user domain.User) (challenge domain.MFAChallenge, err error) {
+	var token string
+
+	claims := domain.TokenClaims{
+		"id":    user.ID,
+		"email": user.Email,
+	}
+
+	if token, err = mfaUseCase.tokenSigner.Sign(domain.TokenTypeMFAChallenge, claims, mfaUseCase.challengeTTL); err != nil {
+		return challenge, err
+	}
+
+	return domain.MFAChallenge{
+		ChallengeToken: token,
+		ExpiresIn:      int64(mfaUseCase.challengeTTL.Seconds()),
+	}, nil
+}
+
+// Verify checks the code against the user of the challenge token and returns
+// the user the login can be completed for, loaded again so that the token
+// issued carries their role. A challenge is used up by a successful verify.
+// The wrong codes are counted for the user whatever challenge they come with,
+// after maxAttempts of them the user is locked out of the codes for
+// lockDuration and the login has to start again.
+func (mfaUseCase *mfaUseCase) Verify(ctx context.Context, challengeToken string, code string) (user domain.User, err error) {
+	var (
+		userMFA domain.UserMFA
+		revoked bool
+	)
+
+	claims, err := mfaUseCase.tokenSigner.Parse(domain.TokenTypeMFAChallenge, challengeToken)
+
+	if err != nil {
+		return user, domain.ErrInvalidMFAChallenge
+	}
+
+	userID, _ := claims["id"].(string)
+	jti, _ := claims["jti"].(string)
+	exp, _ := claims["exp"].(float64)
+	expiresAt := time.Unix(int64(exp), 0)
+
+	if revoked, err = mfaUseCase.revocationStore.IsRevoked(ctx, jti); err != nil {
+		return user, err
+	}
+
+	if revoked {
+		return user, domain.ErrInvalidMFAChallenge
+	}
+
+	if err = mfaUseCase.mfaRepository.FindByUserID(ctx, &userMFA, userID); err != nil || userMFA.EnabledAt == nil {
+		return user, domain.ErrInvalidMFAChallenge
+	}
+
+	if err = mfaUseCase.checkLocked(ctx, userID); err != nil {
+		return user, err
+	}
+
+	if err = mfaUseCase.checkCode(ctx, userMFA, code); err != nil {
+		if !errors.Is(err, domain.ErrInvalidMFACode) {
+			return user, err
+		}
+
+		if err = mfaUseCase.fail(ctx, userID); errors.Is(err, domain.ErrTooManyMFAAttempts) {
+			// the login has to start again once the codes are unlocked
+			if revokeErr := mfaUseCase.revocationStore.Revoke(ctx, jti, expiresAt); revokeErr != nil {
+				return user, revokeErr
+			}
+		}
+
+		return user, err
+	}
+
+	// the challenge can't complete another login
+	if err = mfaUseCase.revocationStore.Revoke(ctx, jti, expiresAt); err != nil {
+		return user, err
+	}
+
+	if err := mfaUseCase.loginThrottleRepository.Delete(ctx, mfaSubject(userID)); err != nil {
+		log.Println("Error deleting the wrong two-factor codes: ", err)
+	}
+
+	if user, err = mfaUseCase.userRepository.FindById(ctx, userID); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// checkLocked tells whether the user is locked out of the codes after too
+// many wrong ones.
+func (mfaUseCase *mfaUseCase) checkLocked(ctx context.Context, userID string) (err error) {
+	var loginThrottle domain.LoginThrottle
+
+	if err = mfaUseCase.loginThrottleRepository.Find(ctx, &loginThrottle, mfaSubject(userID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if loginThrottle.LockedUntil != nil && loginThrottle.LockedUntil.After(time.Now()) {
+		return domain.ErrTooManyMFAAttempts
+	}
+
+	return nil
+}
+
+// fail counts a wrong code of the user and locks the user out of the codes
+// once maxAttempts of them are taken within lockDuration.
+func (mfaUseCase *mfaUseCase) fail(ctx context.Context, userID string) (err error) {
+	var loginThrottle domain.LoginThrottle
+
+	if loginThrottle, err = mfaUseCase.loginThrottleRepository.RecordFailure(ctx, mfaSubject(userID), mfaUseCase.lockDuration); err != nil {
+		return err
+	}
+
+	if loginThrottle.Failures < mfaUseCase.maxAttempts {
+		return domain.ErrInvalidMFACode
+	}
+
+	if err = mfaUseCase.loginThrottleRepository.LockUntil(ctx, mfaSubject(userID), time.Now().Add(mfaUseCase.lockDuration)); err != nil {
+		return err
+	}
+
+	return domain.ErrTooManyMFAAttempts
+}
+
+// checkCode accepts a TOTP code newer than the last accepted one or an unused
+// recovery code.
+func (mfaUseCase *mfaUseCase) checkCode(ctx context.Context, userMFA domain.UserMFA, code string) (err error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+
+	if counter, ok := helpers.ValidateTOTP(userMFA.Secret, code, time.Now()); ok {
+		return mfaUseCase.mfaRepository.UseCounter(ctx, userMFA.UserID, counter)
+	}
+
+	if strings.Contains(code, "-") {
+		return mfaUseCase.mfaRepository.UseRecoveryCode(ctx, userMFA.UserID, helpers.HashToken(code))
+	}
+
+	return domain.ErrInvalidMFACode
+}
+
+// mfaSubject is the login throttle subject counting the wrong codes of the
+// user.
+func mfaSubject(userID string) string {
+	return "mfa:" + userID
+}
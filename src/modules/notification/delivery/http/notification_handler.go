@@ -0,0 +1,117 @@
+package delivery
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+)
+
+type notificationHandler struct {
+	notificationUseCase domain.NotificationUseCase
+}
+
+func NewNotificationHandler(routers *gin.Engine, notificationUseCase domain.NotificationUseCase) *notificationHandler {
+	handler := &notificationHandler{notificationUseCase}
+
+	router := routers.Group("/api/v1/notifications")
+	{
+		router.Use(middleware.Authentication(), middleware.SessionOnly())
+		router.GET("", handler.GetNotifications)
+		router.PUT("/:notificationId/read", handler.MarkRead)
+	}
+
+	return handler
+}
+
+// GetNotifications godoc
+// @Summary			Get the notifications
+// @Description		Get a page of the notifications of the authentication user, like the mentions of them in captions and comments
+// @Tags			notification
+// @Produce			json
+// @Param			cursor		query			string	false	"next_cursor of the previous page"
+// @Param			limit		query			int		false	"Page size, 20 by default and 100 at most"
+// @Param			sort		query			string	false	"-created_at for the most recent first, by default, or created_at"
+// @Success			200			{object}		domain.FetchedNotifications
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/notifications		[get]
+func (handler *notificationHandler) GetNotifications(ctx *gin.Context) {
+	var notifications []domain.Notification
+
+	params, err := pagination.Parse(ctx.Request.URL.Query())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.notificationUseCase.FindAllByUser(ctx.Request.Context(), &notifications, middleware.CurrentPrincipal(ctx).UserID, params); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	notifications, meta := pagination.Trim(notifications, params, func(notification domain.Notification) (*time.Time, string) {
+		return notification.CreatedAt, notification.ID
+	})
+
+	if notifications == nil {
+		notifications = []domain.Notification{}
+	}
+
+	ctx.JSON(http.StatusOK, domain.FetchedNotifications{
+		Status:  "success",
+		Message: "the notifications have been successfully fetched",
+		Data:    notifications,
+		Meta:    meta,
+	})
+}
+
+// MarkRead godoc
+// @Summary			Mark a notification as read
+// @Description		Mark a notification of the authentication user as read
+// @Tags			notification
+// @Produce			json
+// @Param			notificationId	path		string	true	"Notification ID"
+// @Success			200			{object}		domain.ReadNotification
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/notifications/{notificationId}/read		[put]
+func (handler *notificationHandler) MarkRead(ctx *gin.Context) {
+	notificationID := ctx.Param("notificationId")
+
+	if err := handler.notificationUseCase.MarkRead(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, notificationID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: "the notification doesn't exist",
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.ReadNotification{
+		Status:  "success",
+		Message: "the notification has been marked as read",
+	})
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *notificationRepository {
+	return &notificationRepository{db}
+}
+
+// SaveAll stores the notifications in a single insert.
+func (notificationRepository *notificationRepository) SaveAll(ctx context.Context, notifications []domain.Notification) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	for i := range notifications {
+		ID, _ := gonanoid.New(16)
+
+		notifications[i].ID = fmt.Sprintf("notification-%s", ID)
+	}
+
+	if err = notificationRepository.db.WithContext(ctx).Create(&notifications).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// MarkRead marks the notification of the user as read, a notification read
+// before keeps the time it was first read.
+func (notificationRepository *notificationRepository) MarkRead(ctx context.Context, userID string, id string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := notificationRepository.db.WithContext(ctx).Model(&domain.Notification{}).Where("id = ? AND user_id = ?", id, userID).UpdateColumn("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+// FindAllByUser finds a page of the notifications of the user with their
+// actor.
+func (notificationRepository *notificationRepository) FindAllByUser(ctx context.Context, notifications *[]domain.Notification, userID string, params pagination.Params) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = notificationRepository.db.WithContext(ctx).Where("user_id = ?", userID).Preload("Actor", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "display_name", "avatar_url")
+	}).Scopes(pagination.Scope(params, "created_at", "id")).Find(&notifications).Error; err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+)
+
+type notificationUseCase struct {
+	notificationRepository domain.NotificationRepository
+}
+
+func NewNotificationUseCase(notificationRepository domain.NotificationRepository) *notificationUseCase {
+	return &notificationUseCase{notificationRepository}
+}
+
+func (notificationUseCase *notificationUseCase) MarkRead(ctx context.Context, userID string, id string) (err error) {
+	if err = notificationUseCase.notificationRepository.MarkRead(ctx, userID, id); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (notificationUseCase *notificationUseCase) FindAllByUser(ctx context.Context, notifications *[]domain.Notification, userID string, params pagination.Params) (err error) {
+	if err = notificationUseCase.notificationRepository.FindAllByUser(ctx, notifications, userID, params); err != nil {
+		return err
+	}
+
+	return
+}
@@ -0,0 +1,109 @@
+package delivery
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+)
+
+type passwordResetHandler struct {
+	passwordResetUseCase domain.PasswordResetUseCase
+}
+
+func NewPasswordResetHandler(routers *gin.Engine, passwordResetUseCase domain.PasswordResetUseCase) *passwordResetHandler {
+	handler := &passwordResetHandler{passwordResetUseCase}
+
+	router := routers.Group("/api/v1/user/password")
+	{
+		router.POST("/forgot", handler.Forgot)
+		router.POST("/reset", handler.Reset)
+	}
+
+	return handler
+}
+
+// Forgot godoc
+// @Summary			Forgot password
+// @Description		Send a password reset link to the email when it belongs to a user
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.ForgotPassword	true	"Forgot Password"
+// @Success			200		{object}		domain.PasswordResetResponse
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Router			/user/password/forgot		[post]
+func (handler *passwordResetHandler) Forgot(ctx *gin.Context) {
+	var (
+		input domain.ForgotPassword
+		err   error
+	)
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if _, err = govalidator.ValidateStruct(input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// a failure answers as an unknown email does, it would tell the email is
+	// registered otherwise
+	if err = handler.passwordResetUseCase.Forgot(ctx.Request.Context(), input.Email); err != nil {
+		log.Println("Error sending password reset: ", err)
+	}
+
+	ctx.JSON(http.StatusOK, domain.PasswordResetResponse{
+		Status:  "success",
+		Message: "if the email is registered, a password reset link has been sent to it",
+	})
+}
+
+// Reset godoc
+// @Summary			Reset password
+// @Description		Set a new password with the token of a password reset link
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.ResetPassword	true	"Reset Password"
+// @Success			200		{object}		domain.PasswordResetResponse
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Router			/user/password/reset		[post]
+func (handler *passwordResetHandler) Reset(ctx *gin.Context) {
+	var (
+		input domain.ResetPassword
+		err   error
+	)
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.passwordResetUseCase.Reset(ctx.Request.Context(), input.Token, input.Password); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.PasswordResetResponse{
+		Status:  "success",
+		Message: "your password has been reset, please sign in again",
+	})
+}
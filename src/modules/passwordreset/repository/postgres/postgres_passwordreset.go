@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) *passwordResetRepository {
+	return &passwordResetRepository{db}
+}
+
+func (passwordResetRepository *passwordResetRepository) Save(ctx context.Context, passwordReset *domain.PasswordReset) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	passwordReset.ID = fmt.Sprintf("passwordreset-%s", ID)
+
+	if err = passwordResetRepository.db.WithContext(ctx).Create(&passwordReset).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (passwordResetRepository *passwordResetRepository) FindByHash(ctx context.Context, passwordReset *domain.PasswordReset, tokenHash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = passwordResetRepository.db.WithContext(ctx).First(&passwordReset, "token_hash = ?", tokenHash).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Consume marks the reset token as used and stores the new password hash in
+// one transaction. Every other outstanding reset token of the user is used up
+// as well.
+func (passwordResetRepository *passwordResetRepository) Consume(ctx context.Context, passwordReset domain.PasswordReset, passwordHash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return passwordResetRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&domain.PasswordReset{}).Where("id = ? AND used_at IS NULL AND expires_at > ?", passwordReset.ID, now).Update("used_at", now)
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrInvalidPasswordResetToken
+		}
+
+		if err := tx.Model(&domain.PasswordReset{}).Where("user_id = ? AND used_at IS NULL", passwordReset.UserID).Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.User{ID: passwordReset.UserID}).UpdateColumns(map[string]interface{}{
+			"password":   passwordHash,
+			"updated_at": now,
+		}).Error
+	})
+}
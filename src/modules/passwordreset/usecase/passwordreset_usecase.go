@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"gorm.io/gorm"
+)
+
+type passwordResetUseCase struct {
+	passwordResetRepository domain.PasswordResetRepository
+	userRepository          domain.UserRepository
+	tokenUseCase            domain.TokenUseCase
+	apiKeyUseCase           domain.APIKeyUseCase
+	mailer                  domain.Mailer
+	resetURL                string
+	ttl                     time.Duration
+}
+
+func NewPasswordResetUseCase(passwordResetRepository domain.PasswordResetRepository, userRepository domain.UserRepository, tokenUseCase domain.TokenUseCase, apiKeyUseCase domain.APIKeyUseCase, mailer domain.Mailer, resetURL string, ttl time.Duration) *passwordResetUseCase {
+	return &passwordResetUseCase{passwordResetRepository, userRepository, tokenUseCase, apiKeyUseCase, mailer, resetURL, ttl}
+}
+
+// Forgot mails a reset link to the user with the given email. It succeeds
+// for unknown emails too so it can't be used to find registered accounts.
+func (passwordResetUseCase *passwordResetUseCase) Forgot(ctx context.Context, email string) (err error) {
+	var (
+		user  domain.User
+		token string
+	)
+
+	if user, err = passwordResetUseCase.userRepository.FindByEmail(ctx, &domain.User{Email: email}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	if token, err = helpers.GenerateSecureToken(); err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(passwordResetUseCase.ttl)
+	passwordReset := domain.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: helpers.HashToken(token),
+		ExpiresAt: &expiresAt,
+	}
+
+	if err = passwordResetUseCase.passwordResetRepository.Save(ctx, &passwordReset); err != nil {
+		return err
+	}
+
+	return passwordResetUseCase.mailer.Send(ctx, domain.Mail{
+		To:      user.Email,
+		Subject: "Reset your MyGram password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nsomeone asked to reset the password of your MyGram account. Open the link below to choose a new one, it expires in %s:\n\n%s?token=%s\n\nIf it wasn't you, you can ignore this mail.\n",
+			user.Username, passwordResetUseCase.ttl, passwordResetUseCase.resetURL, url.QueryEscape(token),
+		),
+	})
+}
+
+// Reset consumes the reset token, stores the new password, signs the user out
+// of every session and revokes their api keys.
+func (passwordResetUseCase *passwordResetUseCase) Reset(ctx context.Context, token string, password string) (err error) {
+	var (
+		passwordReset domain.PasswordReset
+		passwordHash  string
+	)
+
+	if _, err = govalidator.ValidateStruct(domain.ResetPassword{Token: token, Password: password}); err != nil {
+		return err
+	}
+
+	if err = passwordResetUseCase.passwordResetRepository.FindByHash(ctx, &passwordReset, helpers.HashToken(token)); err != nil {
+		return domain.ErrInvalidPasswordResetToken
+	}
+
+	if passwordReset.UsedAt != nil || passwordReset.ExpiresAt == nil || time.Now().After(*passwordReset.ExpiresAt) {
+		return domain.ErrInvalidPasswordResetToken
+	}
+
+	if passwordHash, err = helpers.Hash(password); err != nil {
+		return err
+	}
+
+	if err = passwordResetUseCase.passwordResetRepository.Consume(ctx, passwordReset, passwordHash); err != nil {
+		return err
+	}
+
+	if err = passwordResetUseCase.tokenUseCase.RevokeAll(ctx, passwordReset.UserID); err != nil {
+		return err
+	}
+
+	return passwordResetUseCase.apiKeyUseCase.RevokeAllByUser(ctx, passwordReset.UserID)
+}
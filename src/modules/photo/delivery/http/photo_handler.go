@@ -1,31 +1,38 @@
 package delivery
 
 import (
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	"github.com/gusrylmubarok/mygram-backend/src/helpers"
 	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 )
 
 type photoHandler struct {
-	photoUseCase domain.PhotoUseCase
+	photoUseCase   domain.PhotoUseCase
+	userUseCase    domain.UserUseCase
+	likeUseCase    domain.LikeUseCase
+	maxUploadBytes int64
 }
 
-func NewPhotoHandler(routers *gin.Engine, photoUseCase domain.PhotoUseCase) {
-	handler := &photoHandler{photoUseCase}
+func NewPhotoHandler(routers *gin.Engine, photoUseCase domain.PhotoUseCase, userUseCase domain.UserUseCase, likeUseCase domain.LikeUseCase, maxUploadBytes int64) {
+	handler := &photoHandler{photoUseCase, userUseCase, likeUseCase, maxUploadBytes}
 
 	router := routers.Group("/api/v1/photo")
 	{
 		router.Use(middleware.Authentication())
-		router.POST("", handler.CreatePhoto)
-		router.PUT("/:photoId", middleware.AuthorizationPhoto(handler.photoUseCase), handler.UpdatePhoto)
-		router.DELETE("/:photoId", middleware.AuthorizationPhoto(handler.photoUseCase), handler.DeleteById)
-		router.GET("", handler.GetAll)
-		router.GET("/:photoId", handler.GetById)
+		router.POST("", middleware.RequireScope(domain.ScopePhotosWrite), middleware.VerifiedEmail(handler.userUseCase), handler.CreatePhoto)
+		router.POST("/upload", middleware.RequireScope(domain.ScopePhotosWrite), middleware.VerifiedEmail(handler.userUseCase), handler.UploadPhoto)
+		router.PUT("/:photoId", middleware.RequireScope(domain.ScopePhotosWrite), middleware.AuthorizationPhoto(handler.photoUseCase), handler.UpdatePhoto)
+		router.DELETE("/:photoId", middleware.RequireScope(domain.ScopePhotosWrite), middleware.AuthorizationPhoto(handler.photoUseCase), handler.DeleteById)
+		router.PUT("/:photoId/comment-policy", middleware.RequireScope(domain.ScopePhotosWrite), middleware.AuthorizationPhoto(handler.photoUseCase), handler.UpdateCommentPolicy)
+		router.GET("", middleware.RequireScope(domain.ScopePhotosRead), handler.GetAll)
+		router.GET("/:photoId", middleware.RequireScope(domain.ScopePhotosRead), handler.GetById)
 	}
 }
 
@@ -39,7 +46,9 @@ func NewPhotoHandler(routers *gin.Engine, photoUseCase domain.PhotoUseCase) {
 // @Success     201			{object}  		domain.AddedPhoto
 // @Failure     400			{object}		helpers.ResponseMessage
 // @Failure     401			{object}		helpers.ResponseMessage
+// @Failure     403			{object}		helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photo	[post]
 func (handler *photoHandler) CreatePhoto(ctx *gin.Context) {
 	var (
@@ -48,8 +57,7 @@ func (handler *photoHandler) CreatePhoto(ctx *gin.Context) {
 		err   error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err = ctx.ShouldBindJSON(&input); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -79,6 +87,8 @@ func (handler *photoHandler) CreatePhoto(ctx *gin.Context) {
 			ID:       photo.ID,
 			Title:    photo.Title,
 			Caption:  photo.Caption,
+			Hashtags: photo.HashtagNames(),
+			Mentions: photo.Mentions,
 			PhotoUrl: photo.PhotoUrl,
 			User: &domain.GetUser{
 				ID:       photo.User.ID,
@@ -90,9 +100,126 @@ func (handler *photoHandler) CreatePhoto(ctx *gin.Context) {
 	})
 }
 
+// UploadPhoto godoc
+// @Summary    	Upload a photo
+// @Description	Upload the image of a photo with authentication user, the type is sniffed from the content and must be JPEG, PNG or GIF. The image is stored re-encoded without its EXIF, XMP and other metadata, the camera model, exposure and time it was taken at are kept with keep_metadata. The photo is processing until its variants are generated in the background
+// @Tags        photo
+// @Accept      mpfd
+// @Produce     json
+// @Param       photo		formData		file	true	"The image"
+// @Param       title		formData		string	true	"A Photo Title"
+// @Param       caption		formData		string	false	"A caption"
+// @Param       keep_metadata	formData	bool	false	"Keep the camera model, exposure and time it was taken at"
+// @Success     201			{object}  		domain.UploadedPhoto
+// @Failure     400			{object}		helpers.ResponseMessage
+// @Failure     401			{object}		helpers.ResponseMessage
+// @Failure     403			{object}		helpers.ResponseMessage
+// @Failure     413			{object}		helpers.ResponseMessage
+// @Failure     415			{object}		helpers.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photo/upload	[post]
+func (handler *photoHandler) UploadPhoto(ctx *gin.Context) {
+	var (
+		input domain.UploadPhoto
+		photo domain.Photo
+		err   error
+	)
+
+	// room is left for the other fields and the multipart boundaries
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, handler.maxUploadBytes+1<<20)
+
+	header, err := ctx.FormFile("photo")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			handler.abortUpload(ctx, domain.ErrImageTooLarge)
+			return
+		}
+
+		if errors.Is(err, http.ErrMissingFile) {
+			err = errors.New("the photo file is required")
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = ctx.ShouldBind(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+	defer file.Close()
+
+	photo.Title = input.Title
+	photo.Caption = input.Caption
+	photo.UserID = middleware.CurrentPrincipal(ctx).UserID
+
+	if err = handler.photoUseCase.Upload(ctx.Request.Context(), &photo, file, input.KeepMetadata); err != nil {
+		handler.abortUpload(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, domain.UploadedPhoto{
+		Status:  "success",
+		Message: "photo has been uploaded",
+		Data: domain.UploadedDataPhoto{
+			ID:       photo.ID,
+			Title:    photo.Title,
+			Caption:  photo.Caption,
+			Hashtags: photo.HashtagNames(),
+			Mentions: photo.Mentions,
+			PhotoUrl: photo.PhotoUrl,
+			MimeType: photo.MimeType,
+			ByteSize: photo.ByteSize,
+			Width:    photo.Width,
+			Height:   photo.Height,
+			Status:   photo.Status,
+			Metadata: photo.CameraMetadata(),
+			User: &domain.GetUser{
+				ID:       photo.User.ID,
+				Email:    photo.User.Email,
+				Username: photo.User.Username,
+			},
+			CreatedAt: photo.CreatedAt,
+		},
+	})
+}
+
+func (handler *photoHandler) abortUpload(ctx *gin.Context, err error) {
+	status := http.StatusBadRequest
+
+	switch {
+	case errors.Is(err, domain.ErrImageTooLarge):
+		status = http.StatusRequestEntityTooLarge
+	case errors.Is(err, domain.ErrUnsupportedImage):
+		status = http.StatusUnsupportedMediaType
+	}
+
+	ctx.AbortWithStatusJSON(status, helpers.ResponseMessage{
+		Status:  "fail",
+		Message: err.Error(),
+	})
+}
+
 // Update godoc
 // @Summary     Update a photo
-// @Description	Update a photo by id with authentication user
+// @Description	Update a photo by id with authentication user, the photo url of an uploaded photo can't be changed
 // @Tags        photo
 // @Accept      json
 // @Produce     json
@@ -103,6 +230,7 @@ func (handler *photoHandler) CreatePhoto(ctx *gin.Context) {
 // @Failure     401		{object}		helpers.ResponseMessage
 // @Failure     404		{object}		helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photo/{id}		[put]
 func (handler *photoHandler) UpdatePhoto(ctx *gin.Context) {
 	var (
@@ -140,6 +268,8 @@ func (handler *photoHandler) UpdatePhoto(ctx *gin.Context) {
 			ID:       photo.ID,
 			Title:    photo.Title,
 			Caption:  photo.Caption,
+			Hashtags: photo.HashtagNames(),
+			Mentions: photo.Mentions,
 			PhotoUrl: photo.PhotoUrl,
 			User: &domain.GetUser{
 				ID:       photo.User.ID,
@@ -163,6 +293,7 @@ func (handler *photoHandler) UpdatePhoto(ctx *gin.Context) {
 // @Failure     401	{object}		helpers.ResponseMessage
 // @Failure     404	{object}		helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photo/{id}	[delete]
 func (handler *photoHandler) DeleteById(ctx *gin.Context) {
 	photoID := ctx.Param("photoId")
@@ -183,22 +314,33 @@ func (handler *photoHandler) DeleteById(ctx *gin.Context) {
 
 // Get All godoc
 // @Summary    	Get all photos
-// @Description	Get all photos
+// @Description	Get a page of the photos, of a user with user_id
 // @Tags        photo
 // @Accept      json
 // @Produce     json
+// @Param       user_id		query		string	false	"Only the photos of the user"
+// @Param       cursor		query		string	false	"next_cursor of the previous page"
+// @Param       limit		query		int		false	"Page size, 20 by default and 100 at most"
+// @Param       sort		query		string	false	"-created_at for the most recent first, by default, or created_at"
 // @Success     200			{object}	domain.GetAllPhotos
 // @Failure     400			{object}	helpers.ResponseMessage
 // @Failure     401			{object}	helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photo	[get]
 func (handler *photoHandler) GetAll(ctx *gin.Context) {
-	var (
-		photos []domain.Photo
-		err    error
-	)
+	var photos []domain.Photo
+
+	params, err := pagination.Parse(ctx.Request.URL.Query(), "user_id")
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
 
-	if err = handler.photoUseCase.FindAll(ctx.Request.Context(), &photos); err != nil {
+	if err = handler.photoUseCase.FindAll(ctx.Request.Context(), &photos, params); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
@@ -207,14 +349,39 @@ func (handler *photoHandler) GetAll(ctx *gin.Context) {
 		return
 	}
 
+	photos, meta := pagination.Trim(photos, params, func(photo domain.Photo) (*time.Time, string) { return photo.CreatedAt, photo.ID })
+
+	photoIDs := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		photoIDs = append(photoIDs, photo.ID)
+	}
+
+	liked, err := handler.likeUseCase.FindLikedPhotoIDs(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, photoIDs)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	fetchedPhotos := []*domain.GetDetailPhoto{}
 
 	for _, photo := range photos {
 		fetchedPhotos = append(fetchedPhotos, &domain.GetDetailPhoto{
-			ID:       photo.ID,
-			Title:    photo.Title,
-			Caption:  photo.Caption,
-			PhotoUrl: photo.PhotoUrl,
+			ID:              photo.ID,
+			Title:           photo.Title,
+			Caption:         photo.Caption,
+			PhotoUrl:        photo.PhotoUrl,
+			Status:          photo.Status,
+			Variants:        photo.VariantURLs(),
+			Hashtags:        photo.HashtagNames(),
+			Mentions:        photo.Mentions,
+			Metadata:        photo.CameraMetadata(),
+			LikeCount:       photo.LikeCount,
+			Liked:           liked[photo.ID],
+			CommentPolicy:   photo.CommentPolicy,
+			PinnedCommentID: photo.PinnedCommentID,
 			User: &domain.GetUser{
 				ID:       photo.User.ID,
 				Email:    photo.User.Email,
@@ -225,18 +392,11 @@ func (handler *photoHandler) GetAll(ctx *gin.Context) {
 		})
 	}
 
-	if len(fetchedPhotos) < 1 {
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
-			Status:  "fail",
-			Message: "photos is empty",
-		})
-		return
-	}
-
 	ctx.JSON(http.StatusOK, domain.GetAllPhotos{
 		Status:  "success",
 		Message: "get all photos",
 		Data:    fetchedPhotos,
+		Meta:    meta,
 	})
 }
 
@@ -250,6 +410,7 @@ func (handler *photoHandler) GetAll(ctx *gin.Context) {
 // @Failure     400			{object}	helpers.ResponseMessage
 // @Failure     401			{object}	helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /photo/{id}	[get]
 func (handler *photoHandler) GetById(ctx *gin.Context) {
 	var (
@@ -275,14 +436,32 @@ func (handler *photoHandler) GetById(ctx *gin.Context) {
 		return
 	}
 
+	liked, err := handler.likeUseCase.FindLikedPhotoIDs(ctx.Request.Context(), middleware.CurrentPrincipal(ctx).UserID, []string{photo.ID})
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	ctx.JSON(http.StatusOK, domain.GetByIdPhoto{
 		Status:  "success",
 		Message: "get detail photo",
 		Data: domain.GetDetailPhoto{
-			ID:       photo.ID,
-			Title:    photo.Title,
-			Caption:  photo.Caption,
-			PhotoUrl: photo.PhotoUrl,
+			ID:              photo.ID,
+			Title:           photo.Title,
+			Caption:         photo.Caption,
+			PhotoUrl:        photo.PhotoUrl,
+			Status:          photo.Status,
+			Variants:        photo.VariantURLs(),
+			Hashtags:        photo.HashtagNames(),
+			Mentions:        photo.Mentions,
+			Metadata:        photo.CameraMetadata(),
+			LikeCount:       photo.LikeCount,
+			Liked:           liked[photo.ID],
+			CommentPolicy:   photo.CommentPolicy,
+			PinnedCommentID: photo.PinnedCommentID,
 			User: &domain.GetUser{
 				ID:       photo.User.ID,
 				Email:    photo.User.Email,
@@ -293,3 +472,51 @@ func (handler *photoHandler) GetById(ctx *gin.Context) {
 		},
 	})
 }
+
+// UpdateCommentPolicy godoc
+// @Summary    	Update the comment policy of a photo
+// @Description	Let everyone, the followers of the owner only or no one comment on a photo
+// @Tags        photo
+// @Accept      json
+// @Produce     json
+// @Param       photoId		path		string						true	"Photo ID"
+// @Param       json		body		domain.UpdateCommentPolicy	true	"Update Comment Policy"
+// @Success     200			{object}	domain.UpdatedCommentSettings
+// @Failure     400			{object}	helpers.ResponseMessage
+// @Failure     401			{object}	helpers.ResponseMessage
+// @Failure     404			{object}	helpers.ResponseMessage
+// @Security    Bearer
+// @Security    ApiKey
+// @Router      /photo/{photoId}/comment-policy	[put]
+func (handler *photoHandler) UpdateCommentPolicy(ctx *gin.Context) {
+	var (
+		input domain.UpdateCommentPolicy
+		photo domain.Photo
+		err   error
+	)
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if photo, err = handler.photoUseCase.UpdateCommentPolicy(ctx.Request.Context(), ctx.Param("photoId"), input.CommentPolicy); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.UpdatedCommentSettings{
+		Status:  "success",
+		Message: "the comment policy of the photo has been updated",
+		Data: domain.CommentSettings{
+			CommentPolicy:   photo.CommentPolicy,
+			PinnedCommentID: photo.PinnedCommentID,
+		},
+	})
+}
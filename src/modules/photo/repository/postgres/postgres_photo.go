@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	"gorm.io/gorm"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -27,13 +28,21 @@ func (photoRepository *photoRepository) Save(ctx context.Context, photo *domain.
 
 	photo.ID = fmt.Sprintf("photo-%s", ID)
 
+	for i := range photo.Mentions {
+		ID, _ := gonanoid.New(16)
+
+		photo.Mentions[i].ID = fmt.Sprintf("mention-%s", ID)
+	}
+
+	// the hashtags and the mentions are created along, the hashtags already
+	// used by other photos are only joined
 	if err := photoRepository.db.WithContext(ctx).Create(&photo).Error; err != nil {
 		return err
 	}
 
 	if err = photoRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "username", "email")
-	}).First(&photo).Error; err != nil {
+	}).Preload("Hashtags").Preload("Mentions", "comment_id IS NULL").First(&photo).Error; err != nil {
 		return err
 	}
 
@@ -50,13 +59,44 @@ func (photoRepository *photoRepository) Update(ctx context.Context, p domain.Pho
 		return photo, err
 	}
 
-	if err = photoRepository.db.WithContext(ctx).Model(&photo).Updates(p).Error; err != nil {
+	if err = photoRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Model(&photo).Omit("Hashtags", "Mentions").Updates(p).Error; err != nil {
+			return err
+		}
+
+		// an empty caption is left as it is by Updates, and so are its
+		// hashtags and mentions
+		if p.Caption == "" {
+			return
+		}
+
+		if err = tx.Model(&photo).Association("Hashtags").Replace(p.Hashtags); err != nil {
+			return err
+		}
+
+		if err = tx.Where("photo_id = ? AND comment_id IS NULL", photo.ID).Delete(&domain.Mention{}).Error; err != nil {
+			return err
+		}
+
+		if len(p.Mentions) == 0 {
+			return
+		}
+
+		for i := range p.Mentions {
+			ID, _ := gonanoid.New(16)
+
+			p.Mentions[i].ID = fmt.Sprintf("mention-%s", ID)
+			p.Mentions[i].PhotoID = photo.ID
+		}
+
+		return tx.Create(&p.Mentions).Error
+	}); err != nil {
 		return photo, err
 	}
 
 	if err = photoRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "username", "email")
-	}).First(&photo).Error; err != nil {
+	}).Preload("Hashtags").Preload("Mentions", "comment_id IS NULL").First(&photo).Error; err != nil {
 		return photo, err
 	}
 
@@ -78,14 +118,14 @@ func (photoRepository *photoRepository) DeleteById(ctx context.Context, id strin
 	return
 }
 
-func (photoRepository *photoRepository) FindAll(ctx context.Context, photos *[]domain.Photo) (err error) {
+func (photoRepository *photoRepository) FindAll(ctx context.Context, photos *[]domain.Photo, params pagination.Params) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
 	if err = photoRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "username", "email")
-	}).Find(&photos).Error; err != nil {
+	}).Preload("Variants").Preload("Hashtags").Preload("Mentions", "comment_id IS NULL").Scopes(pagination.Scope(params, "created_at", "id")).Find(&photos).Error; err != nil {
 		return err
 	}
 
@@ -99,9 +139,82 @@ func (photoRepository *photoRepository) FindById(ctx context.Context, photo *dom
 
 	if err = photoRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("id", "username", "email")
-	}).First(&photo, &id).Error; err != nil {
+	}).Preload("Variants").Preload("Hashtags").Preload("Mentions", "comment_id IS NULL").First(&photo, &id).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// FindProcessing finds the uploaded photos whose variants are still to be
+// generated.
+func (photoRepository *photoRepository) FindProcessing(ctx context.Context, photos *[]domain.Photo) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = photoRepository.db.WithContext(ctx).Where("status = ?", domain.PhotoStatusProcessing).Order("created_at").Find(&photos).Error; err != nil {
 		return err
 	}
 
 	return
 }
+
+// SaveVariants stores the variants of the photo and sets its status in one
+// transaction.
+func (photoRepository *photoRepository) SaveVariants(ctx context.Context, id string, variants []domain.PhotoVariant, status string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return photoRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if len(variants) > 0 {
+			if err = tx.Create(&variants).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Model(&domain.Photo{}).Where("id = ?", id).UpdateColumn("status", status)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return
+	})
+}
+
+func (photoRepository *photoRepository) UpdateCommentPolicy(ctx context.Context, id string, policy string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := photoRepository.db.WithContext(ctx).Model(&domain.Photo{}).Where("id = ?", id).UpdateColumn("comment_policy", policy)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+// UpdatePinnedComment pins the comment on the photo, in place of the one
+// pinned before, or unpins it when commentID is nil.
+func (photoRepository *photoRepository) UpdatePinnedComment(ctx context.Context, id string, commentID *string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := photoRepository.db.WithContext(ctx).Model(&domain.Photo{}).Where("id = ?", id).UpdateColumn("pinned_comment_id", commentID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
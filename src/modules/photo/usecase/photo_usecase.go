@@ -1,45 +1,219 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
 
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/imaging"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/gusrylmubarok/mygram-backend/src/textparse"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
 )
 
+// imageExtensions are the extensions of the keys of the image types that can
+// be uploaded.
+var imageExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+}
+
+// maxImagePixels keeps small files of huge images from being decoded into
+// gigabytes by the variant generation.
+const maxImagePixels = 50_000_000
+
 type photoUseCase struct {
 	photoRepository domain.PhotoRepository
+	feedStrategy    domain.FeedStrategy
+	blobStore       domain.BlobStore
+	photoProcessor  domain.PhotoProcessor
+	mentionUseCase  domain.MentionUseCase
+	maxUploadBytes  int64
 }
 
-func NewPhotoUseCase(photoRepository domain.PhotoRepository) *photoUseCase {
-	return &photoUseCase{photoRepository}
+func NewPhotoUseCase(photoRepository domain.PhotoRepository, feedStrategy domain.FeedStrategy, blobStore domain.BlobStore, photoProcessor domain.PhotoProcessor, mentionUseCase domain.MentionUseCase, maxUploadBytes int64) *photoUseCase {
+	return &photoUseCase{photoRepository, feedStrategy, blobStore, photoProcessor, mentionUseCase, maxUploadBytes}
 }
 
 func (photoUseCase *photoUseCase) Save(ctx context.Context, photo *domain.Photo) (err error) {
+	if photo.Status == "" {
+		photo.Status = domain.PhotoStatusReady
+	}
+
+	photo.Hashtags = hashtagsOf(photo.Caption)
+	photo.Mentions = photoUseCase.mentionUseCase.Resolve(ctx, photo.UserID, photo.Caption)
+
 	if err = photoUseCase.photoRepository.Save(ctx, photo); err != nil {
 		return err
 	}
 
+	// the photo is stored, missing from some feeds is no reason to fail it
+	if err := photoUseCase.feedStrategy.AddPhoto(ctx, *photo); err != nil {
+		log.Println("Error adding the photo to the feeds: ", err)
+	}
+
+	if err := photoUseCase.mentionUseCase.Notify(ctx, photo.UserID, photo.Mentions, nil); err != nil {
+		log.Println("Error notifying the users mentioned in the caption: ", err)
+	}
+
 	return
 }
 
+// Upload stores the image read from body in the blob store and saves the
+// photo linking to it, processing until its variants are generated. The type
+// is sniffed from the content rather than trusted from the client, only JPEG,
+// PNG and GIF images are accepted. The image is stored re-encoded without any
+// of its metadata, only the camera fields are kept on the photo when
+// keepMetadata.
+func (photoUseCase *photoUseCase) Upload(ctx context.Context, photo *domain.Photo, body io.Reader, keepMetadata bool) (err error) {
+	data, err := io.ReadAll(io.LimitReader(body, photoUseCase.maxUploadBytes+1))
+	if err != nil {
+		return err
+	}
+
+	if int64(len(data)) > photoUseCase.maxUploadBytes {
+		return domain.ErrImageTooLarge
+	}
+
+	mimeType := http.DetectContentType(data)
+
+	extension, ok := imageExtensions[mimeType]
+	if !ok {
+		return domain.ErrUnsupportedImage
+	}
+
+	stripped, width, height, err := imaging.Strip(data, maxImagePixels)
+	if errors.Is(err, imaging.ErrTooManyPixels) {
+		return domain.ErrImageTooLarge
+	}
+
+	if err != nil {
+		return domain.ErrUnsupportedImage
+	}
+
+	if keepMetadata {
+		metadata := imaging.ReadMetadata(data)
+
+		photo.CameraModel = metadata.CameraModel
+		photo.ExposureTime = metadata.ExposureTime
+		photo.FNumber = metadata.FNumber
+		photo.ISO = metadata.ISO
+		photo.TakenAt = metadata.TakenAt
+	}
+
+	ID, _ := gonanoid.New(16)
+
+	key := fmt.Sprintf("photos/%s/%s%s", photo.UserID, ID, extension)
+
+	if err = photoUseCase.blobStore.Put(ctx, key, bytes.NewReader(stripped), int64(len(stripped)), mimeType); err != nil {
+		return err
+	}
+
+	photo.PhotoUrl = photoUseCase.blobStore.URL(key)
+	photo.StorageKey = key
+	photo.MimeType = mimeType
+	photo.ByteSize = int64(len(stripped))
+	photo.Width = width
+	photo.Height = height
+	photo.Status = domain.PhotoStatusProcessing
+
+	if err = photoUseCase.Save(ctx, photo); err != nil {
+		if err := photoUseCase.blobStore.Delete(ctx, key); err != nil {
+			log.Println("Error deleting the blob of an unsaved photo: ", err)
+		}
+
+		return err
+	}
+
+	// the photo is stored, it is processed again on the next start if it
+	// can't be queued now
+	if err := photoUseCase.photoProcessor.Enqueue(ctx, *photo); err != nil {
+		log.Println("Error queueing the photo for its variants: ", err)
+	}
+
+	return
+}
+
+// Update updates the photo, the hashtags and the mentions follow the caption
+// when it's updated, only the users newly mentioned are notified.
 func (photoUseCase *photoUseCase) Update(ctx context.Context, p domain.Photo, id string) (photo domain.Photo, err error) {
+	var previous domain.Photo
+
+	if p.Caption != "" || p.PhotoUrl != "" {
+		if err = photoUseCase.photoRepository.FindById(ctx, &previous, id); err != nil {
+			return photo, err
+		}
+	}
+
+	// the url of an uploaded photo points at its blob, the key, type, size
+	// and variants of the photo would no longer match another one
+	if previous.StorageKey != "" && p.PhotoUrl != "" && p.PhotoUrl != previous.PhotoUrl {
+		return photo, domain.ErrPhotoUrlUploaded
+	}
+
+	if p.Caption != "" {
+		p.Hashtags = hashtagsOf(p.Caption)
+		p.Mentions = photoUseCase.mentionUseCase.Resolve(ctx, previous.UserID, p.Caption)
+	}
+
 	if photo, err = photoUseCase.photoRepository.Update(ctx, p, id); err != nil {
 		return photo, err
 	}
 
+	if p.Caption != "" {
+		if err := photoUseCase.mentionUseCase.Notify(ctx, photo.UserID, photo.Mentions, previous.Mentions); err != nil {
+			log.Println("Error notifying the users mentioned in the caption: ", err)
+		}
+	}
+
 	return photo, nil
 }
 
+// DeleteById deletes the photo and the blobs of its image and variants if it
+// was uploaded.
 func (photoUseCase *photoUseCase) DeleteById(ctx context.Context, id string) (err error) {
+	var photo domain.Photo
+
+	if err = photoUseCase.photoRepository.FindById(ctx, &photo, id); err != nil {
+		return err
+	}
+
 	if err = photoUseCase.photoRepository.DeleteById(ctx, id); err != nil {
 		return err
 	}
 
+	// the photo is gone, a blob left behind is only wasted space
+	keys := make([]string, 0, len(photo.Variants)+1)
+	if photo.StorageKey != "" {
+		keys = append(keys, photo.StorageKey)
+	}
+
+	for _, variant := range photo.Variants {
+		keys = append(keys, variant.StorageKey)
+	}
+
+	for _, key := range keys {
+		if err := photoUseCase.blobStore.Delete(ctx, key); err != nil {
+			log.Println("Error deleting the blob of the photo: ", err)
+		}
+	}
+
 	return
 }
 
-func (photoUseCase *photoUseCase) FindAll(ctx context.Context, photos *[]domain.Photo) (err error) {
-	if err = photoUseCase.photoRepository.FindAll(ctx, photos); err != nil {
+func (photoUseCase *photoUseCase) FindAll(ctx context.Context, photos *[]domain.Photo, params pagination.Params) (err error) {
+	if err = photoUseCase.photoRepository.FindAll(ctx, photos, params); err != nil {
 		return err
 	}
 
@@ -53,3 +227,31 @@ func (photoUseCase *photoUseCase) FindById(ctx context.Context, photo *domain.Ph
 
 	return
 }
+
+// UpdateCommentPolicy sets who can comment on the photo, the comments already
+// there stay.
+func (photoUseCase *photoUseCase) UpdateCommentPolicy(ctx context.Context, id string, policy string) (photo domain.Photo, err error) {
+	switch policy {
+	case domain.CommentPolicyEveryone, domain.CommentPolicyFollowers, domain.CommentPolicyOff:
+	default:
+		return photo, domain.ErrInvalidCommentPolicy
+	}
+
+	if err = photoUseCase.photoRepository.UpdateCommentPolicy(ctx, id, policy); err != nil {
+		return photo, err
+	}
+
+	if err = photoUseCase.photoRepository.FindById(ctx, &photo, id); err != nil {
+		return photo, err
+	}
+
+	return photo, nil
+}
+
+func hashtagsOf(caption string) (hashtags []domain.Hashtag) {
+	for _, name := range textparse.Hashtags(caption) {
+		hashtags = append(hashtags, domain.Hashtag{Name: name})
+	}
+
+	return
+}
@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log"
+	"path"
+	"strings"
+
+	_ "image/gif"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/imaging"
+)
+
+// variantWorker generates the variants of the uploaded photos in background
+// goroutines, fed by a queue so that uploads don't wait for them.
+type variantWorker struct {
+	photoRepository domain.PhotoRepository
+	blobStore       domain.BlobStore
+	widths          []int
+	queue           chan domain.Photo
+}
+
+func NewVariantWorker(photoRepository domain.PhotoRepository, blobStore domain.BlobStore, widths []int, queueSize int) *variantWorker {
+	return &variantWorker{photoRepository, blobStore, widths, make(chan domain.Photo, queueSize)}
+}
+
+// Start runs the workers until ctx is done. The photos a previous run left
+// processing are queued again.
+func (variantWorker *variantWorker) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case photo := <-variantWorker.queue:
+					if err := variantWorker.Process(ctx, photo); err != nil {
+						log.Println("Error generating the variants of the photo: ", err)
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		var photos []domain.Photo
+
+		if err := variantWorker.photoRepository.FindProcessing(ctx, &photos); err != nil {
+			log.Println("Error finding the photos left processing: ", err)
+			return
+		}
+
+		// the photos left are waited for, unlike an upload nothing hangs on them
+		for _, photo := range photos {
+			select {
+			case variantWorker.queue <- photo:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Enqueue queues the photo without waiting, ErrProcessingQueueFull is
+// returned when the workers are behind so that the upload isn't held up. The
+// photo stays processing and is queued again on the next start.
+func (variantWorker *variantWorker) Enqueue(ctx context.Context, photo domain.Photo) error {
+	select {
+	case variantWorker.queue <- photo:
+		return nil
+	default:
+		return domain.ErrProcessingQueueFull
+	}
+}
+
+// Process generates the variants of the photo and marks it ready, or failed
+// when they can't be. Only the widths narrower than the photo are generated,
+// images are never upscaled.
+func (variantWorker *variantWorker) Process(ctx context.Context, photo domain.Photo) (err error) {
+	variants, err := variantWorker.generate(ctx, photo)
+	if err != nil {
+		variantWorker.delete(ctx, variants)
+
+		if err := variantWorker.photoRepository.SaveVariants(ctx, photo.ID, nil, domain.PhotoStatusFailed); err != nil {
+			log.Println("Error marking the photo failed: ", err)
+		}
+
+		return err
+	}
+
+	// the photo may have been deleted in the meantime
+	if err = variantWorker.photoRepository.SaveVariants(ctx, photo.ID, variants, domain.PhotoStatusReady); err != nil {
+		variantWorker.delete(ctx, variants)
+		return err
+	}
+
+	return
+}
+
+func (variantWorker *variantWorker) generate(ctx context.Context, photo domain.Photo) (variants []domain.PhotoVariant, err error) {
+	body, err := variantWorker.blobStore.Get(ctx, photo.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	img = imaging.Orient(img, imaging.Orientation(data))
+
+	// JPEG stays JPEG, the other formats lose nothing as PNG
+	extension := path.Ext(photo.StorageKey)
+	base := strings.TrimSuffix(photo.StorageKey, extension)
+	contentType := "image/jpeg"
+
+	if format != "jpeg" {
+		extension = ".png"
+		contentType = "image/png"
+	}
+
+	for _, width := range variantWorker.widths {
+		if width >= img.Bounds().Dx() {
+			continue
+		}
+
+		resized := imaging.Resize(img, width)
+
+		var buffer bytes.Buffer
+
+		if format == "jpeg" {
+			err = jpeg.Encode(&buffer, resized, &jpeg.Options{Quality: 85})
+		} else {
+			err = png.Encode(&buffer, resized)
+		}
+
+		if err != nil {
+			return variants, err
+		}
+
+		key := fmt.Sprintf("%s_%d%s", base, width, extension)
+
+		if err = variantWorker.blobStore.Put(ctx, key, &buffer, int64(buffer.Len()), contentType); err != nil {
+			return variants, err
+		}
+
+		variants = append(variants, domain.PhotoVariant{
+			PhotoID:    photo.ID,
+			Width:      width,
+			Height:     resized.Bounds().Dy(),
+			StorageKey: key,
+			URL:        variantWorker.blobStore.URL(key),
+		})
+	}
+
+	return variants, nil
+}
+
+func (variantWorker *variantWorker) delete(ctx context.Context, variants []domain.PhotoVariant) {
+	for _, variant := range variants {
+		if err := variantWorker.blobStore.Delete(ctx, variant.StorageKey); err != nil {
+			log.Println("Error deleting the blob of a variant: ", err)
+		}
+	}
+}
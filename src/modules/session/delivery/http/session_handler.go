@@ -0,0 +1,115 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"gorm.io/gorm"
+)
+
+type sessionHandler struct {
+	sessionUseCase domain.SessionUseCase
+}
+
+func NewSessionHandler(routers *gin.Engine, sessionUseCase domain.SessionUseCase) *sessionHandler {
+	handler := &sessionHandler{sessionUseCase}
+
+	router := routers.Group("/api/v1/user/sessions")
+	{
+		router.Use(middleware.Authentication(), middleware.SessionOnly())
+		router.GET("", handler.GetAll)
+		router.DELETE("/:sessionId", handler.Revoke)
+	}
+
+	return handler
+}
+
+// GetAll godoc
+// @Summary			Get all sessions
+// @Description		Get the devices the authentication user is signed in on
+// @Tags			user
+// @Produce			json
+// @Success			200		{object}		domain.FetchedSessions
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/sessions		[get]
+func (handler *sessionHandler) GetAll(ctx *gin.Context) {
+	var (
+		sessions []domain.Session
+		err      error
+	)
+
+	principal := middleware.CurrentPrincipal(ctx)
+
+	if err = handler.sessionUseCase.FindAllActiveByUser(ctx.Request.Context(), &sessions, principal.UserID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fetchedSessions := make([]domain.GetSession, 0, len(sessions))
+
+	for _, session := range sessions {
+		fetchedSessions = append(fetchedSessions, domain.GetSession{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			Current:    session.ID == principal.SessionID,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, domain.FetchedSessions{
+		Status:  "success",
+		Message: "sessions have been fetched",
+		Data:    fetchedSessions,
+	})
+}
+
+// Revoke godoc
+// @Summary			Revoke a session
+// @Description		Sign the authentication user out of a session, its tokens are rejected from now on
+// @Tags			user
+// @Produce			json
+// @Param			sessionId	path		string	true	"Session ID"
+// @Success			200		{object}		domain.RevokedSession
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Failure			404		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/sessions/{sessionId}		[delete]
+func (handler *sessionHandler) Revoke(ctx *gin.Context) {
+	sessionID := ctx.Param("sessionId")
+
+	if err := handler.sessionUseCase.Revoke(ctx.Request.Context(), sessionID, middleware.CurrentPrincipal(ctx).UserID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: fmt.Sprintf("session with id %s doesn't exist or is already revoked", sessionID),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.RevokedSession{
+		Status:  "success",
+		Message: "the session has been revoked",
+	})
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *sessionRepository {
+	return &sessionRepository{db}
+}
+
+func (sessionRepository *sessionRepository) Save(ctx context.Context, session *domain.Session) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	session.ID = fmt.Sprintf("session-%s", ID)
+
+	if err = sessionRepository.db.WithContext(ctx).Create(&session).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// FindAllActiveByUser finds the sessions of the user which are neither
+// revoked nor expired, the most recently seen first.
+func (sessionRepository *sessionRepository) FindAllActiveByUser(ctx context.Context, sessions *[]domain.Session, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Touch records that the session was used at lastSeenAt and extends it to
+// expiresAt, it has no effect on a revoked session.
+func (sessionRepository *sessionRepository) Touch(ctx context.Context, id string, lastSeenAt time.Time, expiresAt time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Model(&domain.Session{}).Where("id = ? AND revoked_at IS NULL", id).UpdateColumns(map[string]interface{}{
+		"last_seen_at": lastSeenAt,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Revoke revokes the session when it belongs to the user and isn't revoked yet.
+func (sessionRepository *sessionRepository) Revoke(ctx context.Context, id string, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	result := sessionRepository.db.WithContext(ctx).Model(&domain.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		UpdateColumn("revoked_at", time.Now())
+
+	if err = result.Error; err != nil {
+		return err
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return
+}
+
+func (sessionRepository *sessionRepository) RevokeAllByUser(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = sessionRepository.db.WithContext(ctx).Model(&domain.Session{}).Where("user_id = ? AND revoked_at IS NULL", userID).UpdateColumn("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
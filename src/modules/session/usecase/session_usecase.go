@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+type sessionUseCase struct {
+	sessionRepository      domain.SessionRepository
+	refreshTokenRepository domain.RefreshTokenRepository
+	revocationStore        domain.RevocationStore
+	accessTokenTTL         time.Duration
+}
+
+func NewSessionUseCase(sessionRepository domain.SessionRepository, refreshTokenRepository domain.RefreshTokenRepository, revocationStore domain.RevocationStore, accessTokenTTL time.Duration) *sessionUseCase {
+	return &sessionUseCase{sessionRepository, refreshTokenRepository, revocationStore, accessTokenTTL}
+}
+
+func (sessionUseCase *sessionUseCase) FindAllActiveByUser(ctx context.Context, sessions *[]domain.Session, userID string) (err error) {
+	if err = sessionUseCase.sessionRepository.FindAllActiveByUser(ctx, sessions, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+// Revoke signs the session of the user out: its refresh tokens can no longer
+// be used and its access tokens are rejected until the last one expired.
+func (sessionUseCase *sessionUseCase) Revoke(ctx context.Context, id string, userID string) (err error) {
+	if err = sessionUseCase.sessionRepository.Revoke(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err = sessionUseCase.refreshTokenRepository.RevokeFamily(ctx, id); err != nil {
+		return err
+	}
+
+	// no access token of the session outlives the longest lived one issued now
+	if err = sessionUseCase.revocationStore.Revoke(ctx, id, time.Now().Add(sessionUseCase.accessTokenTTL)); err != nil {
+		return err
+	}
+
+	return
+}
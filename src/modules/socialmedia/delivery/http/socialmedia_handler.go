@@ -2,12 +2,13 @@ package delivery
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	"github.com/gusrylmubarok/mygram-backend/src/helpers"
 	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 )
 
 type socialMediaHandler struct {
@@ -20,11 +21,11 @@ func NewSocialMediaHandler(routers *gin.Engine, socialMediaUseCase domain.Social
 	router := routers.Group("/api/v1/socialmedia")
 	{
 		router.Use(middleware.Authentication())
-		router.POST("", handler.CreateSocialMedia)
-		router.PUT("/:socialMediaId", middleware.AuthorizationSocialMedia(handler.socialMediaUseCase), handler.UpdateSocialMedia)
-		router.DELETE("/:socialMediaId", middleware.AuthorizationSocialMedia(handler.socialMediaUseCase), handler.DeleteSocialMedia)
-		router.GET("/by-user/:userId", handler.GetAllByUser)
-		router.GET("/:socialMediaId", handler.GetBySocialMediaId)
+		router.POST("", middleware.RequireScope(domain.ScopeSocialMediasWrite), handler.CreateSocialMedia)
+		router.PUT("/:socialMediaId", middleware.RequireScope(domain.ScopeSocialMediasWrite), middleware.AuthorizationSocialMedia(handler.socialMediaUseCase), handler.UpdateSocialMedia)
+		router.DELETE("/:socialMediaId", middleware.RequireScope(domain.ScopeSocialMediasWrite), middleware.AuthorizationSocialMedia(handler.socialMediaUseCase), handler.DeleteSocialMedia)
+		router.GET("/by-user/:userId", middleware.RequireScope(domain.ScopeSocialMediasRead), handler.GetAllByUser)
+		router.GET("/:socialMediaId", middleware.RequireScope(domain.ScopeSocialMediasRead), handler.GetBySocialMediaId)
 
 	}
 }
@@ -40,6 +41,7 @@ func NewSocialMediaHandler(routers *gin.Engine, socialMediaUseCase domain.Social
 // @Failure     400		{object}		helpers.ResponseMessage
 // @Failure     401		{object}		helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedias		[post]
 func (handler *socialMediaHandler) CreateSocialMedia(ctx *gin.Context) {
 	var (
@@ -47,8 +49,7 @@ func (handler *socialMediaHandler) CreateSocialMedia(ctx *gin.Context) {
 		err         error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err = ctx.ShouldBindJSON(&socialMedia); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -87,12 +88,13 @@ func (handler *socialMediaHandler) CreateSocialMedia(ctx *gin.Context) {
 // @Accept      json
 // @Produce     json
 // @Param       id		path      string	true	"SocialMedia ID"
-// @Param		json	body				domain.UpdateSocialMedia	true	"Update Social Media"
+// @Param       json	body				domain.UpdateSocialMedia	true	"Update Social Media"
 // @Success     200		{object}			domain.UpdatedSocialMedia
 // @Failure     400		{object}			helpers.ResponseMessage
 // @Failure     401		{object}			helpers.ResponseMessage
 // @Failure     404		{object}			helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedias/{id} [put]
 func (handler *socialMediaHandler) UpdateSocialMedia(ctx *gin.Context) {
 	var (
@@ -101,8 +103,6 @@ func (handler *socialMediaHandler) UpdateSocialMedia(ctx *gin.Context) {
 	)
 
 	socialMediaID := ctx.Param("socialMediaId")
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
 
 	if err = ctx.ShouldBindJSON(&socialMedia); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -113,8 +113,8 @@ func (handler *socialMediaHandler) UpdateSocialMedia(ctx *gin.Context) {
 		return
 	}
 
+	// the owner is kept, a moderator may be the one editing
 	updatedSocialMedia := domain.SocialMedia{
-		UserID:         userID,
 		Name:           socialMedia.Name,
 		SocialMediaUrl: socialMedia.SocialMediaUrl,
 	}
@@ -153,6 +153,7 @@ func (handler *socialMediaHandler) UpdateSocialMedia(ctx *gin.Context) {
 // @Failure     401  {object}	helpers.ResponseMessage
 // @Failure     404  {object}	helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedias/{id} [delete]
 func (handler *socialMediaHandler) DeleteSocialMedia(ctx *gin.Context) {
 	socialMediaID := ctx.Param("socialMediaId")
@@ -174,45 +175,54 @@ func (handler *socialMediaHandler) DeleteSocialMedia(ctx *gin.Context) {
 
 // GetAllByUser godoc
 // @Summary    	Fetch all social media
-// @Description	Get all social media with authentication user
+// @Description	Get a page of the social media of a user, named name with name
 // @Tags        socialmedias
 // @Accept      json
 // @Produce     json
-// @Success     200	{object}	domain.GetDataSocialMedia
+// @Param       userId		path		string	true	"User ID"
+// @Param       name		query		string	false	"Only the social media with the name"
+// @Param       cursor		query		string	false	"next_cursor of the previous page"
+// @Param       limit		query		int		false	"Page size, 20 by default and 100 at most"
+// @Param       sort		query		string	false	"-created_at for the most recent first, by default, or created_at"
+// @Success     200	{object}	domain.GetAllSocialMedias
 // @Failure     400	{object}	helpers.ResponseMessage
 // @Failure     401	{object}	helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedia/by-user/{userId}	[get]
 func (handler *socialMediaHandler) GetAllByUser(ctx *gin.Context) {
-	var (
-		socialMedias []domain.SocialMedia
-		err          error
-	)
+	var socialMedias []domain.SocialMedia
 
 	userID := ctx.Param("userId")
 
-	if err = handler.socialMediaUseCase.FindAllByUser(ctx.Request.Context(), &socialMedias, userID); err != nil {
+	params, err := pagination.Parse(ctx.Request.URL.Query(), "name")
+	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
 			Message: err.Error(),
 		})
-
 		return
 	}
 
-	if len(socialMedias) < 1 {
+	if err = handler.socialMediaUseCase.FindAllByUser(ctx.Request.Context(), &socialMedias, userID, params); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 			Status:  "fail",
-			Message: "social media is empty",
+			Message: err.Error(),
 		})
+
 		return
 	}
 
-	ctx.JSON(http.StatusOK, helpers.ResponseData{
+	socialMedias, meta := pagination.Trim(socialMedias, params, func(socialMedia domain.SocialMedia) (*time.Time, string) {
+		return socialMedia.CreatedAt, socialMedia.ID
+	})
+
+	ctx.JSON(http.StatusOK, domain.GetAllSocialMedias{
 		Status: "success",
 		Data: domain.GetDataSocialMedia{
-			SocialMedias: socialMedias,
+			SocialMedias: append([]domain.SocialMedia{}, socialMedias...),
 		},
+		Meta: meta,
 	})
 }
 
@@ -226,6 +236,7 @@ func (handler *socialMediaHandler) GetAllByUser(ctx *gin.Context) {
 // @Failure     400	{object}	helpers.ResponseMessage
 // @Failure     401	{object}	helpers.ResponseMessage
 // @Security    Bearer
+// @Security    ApiKey
 // @Router      /socialmedia/{socialMediaId}	[get]
 func (handler *socialMediaHandler) GetBySocialMediaId(ctx *gin.Context) {
 	var (
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	"gorm.io/gorm"
 
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -69,14 +70,14 @@ func (socialMediaRepository *socialMediaRepository) DeleteById(ctx context.Conte
 	return
 }
 
-func (socialMediaRepository *socialMediaRepository) FindAllByUser(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string) (err error) {
+func (socialMediaRepository *socialMediaRepository) FindAllByUser(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string, params pagination.Params) (err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 
 	defer cancel()
 
 	if err = socialMediaRepository.db.WithContext(ctx).Where("user_id = ?", userID).Preload("User", func(db *gorm.DB) *gorm.DB {
 		return db.Select("ID", "Email", "Username")
-	}).Find(&socialMedias).Error; err != nil {
+	}).Scopes(pagination.Scope(params, "created_at", "id")).Find(&socialMedias).Error; err != nil {
 		return err
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 )
 
 type socialMediaUseCase struct {
@@ -38,8 +39,8 @@ func (socialMediaUseCase *socialMediaUseCase) DeleteById(ctx context.Context, id
 	return
 }
 
-func (socialMediaUseCase *socialMediaUseCase) FindAllByUser(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string) (err error) {
-	if err = socialMediaUseCase.socialMediaRepository.FindAllByUser(ctx, socialMedias, userID); err != nil {
+func (socialMediaUseCase *socialMediaUseCase) FindAllByUser(ctx context.Context, socialMedias *[]domain.SocialMedia, userID string, params pagination.Params) (err error) {
+	if err = socialMediaUseCase.socialMediaRepository.FindAllByUser(ctx, socialMedias, userID, params); err != nil {
 		return err
 	}
 
@@ -0,0 +1,30 @@
+package delivery
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/signing"
+)
+
+type jwksHandler struct {
+	keyRing *signing.KeyRing
+}
+
+func NewJWKSHandler(routers *gin.Engine, keyRing *signing.KeyRing) {
+	handler := &jwksHandler{keyRing}
+
+	routers.GET("/.well-known/jwks.json", handler.GetJWKS)
+}
+
+// GetJWKS godoc
+// @Summary			Get the json web key set
+// @Description		Get the public keys tokens are signed with, so other services can verify them
+// @Tags			token
+// @Produce			json
+// @Success			200		{object}		domain.JWKS
+// @Router			/.well-known/jwks.json		[get]
+func (handler *jwksHandler) GetJWKS(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, handler.keyRing.JWKS())
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// revocationStore keeps revocations in process memory. It is meant for tests
+// and single instance deployments, revocations are lost on restart.
+type revocationStore struct {
+	mu       sync.RWMutex
+	revoked  map[string]time.Time
+	versions map[string]uint
+}
+
+func NewRevocationStore() *revocationStore {
+	return &revocationStore{
+		revoked:  map[string]time.Time{},
+		versions: map[string]uint{},
+	}
+}
+
+func (revocationStore *revocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) (err error) {
+	revocationStore.mu.Lock()
+	defer revocationStore.mu.Unlock()
+
+	now := time.Now()
+
+	for revokedJTI, revokedExpiresAt := range revocationStore.revoked {
+		if revokedExpiresAt.Before(now) {
+			delete(revocationStore.revoked, revokedJTI)
+		}
+	}
+
+	revocationStore.revoked[jti] = expiresAt
+
+	return
+}
+
+func (revocationStore *revocationStore) IsRevoked(ctx context.Context, jti string) (revoked bool, err error) {
+	revocationStore.mu.RLock()
+	defer revocationStore.mu.RUnlock()
+
+	_, revoked = revocationStore.revoked[jti]
+
+	return revoked, nil
+}
+
+func (revocationStore *revocationStore) TokenVersion(ctx context.Context, userID string) (version uint, err error) {
+	revocationStore.mu.RLock()
+	defer revocationStore.mu.RUnlock()
+
+	return revocationStore.versions[userID], nil
+}
+
+func (revocationStore *revocationStore) BumpTokenVersion(ctx context.Context, userID string) (version uint, err error) {
+	revocationStore.mu.Lock()
+	defer revocationStore.mu.Unlock()
+
+	revocationStore.versions[userID]++
+
+	return revocationStore.versions[userID], nil
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+type refreshTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewRefreshTokenRepository(db *gorm.DB) *refreshTokenRepository {
+	return &refreshTokenRepository{db}
+}
+
+func (refreshTokenRepository *refreshTokenRepository) Save(ctx context.Context, refreshToken *domain.RefreshToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	refreshToken.ID = fmt.Sprintf("refreshtoken-%s", ID)
+
+	if refreshToken.FamilyID == "" {
+		refreshToken.FamilyID = refreshToken.ID
+	}
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Create(&refreshToken).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (refreshTokenRepository *refreshTokenRepository) FindByHash(ctx context.Context, refreshToken *domain.RefreshToken, tokenHash string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// the role is needed along, the refreshed access token carries it
+	if err = refreshTokenRepository.db.WithContext(ctx).Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Select("id", "username", "email", "role")
+	}).First(&refreshToken, "token_hash = ?", tokenHash).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+// Rotate marks current as used and stores next in the same transaction. The
+// update only matches a token that has not been revoked yet, so two requests
+// racing with the same refresh token can never both succeed.
+func (refreshTokenRepository *refreshTokenRepository) Rotate(ctx context.Context, current domain.RefreshToken, next *domain.RefreshToken) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	ID, _ := gonanoid.New(16)
+
+	next.ID = fmt.Sprintf("refreshtoken-%s", ID)
+	next.FamilyID = current.FamilyID
+
+	return refreshTokenRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.RefreshToken{}).Where("id = ? AND revoked_at IS NULL", current.ID).Updates(map[string]interface{}{
+			"revoked_at":  time.Now(),
+			"replaced_by": next.ID,
+		})
+
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return domain.ErrRefreshTokenReused
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+func (refreshTokenRepository *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("family_id = ? AND revoked_at IS NULL", familyID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (refreshTokenRepository *refreshTokenRepository) RevokeAllByUser(ctx context.Context, userID string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = refreshTokenRepository.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("user_id = ? AND revoked_at IS NULL", userID).Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return
+}
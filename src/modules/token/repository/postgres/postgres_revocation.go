@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type revocationStore struct {
+	db *gorm.DB
+}
+
+func NewRevocationStore(db *gorm.DB) *revocationStore {
+	return &revocationStore{db}
+}
+
+func (revocationStore *revocationStore) Revoke(ctx context.Context, jti string, expiresAt time.Time) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	revokedToken := domain.RevokedToken{
+		JTI:       jti,
+		ExpiresAt: &expiresAt,
+	}
+
+	if err = revocationStore.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&revokedToken).Error; err != nil {
+		return err
+	}
+
+	// expired tokens are rejected by their exp claim, there is no need to keep them
+	if err = revocationStore.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&domain.RevokedToken{}).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (revocationStore *revocationStore) IsRevoked(ctx context.Context, jti string) (revoked bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var count int64
+
+	if err = revocationStore.db.WithContext(ctx).Model(&domain.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count != 0, nil
+}
+
+func (revocationStore *revocationStore) TokenVersion(ctx context.Context, userID string) (version uint, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var tokenVersion domain.UserTokenVersion
+
+	if err = revocationStore.db.WithContext(ctx).First(&tokenVersion, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+
+		return 0, err
+	}
+
+	return tokenVersion.Version, nil
+}
+
+func (revocationStore *revocationStore) BumpTokenVersion(ctx context.Context, userID string) (version uint, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = revocationStore.db.WithContext(ctx).Raw(
+		`INSERT INTO user_token_versions (user_id, version, updated_at) VALUES (?, 1, ?)
+		ON CONFLICT (user_id) DO UPDATE SET version = user_token_versions.version + 1, updated_at = EXCLUDED.updated_at
+		RETURNING version`,
+		userID, time.Now(),
+	).Scan(&version).Error; err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
@@ -0,0 +1,245 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"gorm.io/gorm"
+)
+
+type tokenUseCase struct {
+	refreshTokenRepository domain.RefreshTokenRepository
+	sessionRepository      domain.SessionRepository
+	revocationStore        domain.RevocationStore
+	tokenSigner            domain.TokenSigner
+	accessTokenTTL         time.Duration
+	refreshTokenTTL        time.Duration
+}
+
+func NewTokenUseCase(refreshTokenRepository domain.RefreshTokenRepository, sessionRepository domain.SessionRepository, revocationStore domain.RevocationStore, tokenSigner domain.TokenSigner, accessTokenTTL time.Duration, refreshTokenTTL time.Duration) *tokenUseCase {
+	return &tokenUseCase{refreshTokenRepository, sessionRepository, revocationStore, tokenSigner, accessTokenTTL, refreshTokenTTL}
+}
+
+// Issue starts a new session of the user on the client, creating an access
+// token and the first refresh token of the session.
+func (tokenUseCase *tokenUseCase) Issue(ctx context.Context, user domain.User, client domain.SessionClient) (token domain.Token, err error) {
+	var refreshToken string
+
+	now := time.Now()
+	expiresAt := now.Add(tokenUseCase.refreshTokenTTL)
+	session := domain.Session{
+		UserID:     user.ID,
+		UserAgent:  truncate(client.UserAgent, 255),
+		IP:         truncate(client.IP, 45),
+		LastSeenAt: &now,
+		ExpiresAt:  &expiresAt,
+	}
+
+	if err = tokenUseCase.sessionRepository.Save(ctx, &session); err != nil {
+		return token, err
+	}
+
+	stored := domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  session.ID,
+		ExpiresAt: &expiresAt,
+	}
+
+	if refreshToken, err = tokenUseCase.newRefreshToken(&stored); err != nil {
+		return token, err
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.Save(ctx, &stored); err != nil {
+		return token, err
+	}
+
+	return tokenUseCase.newToken(ctx, user, session.ID, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new token pair. Every refresh token
+// can be used once, presenting one that was already rotated is treated as
+// theft and revokes the whole family along with its session.
+func (tokenUseCase *tokenUseCase) Refresh(ctx context.Context, refreshToken string) (token domain.Token, err error) {
+	var (
+		current      domain.RefreshToken
+		nextToken    string
+		refreshedAt  = time.Now()
+		nextExpireAt = refreshedAt.Add(tokenUseCase.refreshTokenTTL)
+	)
+
+	if refreshToken == "" {
+		return token, domain.ErrInvalidRefreshToken
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.FindByHash(ctx, &current, helpers.HashToken(refreshToken)); err != nil {
+		return token, domain.ErrInvalidRefreshToken
+	}
+
+	if current.RevokedAt != nil {
+		// a token revoked by logout was never rotated, only a rotated one signals reuse
+		if current.ReplacedBy == "" {
+			return token, domain.ErrInvalidRefreshToken
+		}
+
+		if err = tokenUseCase.revokeFamily(ctx, current); err != nil {
+			return token, err
+		}
+
+		return token, domain.ErrRefreshTokenReused
+	}
+
+	if current.ExpiresAt == nil || refreshedAt.After(*current.ExpiresAt) || current.User == nil {
+		return token, domain.ErrInvalidRefreshToken
+	}
+
+	next := domain.RefreshToken{
+		UserID:    current.UserID,
+		ExpiresAt: &nextExpireAt,
+	}
+
+	if nextToken, err = tokenUseCase.newRefreshToken(&next); err != nil {
+		return token, err
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.Rotate(ctx, current, &next); err != nil {
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			if err = tokenUseCase.revokeFamily(ctx, current); err != nil {
+				return token, err
+			}
+
+			return token, domain.ErrRefreshTokenReused
+		}
+
+		return token, err
+	}
+
+	// the session outlives its last refresh token no longer than a fresh login would
+	if err = tokenUseCase.sessionRepository.Touch(ctx, current.FamilyID, refreshedAt, nextExpireAt); err != nil {
+		return token, err
+	}
+
+	return tokenUseCase.newToken(ctx, *current.User, current.FamilyID, nextToken)
+}
+
+// Revoke logs out a single session: the access token identified by jti is
+// rejected until it expires and the refresh token family, when given, can no
+// longer be used.
+func (tokenUseCase *tokenUseCase) Revoke(ctx context.Context, userID string, jti string, expiresAt time.Time, refreshToken string) (err error) {
+	if err = tokenUseCase.revocationStore.Revoke(ctx, jti, expiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return
+	}
+
+	var current domain.RefreshToken
+
+	if err = tokenUseCase.refreshTokenRepository.FindByHash(ctx, &current, helpers.HashToken(refreshToken)); err != nil || current.UserID != userID {
+		return domain.ErrInvalidRefreshToken
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.RevokeFamily(ctx, current.FamilyID); err != nil {
+		return err
+	}
+
+	// sessions are refresh token families, logins from before sessions have none
+	if err = tokenUseCase.sessionRepository.Revoke(ctx, current.FamilyID, userID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// RevokeAll logs the user out everywhere by invalidating every access token
+// issued so far and every refresh token of the user.
+func (tokenUseCase *tokenUseCase) RevokeAll(ctx context.Context, userID string) (err error) {
+	if _, err = tokenUseCase.revocationStore.BumpTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+
+	if err = tokenUseCase.refreshTokenRepository.RevokeAllByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	if err = tokenUseCase.sessionRepository.RevokeAllByUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return
+}
+
+// revokeFamily ends the session of the refresh token after a reuse: its
+// refresh tokens, the session and the access tokens issued for it.
+func (tokenUseCase *tokenUseCase) revokeFamily(ctx context.Context, current domain.RefreshToken) (err error) {
+	if err = tokenUseCase.refreshTokenRepository.RevokeFamily(ctx, current.FamilyID); err != nil {
+		return err
+	}
+
+	// sessions are refresh token families, logins from before sessions have none
+	if err = tokenUseCase.sessionRepository.Revoke(ctx, current.FamilyID, current.UserID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	// no access token of the session outlives the longest lived one issued now
+	if err = tokenUseCase.revocationStore.Revoke(ctx, current.FamilyID, time.Now().Add(tokenUseCase.accessTokenTTL)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (tokenUseCase *tokenUseCase) newRefreshToken(refreshToken *domain.RefreshToken) (token string, err error) {
+	if token, err = helpers.GenerateSecureToken(); err != nil {
+		return "", err
+	}
+
+	refreshToken.TokenHash = helpers.HashToken(token)
+
+	return token, nil
+}
+
+// newToken issues the access token of the session of the user along with
+// the refresh token. The access token carries the user's current token
+// version in the revocation store, otherwise Authentication rejects it.
+func (tokenUseCase *tokenUseCase) newToken(ctx context.Context, user domain.User, sessionID string, refreshToken string) (token domain.Token, err error) {
+	var (
+		accessToken string
+		version     uint
+	)
+
+	if version, err = tokenUseCase.revocationStore.TokenVersion(ctx, user.ID); err != nil {
+		return token, err
+	}
+
+	claims := domain.TokenClaims{
+		"id":    user.ID,
+		"email": user.Email,
+		"role":  user.Role,
+		"sid":   sessionID,
+		"ver":   version,
+	}
+
+	if accessToken, err = tokenUseCase.tokenSigner.Sign(domain.TokenTypeAccess, claims, tokenUseCase.accessTokenTTL); err != nil {
+		return token, err
+	}
+
+	return domain.Token{
+		Token:                 accessToken,
+		TokenType:             "Bearer",
+		ExpiresIn:             int64(tokenUseCase.accessTokenTTL.Seconds()),
+		RefreshToken:          refreshToken,
+		RefreshTokenExpiresIn: int64(tokenUseCase.refreshTokenTTL.Seconds()),
+	}, nil
+}
+
+func truncate(value string, length int) string {
+	if len(value) > length {
+		return value[:length]
+	}
+
+	return value
+}
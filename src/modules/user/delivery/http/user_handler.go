@@ -1,30 +1,54 @@
 package delivery
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt"
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	"github.com/gusrylmubarok/mygram-backend/src/helpers"
 	"github.com/gusrylmubarok/mygram-backend/src/middleware"
 )
 
 type userHandler struct {
-	userUseCase domain.UserUseCase
+	userUseCase              domain.UserUseCase
+	tokenUseCase             domain.TokenUseCase
+	emailVerificationUseCase domain.EmailVerificationUseCase
+	mfaUseCase               domain.MFAUseCase
+	loginAttemptUseCase      domain.LoginAttemptUseCase
+	apiKeyUseCase            domain.APIKeyUseCase
 }
 
-func NewUserHandler(routers *gin.Engine, userUseCase domain.UserUseCase) *userHandler {
-	handler := &userHandler{userUseCase}
+func NewUserHandler(routers *gin.Engine, userUseCase domain.UserUseCase, tokenUseCase domain.TokenUseCase, emailVerificationUseCase domain.EmailVerificationUseCase, mfaUseCase domain.MFAUseCase, loginAttemptUseCase domain.LoginAttemptUseCase, apiKeyUseCase domain.APIKeyUseCase) *userHandler {
+	handler := &userHandler{userUseCase, tokenUseCase, emailVerificationUseCase, mfaUseCase, loginAttemptUseCase, apiKeyUseCase}
 
 	router := routers.Group("/api/v1/user")
 	{
 		router.POST("/register", handler.Register)
 		router.POST("/login", handler.Login)
-		router.PUT("", middleware.Authentication(), handler.Update)
-		router.DELETE("", middleware.Authentication(), handler.Delete)
+		router.POST("/refresh", handler.Refresh)
+		router.POST("/logout", middleware.Authentication(), middleware.SessionOnly(), handler.Logout)
+		router.POST("/logout/all", middleware.Authentication(), middleware.SessionOnly(), handler.LogoutAll)
+		router.PUT("", middleware.Authentication(), middleware.SessionOnly(), handler.Update)
+		router.PUT("/password", middleware.Authentication(), middleware.SessionOnly(), handler.ChangePassword)
+		router.DELETE("", middleware.Authentication(), middleware.SessionOnly(), handler.Delete)
+	}
+
+	adminRouter := routers.Group("/api/v1/admin/user")
+	{
+		adminRouter.Use(middleware.Authentication(), middleware.SessionOnly(), middleware.RequireRole(domain.RoleAdmin))
+		adminRouter.PUT("/:userId/role", handler.UpdateRole)
+	}
+
+	usersRouter := routers.Group("/api/v1/users")
+	{
+		usersRouter.GET("/:username", handler.Profile)
 	}
 
 	return handler
@@ -87,6 +111,11 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 		return
 	}
 
+	// the account exists already, a lost mail can be sent again from /user/email/verification
+	if err = handler.emailVerificationUseCase.Send(ctx.Request.Context(), user); err != nil {
+		log.Println("Error sending email verification: ", err)
+	}
+
 	ctx.JSON(http.StatusCreated, domain.RegisteredUser{
 		Status:  "success",
 		Message: "user registration has been successful",
@@ -100,21 +129,26 @@ func (handler *userHandler) Register(ctx *gin.Context) {
 
 // Login godoc
 // @Summary			Login a user
-// @Description		Authentication a user and retrieve a token
+// @Description		Authentication a user and retrieve a token, or a challenge token to complete at /user/login/mfa when two-factor authentication is enabled
 // @Tags			user
 // @Accept			json
 // @Produce			json
 // @Param			json	body			domain.LoginUser	true	"Login User"
 // @Success			200		{object}		domain.LoggedInUser
+// @Success			202		{object}		domain.MFARequired
 // @Failure			400		{object}		helpers.ResponseMessage
 // @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			429		{object}		helpers.ResponseMessage
 // @Router			/user/login		[post]
 func (handler *userHandler) Login(ctx *gin.Context) {
 	var (
-		input domain.LoginUser
-		user  domain.User
-		err   error
-		token string
+		input      domain.LoginUser
+		user       domain.User
+		err        error
+		token      domain.Token
+		mfaEnabled bool
+		challenge  domain.MFAChallenge
+		retryAfter time.Duration
 	)
 
 	if err = ctx.ShouldBindJSON(&input); err != nil {
@@ -128,8 +162,29 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 	user.Email = input.Email
 	user.Password = input.Password
 
+	if retryAfter, err = handler.loginAttemptUseCase.Check(ctx.Request.Context(), input.Email, ctx.ClientIP()); err != nil {
+		if errors.Is(err, domain.ErrTooManyLoginAttempts) {
+			ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
 	if err = handler.userUseCase.Login(ctx.Request.Context(), &user); err != nil {
 		if strings.Contains(err.Error(), "the credential you entered are wrong") {
+			if failErr := handler.loginAttemptUseCase.Fail(ctx.Request.Context(), input.Email, ctx.ClientIP()); failErr != nil {
+				log.Println("Error recording failed login: ", failErr)
+			}
+
 			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
 				Status:  "unauthenticated",
 				Message: err.Error(),
@@ -144,7 +199,37 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	if token = middleware.GenerateToken(user.ID, user.Email); err != nil {
+	if mfaEnabled, err = handler.mfaUseCase.IsEnabled(ctx.Request.Context(), user.ID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if mfaEnabled {
+		if challenge, err = handler.mfaUseCase.Challenge(ctx.Request.Context(), user); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusAccepted, domain.MFARequired{
+			Status:  "mfa_required",
+			Message: "enter the code of your authenticator app to proceed",
+			Data:    challenge,
+		})
+		return
+	}
+
+	// with two-factor authentication the failed logins are kept until the code passes
+	if err = handler.loginAttemptUseCase.Succeed(ctx.Request.Context(), input.Email); err != nil {
+		log.Println("Error clearing failed logins: ", err)
+	}
+
+	if token, err = handler.tokenUseCase.Issue(ctx.Request.Context(), user, middleware.Client(ctx)); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
 			"error":   "unauthenticated",
 			"message": err.Error(),
@@ -155,15 +240,133 @@ func (handler *userHandler) Login(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, domain.LoggedInUser{
 		Status:  "success",
 		Message: "user login has beed successful",
-		Data: domain.Token{
-			Token: token,
-		},
+		Data:    token,
+	})
+}
+
+// Refresh godoc
+// @Summary			Refresh a token
+// @Description		Exchange a refresh token for a new access token and refresh token, the given refresh token can't be used again
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.RefreshUserToken	true	"Refresh Token"
+// @Success			200		{object}		domain.RefreshedToken
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Router			/user/refresh		[post]
+func (handler *userHandler) Refresh(ctx *gin.Context) {
+	var (
+		input domain.RefreshUserToken
+		token domain.Token
+		err   error
+	)
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if token, err = handler.tokenUseCase.Refresh(ctx.Request.Context(), input.RefreshToken); err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) || errors.Is(err, domain.ErrRefreshTokenReused) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, helpers.ResponseMessage{
+				Status:  "unauthenticated",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.RefreshedToken{
+		Status:  "success",
+		Message: "token has been refreshed",
+		Data:    token,
+	})
+}
+
+// Logout godoc
+// @Summary			Logout a user
+// @Description		Revoke the access token of the request and the given refresh token
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json	body			domain.LogoutUser	false	"Logout User"
+// @Success			200		{object}		domain.LoggedOutUser
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/logout		[post]
+func (handler *userHandler) Logout(ctx *gin.Context) {
+	var (
+		input domain.LogoutUser
+		err   error
+	)
+
+	principal := middleware.CurrentPrincipal(ctx)
+
+	if ctx.Request.ContentLength != 0 {
+		if err = ctx.ShouldBindJSON(&input); err != nil {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	if err = handler.tokenUseCase.Revoke(ctx.Request.Context(), principal.UserID, principal.TokenID, principal.ExpiresAt, input.RefreshToken); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.LoggedOutUser{
+		Status:  "success",
+		Message: "you have been logged out",
+	})
+}
+
+// LogoutAll godoc
+// @Summary			Logout a user everywhere
+// @Description		Revoke every access token and refresh token of the authentication user
+// @Tags			user
+// @Produce			json
+// @Success			200		{object}		domain.LoggedOutUser
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/logout/all		[post]
+func (handler *userHandler) LogoutAll(ctx *gin.Context) {
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err := handler.tokenUseCase.RevokeAll(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.LoggedOutUser{
+		Status:  "success",
+		Message: "you have been logged out from every device",
 	})
 }
 
 // Update godoc
 // @Summary			Update a user
-// @Description		Update a user with authentication user
+// @Description		Update a user with authentication user, a new email replaces the current one once it is verified, empty fields are left unchanged
 // @Tags			user
 // @Accept			json
 // @Produce			json
@@ -181,8 +384,7 @@ func (handler *userHandler) Update(ctx *gin.Context) {
 		err   error
 	)
 
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err = ctx.ShouldBindJSON(&input); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
@@ -195,6 +397,10 @@ func (handler *userHandler) Update(ctx *gin.Context) {
 	user.Email = input.Email
 	user.Username = input.Username
 	user.Age = input.Age
+	user.DisplayName = input.DisplayName
+	user.Bio = input.Bio
+	user.AvatarURL = input.AvatarURL
+	user.Website = input.Website
 	user.ID = userID
 
 	if user, err = handler.userUseCase.Update(ctx.Request.Context(), user); err != nil {
@@ -221,15 +427,205 @@ func (handler *userHandler) Update(ctx *gin.Context) {
 		return
 	}
 
+	message := "update user successfully"
+
+	if input.Email != "" && input.Email == user.PendingEmail {
+		if err = handler.emailVerificationUseCase.Send(ctx.Request.Context(), user); err != nil {
+			log.Println("Error sending email verification: ", err)
+		}
+
+		message = "update user successfully, confirm your new email address with the link sent to it"
+	}
+
 	ctx.JSON(http.StatusOK, domain.UpdatedUser{
 		Status:  "success",
-		Message: "update user successfully",
+		Message: message,
 		Data: domain.UpdatedDataUser{
-			ID:        user.ID,
-			Email:     user.Email,
-			Username:  user.Username,
-			Age:       user.Age,
-			UpdatedAt: user.UpdatedAt,
+			ID:           user.ID,
+			Email:        user.Email,
+			PendingEmail: user.PendingEmail,
+			Username:     user.Username,
+			Age:          user.Age,
+			DisplayName:  user.DisplayName,
+			Bio:          user.Bio,
+			AvatarURL:    user.AvatarURL,
+			Website:      user.Website,
+			UpdatedAt:    user.UpdatedAt,
+		},
+	})
+}
+
+// Profile godoc
+// @Summary			Fetch the profile of a user
+// @Description		Get the public profile of a user by its username, the email and the age are never part of it
+// @Tags			user
+// @Produce			json
+// @Param			username	path			string	true	"Username"
+// @Success			200			{object}		domain.FetchedUserProfile
+// @Failure			404			{object}		helpers.ResponseMessage
+// @Router			/users/{username}		[get]
+func (handler *userHandler) Profile(ctx *gin.Context) {
+	var (
+		profile domain.UserProfile
+		err     error
+	)
+
+	username := ctx.Param("username")
+
+	if profile, err = handler.userUseCase.FindProfile(ctx.Request.Context(), username); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: fmt.Sprintf("user with username %s doesn't exist", username),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.FetchedUserProfile{
+		Status:  "success",
+		Message: "the profile has been successfully fetched",
+		Data:    profile,
+	})
+}
+
+// ChangePassword godoc
+// @Summary			Change password
+// @Description		Change the password of the authentication user, every other session is signed out, the api keys are revoked and a new token is returned
+// @Tags			user
+// @Accept			json
+// @Produce			json
+// @Param			json		body			domain.ChangePassword	true	"Change Password"
+// @Success			200			{object}		domain.ChangedPassword
+// @Failure			400			{object}		helpers.ResponseMessage
+// @Failure			401			{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/user/password	[put]
+func (handler *userHandler) ChangePassword(ctx *gin.Context) {
+	var (
+		input domain.ChangePassword
+		user  domain.User
+		token domain.Token
+		err   error
+	)
+
+	userID := middleware.CurrentPrincipal(ctx).UserID
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.userUseCase.UpdatePassword(ctx.Request.Context(), userID, input.CurrentPassword, input.NewPassword); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.tokenUseCase.RevokeAll(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err = handler.apiKeyUseCase.RevokeAllByUser(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// the token of this request was revoked with the others, the caller keeps its session with a new one
+	if user, err = handler.userUseCase.FindById(ctx.Request.Context(), userID); err == nil {
+		token, err = handler.tokenUseCase.Issue(ctx.Request.Context(), user, middleware.Client(ctx))
+	}
+
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.ChangedPassword{
+		Status:  "success",
+		Message: "your password has been changed and every other session has been signed out",
+		Data:    token,
+	})
+}
+
+// UpdateRole godoc
+// @Summary			Update the role of a user
+// @Description		Make a user a user, moderator or admin, admin only. The user is signed out everywhere so the new role applies to the next token
+// @Tags			admin
+// @Accept			json
+// @Produce			json
+// @Param			userId	path			string				true	"User ID"
+// @Param			json	body			domain.UpdateRole	true	"Update Role"
+// @Success			200		{object}		domain.UpdatedRole
+// @Failure			400		{object}		helpers.ResponseMessage
+// @Failure			401		{object}		helpers.ResponseMessage
+// @Failure			403		{object}		helpers.ResponseMessage
+// @Failure			404		{object}		helpers.ResponseMessage
+// @Security		Bearer
+// @Router			/admin/user/{userId}/role		[put]
+func (handler *userHandler) UpdateRole(ctx *gin.Context) {
+	var (
+		input domain.UpdateRole
+		user  domain.User
+		err   error
+	)
+
+	userID := ctx.Param("userId")
+
+	if err = ctx.ShouldBindJSON(&input); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if user, err = handler.userUseCase.UpdateRole(ctx.Request.Context(), userID, input.Role); err != nil {
+		if errors.Is(err, domain.ErrInvalidRole) {
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+				Status:  "fail",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusNotFound, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: fmt.Sprintf("user with id %s doesn't exist", userID),
+		})
+		return
+	}
+
+	// the role is a claim, tokens issued before still carry the old one
+	if err = handler.tokenUseCase.RevokeAll(ctx.Request.Context(), userID); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
+			Status:  "fail",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, domain.UpdatedRole{
+		Status:  "success",
+		Message: "the role of the user has been updated",
+		Data: domain.GetUser{
+			ID:       user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
 		},
 	})
 }
@@ -247,8 +643,7 @@ func (handler *userHandler) Update(ctx *gin.Context) {
 // @Security		Bearer
 // @Router			/user/{userId}	[delete]
 func (handler *userHandler) Delete(ctx *gin.Context) {
-	userData := ctx.MustGet("userData").(jwt.MapClaims)
-	userID := string(userData["id"].(string))
+	userID := middleware.CurrentPrincipal(ctx).UserID
 
 	if err := handler.userUseCase.DeleteById(ctx, userID); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, helpers.ResponseMessage{
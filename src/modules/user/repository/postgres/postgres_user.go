@@ -38,7 +38,7 @@ func (userRepository *userRepository) Register(ctx context.Context, user *domain
 				return err
 			} else {
 				err = errors.New("duplicate key on idx_users_username")
-				return err 
+				return err
 			}
 		}
 		return err
@@ -53,44 +53,67 @@ func (userRepository *userRepository) Login(ctx context.Context, user *domain.Us
 
 	password := user.Password
 
+	// unknown emails and wrong passwords fail alike so accounts can't be enumerated
+	errCredential := errors.New("the credential you entered are wrong")
+
 	if err = userRepository.db.WithContext(ctx).Where("email = ?", user.Email).Take(&user).Error; err != nil {
-		return errors.New("the email you entered are not registered")
+		// hashing costs as much as comparing, the response time doesn't tell either
+		_, _ = helpers.Hash(password)
+
+		return errCredential
 	}
 
 	if isValid := helpers.Compare([]byte(user.Password), []byte(password)); !isValid {
-		return errors.New("the password you entered are wrong")
+		return errCredential
+	}
+
+	// the password is only known here, so this is where old hashes are upgraded
+	if helpers.NeedsRehash(user.Password) {
+		var passwordHash string
+
+		if passwordHash, err = helpers.Hash(password); err != nil {
+			return err
+		}
+
+		if err = userRepository.db.WithContext(ctx).Model(user).UpdateColumn("password", passwordHash).Error; err != nil {
+			return err
+		}
 	}
 
 	return
 }
 
+// Update changes the profile of the user. A new email only becomes the
+// pending email, the current one stays in use until VerifyEmail confirms it.
 func (userRepository *userRepository) Update(ctx context.Context, u domain.User) (user domain.User, err error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	user = domain.User{}
 
-	if err = userRepository.db.WithContext(ctx).First(&user).Error; err != nil {
+	if err = userRepository.db.WithContext(ctx).First(&user, "id = ?", u.ID).Error; err != nil {
 		return user, err
 	}
 
-	if user.Email == u.Email {
-		u.Email = ""
+	if u.Email != "" && u.Email != user.Email {
+		var count int64
+		userRepository.db.WithContext(ctx).Model(&domain.User{}).Where("email = ?", u.Email).Count(&count)
+		if count != 0 {
+			return user, errors.New("duplicate key on idx_users_email")
+		}
+
+		u.PendingEmail = u.Email
 	}
 
+	u.Email = ""
+
 	if user.Username == u.Username {
 		u.Username = ""
 	}
 
 	if err = userRepository.db.WithContext(ctx).Model(&user).Updates(&u).Error; err != nil {
 		if errors.Is(err, gorm.ErrDuplicatedKey) {
-			var count int64
-			userRepository.db.WithContext(ctx).Model(&user).Where("email = ?", user.Email).Count(&count)
-			if count != 0 {
-				return user, errors.New("duplicate key on idx_users_email")
-			} else {
-				return user, errors.New("duplicate key on idx_users_username")
-			}
+			return user, errors.New("duplicate key on idx_users_username")
 		}
 		return user, err
 	}
@@ -106,15 +129,52 @@ func (userRepository *userRepository) DeleteById(ctx context.Context, id string)
 		return err
 	}
 
-	if err = userRepository.db.WithContext(ctx).Where("user_id = ?", id).Delete(&domain.SocialMedia{}).Error; err != nil {
-		return err
-	}
+	return userRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Where("user_id = ?", id).Delete(&domain.SocialMedia{}).Error; err != nil {
+			return err
+		}
 
-	if err = userRepository.db.WithContext(ctx).Delete(&domain.User{}, &id).Error; err != nil {
-		return err
-	}
+		// the follows go with the user, so do they from the counters of the other side
+		if err = tx.Model(&domain.User{}).
+			Where("id IN (?)", tx.Model(&domain.Follow{}).Select("following_id").Where("follower_id = ?", id)).
+			UpdateColumn("follower_count", gorm.Expr("follower_count - 1")).Error; err != nil {
+			return err
+		}
 
-	return
+		if err = tx.Model(&domain.User{}).
+			Where("id IN (?)", tx.Model(&domain.Follow{}).Select("follower_id").Where("following_id = ?", id)).
+			UpdateColumn("following_count", gorm.Expr("following_count - 1")).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Where("follower_id = ? OR following_id = ?", id, id).Delete(&domain.Follow{}).Error; err != nil {
+			return err
+		}
+
+		// and so do the likes from the counters of the photos of others
+		if err = tx.Model(&domain.Photo{}).
+			Where("id IN (?)", tx.Model(&domain.Like{}).Select("photo_id").Where("user_id = ?", id)).
+			UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Where("user_id = ?", id).Delete(&domain.Like{}).Error; err != nil {
+			return err
+		}
+
+		// and so do the replies from the counters of the comments they reply to
+		if err = tx.Model(&domain.Comment{}).
+			Where("id IN (?)", tx.Model(&domain.Comment{}).Select("parent_id").Where("user_id = ? AND parent_id IS NOT NULL", id)).
+			UpdateColumn("reply_count", gorm.Expr("reply_count - (SELECT COUNT(*) FROM comments AS replies WHERE replies.parent_id = comments.id AND replies.user_id = ?)", id)).Error; err != nil {
+			return err
+		}
+
+		if err = tx.Delete(&domain.User{}, &id).Error; err != nil {
+			return err
+		}
+
+		return
+	})
 }
 
 func (userRepository *userRepository) FindByEmail(ctx context.Context, u *domain.User) (user domain.User, err error) {
@@ -138,3 +198,140 @@ func (userRepository *userRepository) FindByUsername(ctx context.Context, u *dom
 
 	return user, nil
 }
+
+func (userRepository *userRepository) FindById(ctx context.Context, id string) (user domain.User, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+// VerifyEmail marks email as verified when it is the current email of the
+// user, or makes it the current email when it is the pending one.
+func (userRepository *userRepository) VerifyEmail(ctx context.Context, id string, email string) (user domain.User, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	err = userRepository.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		columns := map[string]interface{}{}
+
+		switch {
+		case user.PendingEmail != "" && user.PendingEmail == email:
+			var count int64
+			tx.Model(&domain.User{}).Where("email = ? AND id <> ?", email, id).Count(&count)
+			if count != 0 {
+				return errors.New("duplicate key on idx_users_email")
+			}
+
+			columns["email"] = email
+			columns["pending_email"] = ""
+			columns["email_verified_at"] = now
+		case user.Email == email && user.EmailVerifiedAt == nil:
+			columns["email_verified_at"] = now
+		case user.Email == email:
+			return nil
+		default:
+			return domain.ErrInvalidEmailVerificationToken
+		}
+
+		columns["updated_at"] = now
+
+		// UpdateColumns skips the hooks, the row was validated when it was saved
+		return tx.Model(&user).UpdateColumns(columns).Error
+	})
+
+	return user, err
+}
+
+// UpdatePassword stores the hash of newPassword when currentPassword is the
+// password of the user.
+func (userRepository *userRepository) UpdatePassword(ctx context.Context, id string, currentPassword string, newPassword string) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	var passwordHash string
+
+	user := domain.User{}
+
+	if err = userRepository.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return err
+	}
+
+	if isValid := helpers.Compare([]byte(user.Password), []byte(currentPassword)); !isValid {
+		return errors.New("the current password you entered are wrong")
+	}
+
+	if passwordHash, err = helpers.Hash(newPassword); err != nil {
+		return err
+	}
+
+	// BeforeUpdate doesn't hash, so the hash is written as is without hooks
+	if err = userRepository.db.WithContext(ctx).Model(&user).UpdateColumns(map[string]interface{}{
+		"password":   passwordHash,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userRepository *userRepository) UpdateRole(ctx context.Context, id string, role string) (user domain.User, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err = userRepository.db.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+		return user, err
+	}
+
+	if err = userRepository.db.WithContext(ctx).Model(&user).UpdateColumns(map[string]interface{}{
+		"role":       role,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return user, err
+	}
+
+	user.Role = role
+
+	return user, nil
+}
+
+// FindProfile returns the public projection of the user with the given
+// username along with its counters, the follow ones are kept on the user.
+func (userRepository *userRepository) FindProfile(ctx context.Context, username string) (profile domain.UserProfile, err error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	user := domain.User{}
+
+	if err = userRepository.db.WithContext(ctx).First(&user, "username = ?", username).Error; err != nil {
+		return profile, err
+	}
+
+	profile = domain.UserProfile{
+		ID:             user.ID,
+		Username:       user.Username,
+		DisplayName:    user.DisplayName,
+		Bio:            user.Bio,
+		AvatarURL:      user.AvatarURL,
+		Website:        user.Website,
+		FollowerCount:  user.FollowerCount,
+		FollowingCount: user.FollowingCount,
+		CreatedAt:      user.CreatedAt,
+	}
+
+	if err = userRepository.db.WithContext(ctx).Model(&domain.Photo{}).Where("user_id = ?", user.ID).Count(&profile.PhotoCount).Error; err != nil {
+		return profile, err
+	}
+
+	return profile, nil
+}
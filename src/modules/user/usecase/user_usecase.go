@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
+
+	"github.com/asaskevich/govalidator"
 
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 )
@@ -31,6 +34,15 @@ func (userUseCase *userUseCase) Login(ctx context.Context, user *domain.User) (e
 }
 
 func (userUseCase *userUseCase) Update(ctx context.Context, u domain.User) (user domain.User, err error) {
+	if _, err = govalidator.ValidateStruct(domain.UpdateUser{
+		DisplayName: u.DisplayName,
+		Bio:         u.Bio,
+		AvatarURL:   u.AvatarURL,
+		Website:     u.Website,
+	}); err != nil {
+		return user, err
+	}
+
 	if user, err = userUseCase.userRepository.Update(ctx, u); err != nil {
 		return user, err
 	}
@@ -61,3 +73,55 @@ func (userUseCase *userUseCase) FindByUsername(ctx context.Context, u *domain.Us
 
 	return user, nil
 }
+
+func (userUseCase *userUseCase) FindById(ctx context.Context, id string) (user domain.User, err error) {
+	if user, err = userUseCase.userRepository.FindById(ctx, id); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (userUseCase *userUseCase) VerifyEmail(ctx context.Context, id string, email string) (user domain.User, err error) {
+	if user, err = userUseCase.userRepository.VerifyEmail(ctx, id, email); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (userUseCase *userUseCase) UpdatePassword(ctx context.Context, id string, currentPassword string, newPassword string) (err error) {
+	if _, err = govalidator.ValidateStruct(domain.ChangePassword{CurrentPassword: currentPassword, NewPassword: newPassword}); err != nil {
+		return err
+	}
+
+	if currentPassword == newPassword {
+		return errors.New("the new password must be different from the current password")
+	}
+
+	if err = userUseCase.userRepository.UpdatePassword(ctx, id, currentPassword, newPassword); err != nil {
+		return err
+	}
+
+	return
+}
+
+func (userUseCase *userUseCase) UpdateRole(ctx context.Context, id string, role string) (user domain.User, err error) {
+	if !domain.IsValidRole(role) {
+		return user, domain.ErrInvalidRole
+	}
+
+	if user, err = userUseCase.userRepository.UpdateRole(ctx, id, role); err != nil {
+		return user, err
+	}
+
+	return user, nil
+}
+
+func (userUseCase *userUseCase) FindProfile(ctx context.Context, username string) (profile domain.UserProfile, err error) {
+	if profile, err = userUseCase.userRepository.FindProfile(ctx, username); err != nil {
+		return profile, err
+	}
+
+	return profile, nil
+}
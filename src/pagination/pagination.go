@@ -0,0 +1,171 @@
+// Package pagination pages lists with opaque cursors over (created_at, id),
+// a page never skips nor repeats a row when rows are added in between.
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+
+	SortNewest = "-created_at"
+	SortOldest = "created_at"
+)
+
+var (
+	ErrInvalidCursor = errors.New("the cursor is invalid")
+	ErrInvalidLimit  = errors.New("the limit must be a positive number")
+	ErrInvalidSort   = errors.New("the sort must be created_at or -created_at")
+)
+
+// Cursor points at the last row of a page, the next page starts right after
+// it.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// Params represents the page of a list a client asks for.
+type Params struct {
+	After     *Cursor
+	Limit     int
+	Ascending bool
+	// Filters are the equality filters of the list by column
+	Filters map[string]string
+}
+
+// Meta represents where the next page of a list starts.
+type Meta struct {
+	NextCursor string `json:"next_cursor,omitempty" example:"MTY5ODc2NTQzMjEwMDAwMDAwMHxwaG90by0xMjM"`
+	HasMore    bool   `json:"has_more" example:"true"`
+}
+
+// EncodeCursor makes the opaque cursor given to clients.
+func EncodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(createdAt.UnixNano(), 10) + "|" + id))
+}
+
+// DecodeCursor reads a cursor made by EncodeCursor.
+func DecodeCursor(cursor string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	nanos, id, found := strings.Cut(string(raw), "|")
+	if !found || id == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	unixNano, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.Unix(0, unixNano), ID: id}, nil
+}
+
+// Parse reads the cursor, limit and sort params of a query and the filter
+// params the list allows, which are named after their column. An empty cursor
+// is the first page, the limit defaults to DefaultLimit and is capped at
+// MaxLimit, and the newest rows come first unless sort is created_at.
+func Parse(query url.Values, filters ...string) (params Params, err error) {
+	params.Limit = DefaultLimit
+
+	if limit := query.Get("limit"); limit != "" {
+		if params.Limit, err = strconv.Atoi(limit); err != nil || params.Limit < 1 {
+			return params, ErrInvalidLimit
+		}
+
+		if params.Limit > MaxLimit {
+			params.Limit = MaxLimit
+		}
+	}
+
+	switch query.Get("sort") {
+	case "", SortNewest:
+	case SortOldest:
+		params.Ascending = true
+	default:
+		return params, ErrInvalidSort
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := DecodeCursor(cursor)
+		if err != nil {
+			return params, err
+		}
+
+		params.After = &after
+	}
+
+	for _, filter := range filters {
+		if value := query.Get(filter); value != "" {
+			if params.Filters == nil {
+				params.Filters = map[string]string{}
+			}
+
+			params.Filters[filter] = value
+		}
+	}
+
+	return params, nil
+}
+
+// Scope narrows a query to the page of params, ordered by the createdAt and
+// id columns. It fetches one row more than the limit, Trim cuts it off and
+// tells from it whether there is a next page.
+func Scope(params Params, createdAt string, id string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		for column, value := range params.Filters {
+			db = db.Where(column+" = ?", value)
+		}
+
+		order, after := "DESC", "<"
+		if params.Ascending {
+			order, after = "ASC", ">"
+		}
+
+		if params.After != nil {
+			db = db.Where("("+createdAt+", "+id+") "+after+" (?, ?)", params.After.CreatedAt, params.After.ID)
+		}
+
+		limit := params.Limit
+		if limit < 1 {
+			limit = DefaultLimit
+		}
+
+		return db.Order(createdAt + " " + order + ", " + id + " " + order).Limit(limit + 1)
+	}
+}
+
+// Trim cuts the row fetched past the limit by Scope off items and makes the
+// cursor of the next page from the last row kept.
+func Trim[T any](items []T, params Params, key func(T) (*time.Time, string)) ([]T, Meta) {
+	limit := params.Limit
+	if limit < 1 {
+		limit = DefaultLimit
+	}
+
+	if len(items) <= limit {
+		return items, Meta{}
+	}
+
+	items = items[:limit]
+
+	createdAt, id := key(items[limit-1])
+	if createdAt == nil {
+		return items, Meta{HasMore: true}
+	}
+
+	return items, Meta{NextCursor: EncodeCursor(*createdAt, id), HasMore: true}
+}
@@ -0,0 +1,256 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+var errNoSigningKey = errors.New("no signing key is configured")
+
+// SigningKey is a key of the key ring. Keys without a private part can only
+// verify tokens, which is how retired keys are kept around until every token
+// they signed has expired.
+type SigningKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.PrivateKey
+	PublicKey  crypto.PublicKey
+}
+
+// KeyRing holds every key a token may be verified with, selected by the kid
+// header, and the key new tokens are signed with.
+type KeyRing struct {
+	mu           sync.RWMutex
+	keys         map[string]*SigningKey
+	signingKeyID string
+}
+
+func NewKeyRing() *KeyRing {
+	return &KeyRing{keys: map[string]*SigningKey{}}
+}
+
+// LoadKeyRing reads every PEM file of dir. A file named <kid>.pem holds a
+// private key, a file named <kid>.pub.pem holds a verify only public key. The
+// signing key is signingKeyID or, when empty, the private key with the
+// greatest kid so date prefixed kids rotate by simply adding a file.
+func LoadKeyRing(dir string, signingKeyID string) (*KeyRing, error) {
+	ring := NewKeyRing()
+
+	if err := ring.Reload(dir, signingKeyID); err != nil {
+		return nil, err
+	}
+
+	return ring, nil
+}
+
+// Reload replaces the keys of the ring with the ones found in dir. The ring
+// keeps serving its current keys when loading fails.
+func (ring *KeyRing) Reload(dir string, signingKeyID string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+
+	if err != nil {
+		return err
+	}
+
+	loaded := NewKeyRing()
+
+	for _, file := range files {
+		var (
+			pemBytes []byte
+			key      interface{}
+		)
+
+		if pemBytes, err = os.ReadFile(file); err != nil {
+			return err
+		}
+
+		kid := strings.TrimSuffix(filepath.Base(file), ".pem")
+
+		if strings.HasSuffix(kid, ".pub") {
+			kid = strings.TrimSuffix(kid, ".pub")
+			key, err = parsePublicKey(pemBytes)
+		} else {
+			key, err = parsePrivateKey(pemBytes)
+		}
+
+		if err != nil {
+			return fmt.Errorf("key %s: %w", file, err)
+		}
+
+		if err = loaded.Add(kid, key); err != nil {
+			return fmt.Errorf("key %s: %w", file, err)
+		}
+	}
+
+	if signingKeyID == "" {
+		signingKeyID = loaded.latestPrivateKeyID()
+	}
+
+	if err = loaded.SetSigningKey(signingKeyID); err != nil {
+		return err
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	ring.keys = loaded.keys
+	ring.signingKeyID = loaded.signingKeyID
+
+	return nil
+}
+
+// Add puts a RSA or Ed25519 key on the ring. Private keys can sign and
+// verify, public keys can only verify.
+func (ring *KeyRing) Add(kid string, key interface{}) error {
+	signingKey := &SigningKey{ID: kid}
+
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		signingKey.Method, signingKey.PrivateKey, signingKey.PublicKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		signingKey.Method, signingKey.PublicKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		signingKey.Method, signingKey.PrivateKey, signingKey.PublicKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		signingKey.Method, signingKey.PublicKey = jwt.SigningMethodEdDSA, k
+	default:
+		return fmt.Errorf("unsupported key type %T, use RSA or Ed25519", key)
+	}
+
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	if existing, ok := ring.keys[kid]; ok && existing.PrivateKey != nil && signingKey.PrivateKey == nil {
+		// a private key already verifies, don't downgrade it to its public half
+		return nil
+	}
+
+	ring.keys[kid] = signingKey
+
+	return nil
+}
+
+func (ring *KeyRing) SetSigningKey(kid string) error {
+	ring.mu.Lock()
+	defer ring.mu.Unlock()
+
+	key, ok := ring.keys[kid]
+
+	if !ok || key.PrivateKey == nil {
+		return fmt.Errorf("signing key %q: %w", kid, errNoSigningKey)
+	}
+
+	ring.signingKeyID = kid
+
+	return nil
+}
+
+func (ring *KeyRing) SigningKey() (*SigningKey, error) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	key, ok := ring.keys[ring.signingKeyID]
+
+	if !ok {
+		return nil, errNoSigningKey
+	}
+
+	return key, nil
+}
+
+func (ring *KeyRing) Key(kid string) (*SigningKey, bool) {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	key, ok := ring.keys[kid]
+
+	return key, ok
+}
+
+// JWKS returns the public part of every key of the ring.
+func (ring *KeyRing) JWKS() domain.JWKS {
+	ring.mu.RLock()
+	defer ring.mu.RUnlock()
+
+	jwks := domain.JWKS{Keys: []domain.JWK{}}
+
+	for _, key := range ring.keys {
+		jwk := domain.JWK{
+			KeyID:     key.ID,
+			Algorithm: key.Method.Alg(),
+			Use:       "sig",
+		}
+
+		switch publicKey := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(publicKey)
+		}
+
+		jwks.Keys = append(jwks.Keys, jwk)
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].KeyID < jwks.Keys[j].KeyID
+	})
+
+	return jwks
+}
+
+func (ring *KeyRing) latestPrivateKeyID() (kid string) {
+	for id, key := range ring.keys {
+		if key.PrivateKey != nil && id > kid {
+			kid = id
+		}
+	}
+
+	return kid
+}
+
+func parsePrivateKey(pemBytes []byte) (interface{}, error) {
+	block, _ := pem.Decode(pemBytes)
+
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func parsePublicKey(pemBytes []byte) (interface{}, error) {
+	block, _ := pem.Decode(pemBytes)
+
+	if block == nil {
+		return nil, errors.New("invalid PEM")
+	}
+
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return x509.ParsePKCS1PublicKey(block.Bytes)
+}
@@ -0,0 +1,99 @@
+package signing
+
+import (
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+)
+
+var errInvalidToken = errors.New("the token is invalid or has expired")
+
+// Signer signs the tokens of the api with the signing key of a key ring and
+// verifies them with any key of it. Every token is issued by issuer for
+// audience.
+type Signer struct {
+	keyRing  *KeyRing
+	issuer   string
+	audience string
+}
+
+func NewSigner(keyRing *KeyRing, issuer string, audience string) *Signer {
+	return &Signer{keyRing, issuer, audience}
+}
+
+// Sign signs the claims with the signing key of the key ring, adding the typ,
+// iss, aud, jti, iat and exp claims.
+func (signer *Signer) Sign(typ string, claims domain.TokenClaims, ttl time.Duration) (string, error) {
+	signingKey, err := signer.keyRing.SigningKey()
+
+	if err != nil {
+		return "", err
+	}
+
+	jti, err := gonanoid.New(21)
+
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	mapClaims := jwt.MapClaims{}
+
+	for name, value := range claims {
+		mapClaims[name] = value
+	}
+
+	mapClaims["typ"] = typ
+	mapClaims["iss"] = signer.issuer
+	mapClaims["aud"] = signer.audience
+	mapClaims["jti"] = jti
+	mapClaims["iat"] = now.Unix()
+	mapClaims["exp"] = now.Add(ttl).Unix()
+
+	token := jwt.NewWithClaims(signingKey.Method, mapClaims)
+	token.Header["kid"] = signingKey.ID
+
+	return token.SignedString(signingKey.PrivateKey)
+}
+
+// Parse verifies the signature, expiry, issuer and audience of the token and
+// that it was issued with the given typ.
+func (signer *Signer) Parse(typ string, stringToken string) (domain.TokenClaims, error) {
+	token, err := jwt.Parse(stringToken, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := signer.keyRing.Key(kid)
+
+		// the algorithm must be the one of the key, never the one the token asks for
+		if !ok || token.Method.Alg() != key.Method.Alg() {
+			return nil, errInvalidToken
+		}
+
+		return key.PublicKey, nil
+	})
+
+	if err != nil || !token.Valid {
+		return nil, errInvalidToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+
+	if !ok || claims["typ"] != typ {
+		return nil, errInvalidToken
+	}
+
+	now := time.Now().Unix()
+
+	if !claims.VerifyExpiresAt(now, true) || !claims.VerifyIssuedAt(now, true) || !claims.VerifyIssuer(signer.issuer, true) || !claims.VerifyAudience(signer.audience, true) {
+		return nil, errInvalidToken
+	}
+
+	if jti, _ := claims["jti"].(string); jti == "" {
+		return nil, errInvalidToken
+	}
+
+	return domain.TokenClaims(claims), nil
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+// localStore keeps the blobs as files of dir, which is served statically at
+// baseURL.
+type localStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStore(dir string, baseURL string) *localStore {
+	return &localStore{dir, strings.TrimSuffix(baseURL, "/")}
+}
+
+func (store *localStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (err error) {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// written aside and renamed so a reader never sees half a file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (store *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := store.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.ErrBlobNotFound
+	}
+
+	return file, err
+}
+
+func (store *localStore) Delete(ctx context.Context, key string) (err error) {
+	path, err := store.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (store *localStore) URL(key string) string {
+	return store.baseURL + "/" + key
+}
+
+// path maps the key into dir, keys climbing out of it are refused.
+func (store *localStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", errors.New("invalid blob key")
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", errors.New("invalid blob key")
+		}
+	}
+
+	return filepath.Join(store.dir, filepath.FromSlash(key)), nil
+}
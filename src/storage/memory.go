@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+// MemoryStore keeps the blobs in memory so tests can inspect them.
+type MemoryStore struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+	types map[string]string
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{blobs: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (store *MemoryStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	store.blobs[key] = data
+	store.types[key] = contentType
+
+	return nil
+}
+
+func (store *MemoryStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	data, found := store.blobs[key]
+	if !found {
+		return nil, domain.ErrBlobNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (store *MemoryStore) Delete(ctx context.Context, key string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	delete(store.blobs, key)
+	delete(store.types, key)
+
+	return nil
+}
+
+func (store *MemoryStore) URL(key string) string {
+	return "memory://" + key
+}
+
+// ContentType returns the content type the blob of the key was put with.
+func (store *MemoryStore) ContentType(key string) string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.types[key]
+}
+
+// Keys returns the keys of every blob stored so far.
+func (store *MemoryStore) Keys() []string {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	keys := make([]string, 0, len(store.blobs))
+	for key := range store.blobs {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Blob returns the content of the blob of the key, nil if there is none.
+func (store *MemoryStore) Blob(key string) []byte {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	return store.blobs[key]
+}
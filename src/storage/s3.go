@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+)
+
+// UnsignedPayload is sent as x-amz-content-sha256 so that uploads are
+// streamed without hashing them first.
+const UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// s3Store keeps the blobs in a bucket of an S3 compatible service (AWS S3,
+// MinIO...) addressed by path, so that any endpoint works without DNS.
+type s3Store struct {
+	client    *http.Client
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	publicURL string
+}
+
+// NewS3Store creates a store of the bucket at endpoint. Blobs are linked to
+// under publicURL, or to the object URL at the endpoint when it is empty.
+func NewS3Store(endpoint string, region string, bucket string, accessKey string, secretKey string, publicURL string) *s3Store {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+
+	if publicURL == "" {
+		publicURL = endpoint + "/" + bucket
+	}
+
+	return &s3Store{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}
+}
+
+func (store *s3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) (err error) {
+	req, err := store.request(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := store.do(req)
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (store *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := store.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := store.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Body, nil
+}
+
+func (store *s3Store) Delete(ctx context.Context, key string) (err error) {
+	req, err := store.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := store.do(req)
+	if err == domain.ErrBlobNotFound {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return res.Body.Close()
+}
+
+func (store *s3Store) URL(key string) string {
+	return store.publicURL + "/" + escapePath(key)
+}
+
+func (store *s3Store) request(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	return http.NewRequestWithContext(ctx, method, store.endpoint+"/"+escapePath(store.bucket+"/"+key), body)
+}
+
+// do signs and sends the request, a response other than 2xx is returned as
+// an error with its body closed.
+func (store *s3Store) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Amz-Content-Sha256", UnsignedPayload)
+
+	SignV4(req, store.accessKey, store.secretKey, store.region, "s3", time.Now())
+
+	res, err := store.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return res, nil
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrBlobNotFound
+	}
+
+	message, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+
+	return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, res.Status, strings.TrimSpace(string(message)))
+}
+
+// SignV4 signs the request with the AWS Signature Version 4 in its
+// Authorization header. The payload hash is taken from the
+// X-Amz-Content-Sha256 header, which is set to UnsignedPayload when missing.
+func SignV4(req *http.Request, accessKey string, secretKey string, region string, service string, now time.Time) {
+	now = now.UTC()
+	date := now.Format("20060102")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+
+	payloadHash := req.Header.Get("X-Amz-Content-Sha256")
+	if payloadHash == "" {
+		payloadHash = UnsignedPayload
+		req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	}
+
+	req.Header.Set("X-Amz-Date", now.Format("20060102T150405Z"))
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || name == "content-md5" || name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		now.Format("20060102T150405Z"),
+		scope,
+		hashHex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	pairs := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			pairs = append(pairs, escape(name, true)+"="+escape(value, true))
+		}
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, "&")
+}
+
+// escapePath escapes the key the way SigV4 expects, keeping the slashes.
+func escapePath(path string) string {
+	return escape(path, false)
+}
+
+// escape percent-encodes every byte but the unreserved characters of RFC
+// 3986, and the slash unless escapeSlash.
+func escape(value string, escapeSlash bool) string {
+	var escaped strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			escaped.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+
+	return escaped.String()
+}
+
+func hashHex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
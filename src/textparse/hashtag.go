@@ -0,0 +1,98 @@
+// Package textparse finds the tags people write in free text, like the
+// hashtags and the mentions of a caption.
+package textparse
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxHashtagLength is the longest hashtag in runes, a longer one isn't
+	// taken as a hashtag at all
+	MaxHashtagLength = 100
+	// MaxHashtags is how many hashtags are taken from a text, the first ones
+	MaxHashtags = 30
+)
+
+// Hashtags finds the hashtags of the text, normalized and without
+// duplicates, in the order they're first written. A hashtag is a # followed
+// by letters, digits and underscores, with at least one letter, which doesn't
+// follow a word, so that neither "a#b" nor the fragment of a link is one.
+func Hashtags(text string) []string {
+	var (
+		hashtags []string
+		seen     = map[string]bool{}
+		previous rune
+	)
+
+	for i := 0; i < len(text) && len(hashtags) < MaxHashtags; {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		if r != '#' || isWordRune(previous) || previous == '/' || previous == '#' {
+			previous = r
+			i += size
+			continue
+		}
+
+		end, last := i+size, r
+		for end < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[end:])
+			if !isWordRune(next) {
+				break
+			}
+
+			end, last = end+nextSize, next
+		}
+
+		if hashtag, ok := NormalizeHashtag(text[i:end]); ok && !seen[hashtag] {
+			seen[hashtag] = true
+			hashtags = append(hashtags, hashtag)
+		}
+
+		previous = last
+		i = end
+	}
+
+	return hashtags
+}
+
+// NormalizeHashtag lowercases the hashtag, with or without its #, and tells
+// whether it's a valid one.
+func NormalizeHashtag(hashtag string) (string, bool) {
+	return normalize(hashtag, false)
+}
+
+// NormalizeHashtagPrefix lowercases the start of a hashtag being written, with
+// or without its #, and tells whether a valid hashtag can start with it.
+func NormalizeHashtagPrefix(prefix string) (string, bool) {
+	return normalize(prefix, true)
+}
+
+func normalize(hashtag string, prefix bool) (string, bool) {
+	hashtag = strings.ToLower(strings.TrimPrefix(hashtag, "#"))
+
+	length, letter := 0, false
+	for _, r := range hashtag {
+		if !isWordRune(r) {
+			return "", false
+		}
+
+		if unicode.IsLetter(r) {
+			letter = true
+		}
+
+		length++
+	}
+
+	if (!letter && !prefix) || length > MaxHashtagLength {
+		return "", false
+	}
+
+	return hashtag, true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
@@ -0,0 +1,79 @@
+package textparse
+
+import (
+	"unicode/utf8"
+)
+
+const (
+	// MaxUsernameLength is the longest username in runes, a longer one isn't
+	// taken as a mention at all
+	MaxUsernameLength = 50
+	// MaxMentions is how many users are mentioned in a text, the first ones
+	MaxMentions = 20
+)
+
+// Mention is an @username written in a text. Offset and Length are counted in
+// runes from the start of the text and cover the @, so that the mention can
+// be linked where it's written.
+type Mention struct {
+	Username string
+	Offset   int
+	Length   int
+}
+
+// Mentions finds the mentions of the text in the order they're written, a
+// user mentioned twice is found at both places. A mention is an @ followed by
+// letters, digits, underscores and dots, not ending with a dot, which doesn't
+// follow a word so that an email address isn't one.
+func Mentions(text string) []Mention {
+	var (
+		mentions []Mention
+		seen     = map[string]bool{}
+		previous rune
+		offset   int
+	)
+
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+
+		if r != '@' || isWordRune(previous) || previous == '@' || previous == '.' {
+			previous = r
+			offset++
+			i += size
+			continue
+		}
+
+		// the end of the username leaves out the dots it ends with, they end
+		// the sentence
+		end, length, last := i+size, 0, r
+		scanned, scannedLength := end, 0
+		for scanned < len(text) {
+			next, nextSize := utf8.DecodeRuneInString(text[scanned:])
+			if !isWordRune(next) && next != '.' {
+				break
+			}
+
+			scanned, scannedLength, last = scanned+nextSize, scannedLength+1, next
+			if next != '.' {
+				end, length = scanned, scannedLength
+			}
+		}
+
+		username := text[i+size : end]
+
+		if length > 0 && length <= MaxUsernameLength {
+			if !seen[username] && len(seen) == MaxMentions {
+				break
+			}
+
+			seen[username] = true
+			mentions = append(mentions, Mention{Username: username, Offset: offset, Length: length + 1})
+		}
+
+		previous = last
+		offset += scannedLength + 1
+		i = scanned
+	}
+
+	return mentions
+}
@@ -7,11 +7,13 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/gin-gonic/gin"
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	mocksUseCase "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
 	delivery "github.com/gusrylmubarok/mygram-backend/src/modules/user/delivery/http"
 	userUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/user/usecase"
 	"github.com/stretchr/testify/assert"
@@ -22,6 +24,11 @@ func TestRegisterUser(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockUserUseCase := new(mocksUseCase.UserUseCase)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockEmailVerificationUseCase := new(mocksUseCase.EmailVerificationUseCase)
+	mockMFAUseCase := new(mocksUseCase.MFAUseCase)
+	mockLoginAttemptUseCase := new(mocksUseCase.LoginAttemptUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
 	userUseCase := userUseCase.NewUserUseCase(mockUserUseCase)
 
 	t.Run("should success register user", func(t *testing.T) {
@@ -43,11 +50,12 @@ func TestRegisterUser(t *testing.T) {
 		}
 
 		mockUserUseCase.On("Register", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+		mockEmailVerificationUseCase.On("Send", mock.Anything, mock.AnythingOfType("domain.User")).Return(nil).Once()
 
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -67,6 +75,7 @@ func TestRegisterUser(t *testing.T) {
 		// assert
 		assert.Equal(t, http.StatusCreated, rec.Code)
 		assert.Equal(t, expected.Data.Email, res.Data.Email)
+		mockEmailVerificationUseCase.AssertExpectations(t)
 	})
 
 	t.Run("should fail register user with empty email", func(t *testing.T) {
@@ -83,7 +92,7 @@ func TestRegisterUser(t *testing.T) {
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -118,7 +127,7 @@ func TestRegisterUser(t *testing.T) {
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -153,7 +162,7 @@ func TestRegisterUser(t *testing.T) {
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -188,7 +197,7 @@ func TestRegisterUser(t *testing.T) {
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -223,7 +232,7 @@ func TestRegisterUser(t *testing.T) {
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -256,7 +265,7 @@ func TestRegisterUser(t *testing.T) {
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -292,7 +301,7 @@ func TestRegisterUser(t *testing.T) {
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/register", userHandler.Register)
 
 		// do
@@ -318,44 +327,97 @@ func TestLoginUser(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockUserUseCase := new(mocksUseCase.UserUseCase)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockEmailVerificationUseCase := new(mocksUseCase.EmailVerificationUseCase)
+	mockMFAUseCase := new(mocksUseCase.MFAUseCase)
+	mockLoginAttemptUseCase := new(mocksUseCase.LoginAttemptUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
 	userUseCase := userUseCase.NewUserUseCase(mockUserUseCase)
 
-	// t.Run("should success login user", func(t *testing.T) {
-	// 	// prepare
-	// 	tempMockLoginUser := domain.User{
-	// 		Email:    "johndoe@example.com",
-	// 		Password: "secret",
-	// 	}
-	// 	expected := domain.LoggedInUser{
-	// 		Status:  "success",
-	// 		Message: "user registration has been successful",
-	// 		Data: domain.Token{
-	// 			Token: "your-token",
-	// 		},
-	// 	}
-
-	// 	mockUserUseCase.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
-
-	// 	router := gin.Default()
-	// 	rec := httptest.NewRecorder()
-
-	// 	userHandler := delivery.NewUserHandler(router, userUseCase)
-	// 	router.POST("/user/login", userHandler.Login)
-
-	// 	// do
-	// 	reqBody, err := json.Marshal(tempMockLoginUser)
-	// 	assert.NoError(t, err)
-	// 	req := httptest.NewRequest(http.MethodPost, "/user/login", strings.NewReader(string(reqBody)))
-	// 	router.ServeHTTP(rec, req)
-
-	// 	var res domain.LoggedInUser
-	// 	err = json.Unmarshal(rec.Body.Bytes(), &res)
-	// 	assert.NoError(t, err)
-
-	// 	// assert
-	// 	assert.Equal(t, http.StatusOK, rec.Code)
-	// 	assert.Equal(t, expected.Message, res.Message)
-	// })
+	mockLoginAttemptUseCase.On("Check", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(time.Duration(0), nil)
+	mockLoginAttemptUseCase.On("Succeed", mock.Anything, mock.AnythingOfType("string")).Return(nil)
+
+	t.Run("should success login user", func(t *testing.T) {
+		// prepare
+		tempMockLoginUser := domain.User{
+			Email:    "johndoe@example.com",
+			Password: "secret",
+		}
+		expected := domain.LoggedInUser{
+			Status:  "success",
+			Message: "user login has beed successful",
+			Data: domain.Token{
+				Token:        "your-token",
+				TokenType:    "Bearer",
+				ExpiresIn:    900,
+				RefreshToken: "your-refresh-token",
+			},
+		}
+
+		mockUserUseCase.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+		mockMFAUseCase.On("IsEnabled", mock.Anything, mock.AnythingOfType("string")).Return(false, nil).Once()
+		mockTokenUseCase.On("Issue", mock.Anything, mock.AnythingOfType("domain.User"), mock.AnythingOfType("domain.SessionClient")).Return(expected.Data, nil).Once()
+
+		router := gin.Default()
+		rec := httptest.NewRecorder()
+
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+		router.POST("/user/login", userHandler.Login)
+
+		// do
+		reqBody, err := json.Marshal(tempMockLoginUser)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/user/login", strings.NewReader(string(reqBody)))
+		router.ServeHTTP(rec, req)
+
+		var res domain.LoggedInUser
+		err = json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expected.Message, res.Message)
+		assert.Equal(t, expected.Data, res.Data)
+	})
+
+	t.Run("should require two-factor code when enabled", func(t *testing.T) {
+		// prepare
+		tempMockLoginUser := domain.User{
+			Email:    "johndoe@example.com",
+			Password: "secret",
+		}
+		expected := domain.MFAChallenge{
+			ChallengeToken: "challenge-token",
+			ExpiresIn:      300,
+		}
+
+		mockUserUseCase.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil).Once()
+		mockMFAUseCase.On("IsEnabled", mock.Anything, mock.AnythingOfType("string")).Return(true, nil).Once()
+		mockMFAUseCase.On("Challenge", mock.Anything, mock.AnythingOfType("domain.User")).Return(expected, nil).Once()
+
+		router := gin.Default()
+		rec := httptest.NewRecorder()
+
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+		router.POST("/user/login", userHandler.Login)
+
+		// do
+		reqBody, err := json.Marshal(tempMockLoginUser)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/user/login", strings.NewReader(string(reqBody)))
+		router.ServeHTTP(rec, req)
+
+		var res domain.MFARequired
+		err = json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusAccepted, rec.Code)
+		assert.Equal(t, "mfa_required", res.Status)
+		assert.Equal(t, expected, res.Data)
+		mockTokenUseCase.AssertNumberOfCalls(t, "Issue", 1)
+		mockMFAUseCase.AssertExpectations(t)
+	})
 
 	t.Run("should fail login user with invalid email", func(t *testing.T) {
 		// prepare
@@ -364,12 +426,13 @@ func TestLoginUser(t *testing.T) {
 			Password: "secret",
 		}
 
-		mockUserUseCase.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("the email you entered are not registered")).Once()
+		mockUserUseCase.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("the credential you entered are wrong")).Once()
+		mockLoginAttemptUseCase.On("Fail", mock.Anything, "jhondoe@example.com", mock.AnythingOfType("string")).Return(nil).Once()
 
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/login", userHandler.Login)
 
 		// do
@@ -387,7 +450,8 @@ func TestLoginUser(t *testing.T) {
 		// assert
 		assert.Error(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Equal(t, "the email you entered are not registered", res.Message)
+		assert.Equal(t, "the credential you entered are wrong", res.Message)
+		mockLoginAttemptUseCase.AssertExpectations(t)
 	})
 
 	t.Run("should fail login user with invalid password", func(t *testing.T) {
@@ -397,12 +461,13 @@ func TestLoginUser(t *testing.T) {
 			Password: "secret",
 		}
 
-		mockUserUseCase.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("the password you entered are wrong")).Once()
+		mockUserUseCase.On("Login", mock.Anything, mock.AnythingOfType("*domain.User")).Return(errors.New("the credential you entered are wrong")).Once()
+		mockLoginAttemptUseCase.On("Fail", mock.Anything, "jhondoe@example.com", mock.AnythingOfType("string")).Return(nil).Once()
 
 		router := gin.Default()
 		rec := httptest.NewRecorder()
 
-		userHandler := delivery.NewUserHandler(router, userUseCase)
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
 		router.POST("/user/login", userHandler.Login)
 
 		// do
@@ -420,6 +485,271 @@ func TestLoginUser(t *testing.T) {
 		// assert
 		assert.Error(t, err)
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
-		assert.Equal(t, "the password you entered are wrong", res.Message)
+		assert.Equal(t, "the credential you entered are wrong", res.Message)
+		mockLoginAttemptUseCase.AssertExpectations(t)
+	})
+}
+
+func TestLoginUserThrottled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserUseCase := new(mocksUseCase.UserUseCase)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockEmailVerificationUseCase := new(mocksUseCase.EmailVerificationUseCase)
+	mockMFAUseCase := new(mocksUseCase.MFAUseCase)
+	mockLoginAttemptUseCase := new(mocksUseCase.LoginAttemptUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
+	userUseCase := userUseCase.NewUserUseCase(mockUserUseCase)
+
+	t.Run("should fail login user with too many failed attempts", func(t *testing.T) {
+		// prepare
+		tempMockLoginUser := domain.User{
+			Email:    "johndoe@example.com",
+			Password: "secret",
+		}
+
+		mockLoginAttemptUseCase.On("Check", mock.Anything, "johndoe@example.com", mock.AnythingOfType("string")).Return(90*time.Second+time.Millisecond, domain.ErrTooManyLoginAttempts).Once()
+
+		router := gin.Default()
+		rec := httptest.NewRecorder()
+
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+		router.POST("/user/login", userHandler.Login)
+
+		// do
+		reqBody, err := json.Marshal(tempMockLoginUser)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/user/login", strings.NewReader(string(reqBody)))
+		router.ServeHTTP(rec, req)
+
+		var res helpers.ResponseMessage
+		err = json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "91", rec.Header().Get("Retry-After"))
+		assert.Equal(t, domain.ErrTooManyLoginAttempts.Error(), res.Message)
+		mockUserUseCase.AssertNotCalled(t, "Login", mock.Anything, mock.Anything)
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserUseCase := new(mocksUseCase.UserUseCase)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockEmailVerificationUseCase := new(mocksUseCase.EmailVerificationUseCase)
+	mockMFAUseCase := new(mocksUseCase.MFAUseCase)
+	mockLoginAttemptUseCase := new(mocksUseCase.LoginAttemptUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
+	userUseCase := userUseCase.NewUserUseCase(mockUserUseCase)
+
+	t.Run("should success refresh token", func(t *testing.T) {
+		// prepare
+		expected := domain.Token{
+			Token:        "new-token",
+			TokenType:    "Bearer",
+			RefreshToken: "new-refresh-token",
+		}
+
+		mockTokenUseCase.On("Refresh", mock.Anything, "refresh-token").Return(expected, nil).Once()
+
+		router := gin.Default()
+		rec := httptest.NewRecorder()
+
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+		router.POST("/user/refresh", userHandler.Refresh)
+
+		// do
+		reqBody, err := json.Marshal(domain.RefreshUserToken{RefreshToken: "refresh-token"})
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/user/refresh", strings.NewReader(string(reqBody)))
+		router.ServeHTTP(rec, req)
+
+		var res domain.RefreshedToken
+		err = json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expected, res.Data)
+	})
+
+	t.Run("should fail refresh token with reused token", func(t *testing.T) {
+		// prepare
+		mockTokenUseCase.On("Refresh", mock.Anything, "reused-token").Return(domain.Token{}, domain.ErrRefreshTokenReused).Once()
+
+		router := gin.Default()
+		rec := httptest.NewRecorder()
+
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+		router.POST("/user/refresh", userHandler.Refresh)
+
+		// do
+		reqBody, err := json.Marshal(domain.RefreshUserToken{RefreshToken: "reused-token"})
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/user/refresh", strings.NewReader(string(reqBody)))
+		router.ServeHTTP(rec, req)
+
+		var res domain.RefreshedToken
+		err = json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Equal(t, domain.ErrRefreshTokenReused.Error(), res.Message)
+	})
+}
+
+func TestChangePasswordUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserUseCase := new(mocksUseCase.UserUseCase)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockEmailVerificationUseCase := new(mocksUseCase.EmailVerificationUseCase)
+	mockMFAUseCase := new(mocksUseCase.MFAUseCase)
+	mockLoginAttemptUseCase := new(mocksUseCase.LoginAttemptUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
+	userUseCase := userUseCase.NewUserUseCase(mockUserUseCase)
+
+	authenticated := func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(domain.ContextWithPrincipal(ctx.Request.Context(), domain.Principal{
+			UserID:     "user-123",
+			Email:      "johndoe@example.com",
+			Roles:      []string{domain.RoleUser},
+			AuthMethod: domain.AuthMethodAccessToken,
+		}))
+	}
+
+	t.Run("should success change password and sign out other sessions", func(t *testing.T) {
+		// prepare
+		expected := domain.Token{
+			Token:        "new-token",
+			TokenType:    "Bearer",
+			RefreshToken: "new-refresh-token",
+		}
+		mockUser := domain.User{ID: "user-123", Email: "johndoe@example.com"}
+
+		mockUserUseCase.On("UpdatePassword", mock.Anything, "user-123", "secret", "newsecret").Return(nil).Once()
+		mockTokenUseCase.On("RevokeAll", mock.Anything, "user-123").Return(nil).Once()
+		mockAPIKeyUseCase.On("RevokeAllByUser", mock.Anything, "user-123").Return(nil).Once()
+		mockUserUseCase.On("FindById", mock.Anything, "user-123").Return(mockUser, nil).Once()
+		mockTokenUseCase.On("Issue", mock.Anything, mockUser, mock.AnythingOfType("domain.SessionClient")).Return(expected, nil).Once()
+
+		router := gin.Default()
+		rec := httptest.NewRecorder()
+
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+		router.PUT("/user/password", authenticated, userHandler.ChangePassword)
+
+		// do
+		reqBody, err := json.Marshal(domain.ChangePassword{CurrentPassword: "secret", NewPassword: "newsecret"})
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/user/password", strings.NewReader(string(reqBody)))
+		router.ServeHTTP(rec, req)
+
+		var res domain.ChangedPassword
+		err = json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expected, res.Data)
+		mockUserUseCase.AssertExpectations(t)
+		mockTokenUseCase.AssertExpectations(t)
+		mockAPIKeyUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should fail change password with wrong current password", func(t *testing.T) {
+		// prepare
+		mockUserUseCase.On("UpdatePassword", mock.Anything, "user-123", "wrong", "newsecret").Return(errors.New("the current password you entered are wrong")).Once()
+
+		router := gin.Default()
+		rec := httptest.NewRecorder()
+
+		userHandler := delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+		router.PUT("/user/password", authenticated, userHandler.ChangePassword)
+
+		// do
+		reqBody, err := json.Marshal(domain.ChangePassword{CurrentPassword: "wrong", NewPassword: "newsecret"})
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPut, "/user/password", strings.NewReader(string(reqBody)))
+		router.ServeHTTP(rec, req)
+
+		var res helpers.ResponseMessage
+		err = json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, "the current password you entered are wrong", res.Message)
+		mockTokenUseCase.AssertNumberOfCalls(t, "RevokeAll", 1)
+	})
+}
+
+func TestProfileUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserUseCase := new(mocksUseCase.UserUseCase)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockEmailVerificationUseCase := new(mocksUseCase.EmailVerificationUseCase)
+	mockMFAUseCase := new(mocksUseCase.MFAUseCase)
+	mockLoginAttemptUseCase := new(mocksUseCase.LoginAttemptUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
+	userUseCase := userUseCase.NewUserUseCase(mockUserUseCase)
+
+	router := gin.Default()
+	delivery.NewUserHandler(router, userUseCase, mockTokenUseCase, mockEmailVerificationUseCase, mockMFAUseCase, mockLoginAttemptUseCase, mockAPIKeyUseCase)
+
+	t.Run("should success fetch the public profile", func(t *testing.T) {
+		// prepare
+		expected := domain.UserProfile{
+			ID:          "user-123",
+			Username:    "johndoe",
+			DisplayName: "John Doe",
+			Bio:         "Taking photos of cats",
+			PhotoCount:  2,
+		}
+
+		mockUserUseCase.On("FindProfile", mock.Anything, "johndoe").Return(expected, nil).Once()
+
+		rec := httptest.NewRecorder()
+
+		// do
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/johndoe", nil)
+		router.ServeHTTP(rec, req)
+
+		var res domain.FetchedUserProfile
+		err := json.Unmarshal(rec.Body.Bytes(), &res)
+		assert.NoError(t, err)
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, expected, res.Data)
+
+		var raw struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		err = json.Unmarshal(rec.Body.Bytes(), &raw)
+		assert.NoError(t, err)
+		assert.NotContains(t, raw.Data, "email")
+		assert.NotContains(t, raw.Data, "age")
+		mockUserUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should fail fetch the profile of an unknown user", func(t *testing.T) {
+		// prepare
+		mockUserUseCase.On("FindProfile", mock.Anything, "janedoe").Return(domain.UserProfile{}, errors.New("record not found")).Once()
+
+		rec := httptest.NewRecorder()
+
+		// do
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/janedoe", nil)
+		router.ServeHTTP(rec, req)
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		mockUserUseCase.AssertExpectations(t)
 	})
 }
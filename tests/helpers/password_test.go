@@ -0,0 +1,61 @@
+package helpers_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPassword(t *testing.T) {
+	t.Run("should hash password with argon2id", func(t *testing.T) {
+		hashedPassword, err := helpers.Hash("secret")
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(hashedPassword, "$argon2id$v=19$m=19456,t=2,p=1$"))
+		assert.True(t, helpers.Compare([]byte(hashedPassword), []byte("secret")))
+		assert.False(t, helpers.Compare([]byte(hashedPassword), []byte("wrong")))
+		assert.False(t, helpers.NeedsRehash(hashedPassword))
+	})
+
+	t.Run("should salt every hash", func(t *testing.T) {
+		first, err := helpers.Hash("secret")
+		assert.NoError(t, err)
+
+		second, err := helpers.Hash("secret")
+		assert.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestComparePassword(t *testing.T) {
+	t.Run("should verify legacy bcrypt hash and ask for a rehash", func(t *testing.T) {
+		legacy, err := bcrypt.GenerateFromPassword([]byte("secret"), 8)
+		assert.NoError(t, err)
+
+		assert.True(t, helpers.Compare(legacy, []byte("secret")))
+		assert.False(t, helpers.Compare(legacy, []byte("wrong")))
+		assert.True(t, helpers.NeedsRehash(string(legacy)))
+	})
+
+	t.Run("should ask for a rehash when the parameters change", func(t *testing.T) {
+		hashedPassword, err := helpers.Hash("secret")
+		assert.NoError(t, err)
+
+		params := helpers.DefaultArgon2idParams
+		params.Iterations++
+		helpers.SetPasswordHasher(helpers.NewArgon2idHasher(params))
+		t.Cleanup(func() { helpers.SetPasswordHasher(helpers.NewArgon2idHasher(helpers.DefaultArgon2idParams)) })
+
+		assert.True(t, helpers.Compare([]byte(hashedPassword), []byte("secret")))
+		assert.True(t, helpers.NeedsRehash(hashedPassword))
+	})
+
+	t.Run("should reject unknown or malformed hash", func(t *testing.T) {
+		assert.False(t, helpers.Compare([]byte("secret"), []byte("secret")))
+		assert.False(t, helpers.Compare([]byte("$argon2id$v=19$m=19456$broken"), []byte("secret")))
+	})
+}
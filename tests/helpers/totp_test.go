@@ -0,0 +1,83 @@
+package helpers_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/stretchr/testify/assert"
+)
+
+// base32 of the RFC 6238 test secret "12345678901234567890"
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTP(t *testing.T) {
+	t.Run("should match the RFC 6238 test vectors", func(t *testing.T) {
+		vectors := map[int64]string{
+			59:          "287082",
+			1111111109:  "081804",
+			1111111111:  "050471",
+			1234567890:  "005924",
+			2000000000:  "279037",
+			20000000000: "353130",
+		}
+
+		for unix, expected := range vectors {
+			code, err := helpers.TOTP(rfc6238Secret, helpers.TOTPCounter(time.Unix(unix, 0)))
+
+			assert.NoError(t, err)
+			assert.Equal(t, expected, code)
+		}
+	})
+
+	t.Run("should fail with invalid secret", func(t *testing.T) {
+		_, err := helpers.TOTP("not base32!", 1)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+
+	t.Run("should accept the code of the current and adjacent time steps", func(t *testing.T) {
+		for _, at := range []time.Time{now, now.Add(-30 * time.Second), now.Add(30 * time.Second)} {
+			code, err := helpers.TOTP(rfc6238Secret, helpers.TOTPCounter(at))
+			assert.NoError(t, err)
+
+			counter, ok := helpers.ValidateTOTP(rfc6238Secret, code, now)
+
+			assert.True(t, ok)
+			assert.Equal(t, helpers.TOTPCounter(at), counter)
+		}
+	})
+
+	t.Run("should reject a code out of the allowed skew", func(t *testing.T) {
+		code, err := helpers.TOTP(rfc6238Secret, helpers.TOTPCounter(now.Add(-2*time.Minute)))
+		assert.NoError(t, err)
+
+		_, ok := helpers.ValidateTOTP(rfc6238Secret, code, now)
+
+		assert.False(t, ok)
+	})
+
+	t.Run("should reject a malformed code", func(t *testing.T) {
+		_, ok := helpers.ValidateTOTP(rfc6238Secret, "12345", now)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestTOTPURI(t *testing.T) {
+	t.Run("should build an otpauth uri", func(t *testing.T) {
+		uri, err := url.Parse(helpers.TOTPURI("MyGram", "johndoe@example.com", rfc6238Secret))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "otpauth", uri.Scheme)
+		assert.Equal(t, "totp", uri.Host)
+		assert.Equal(t, "/MyGram:johndoe@example.com", uri.Path)
+		assert.Equal(t, rfc6238Secret, uri.Query().Get("secret"))
+		assert.Equal(t, "MyGram", uri.Query().Get("issuer"))
+	})
+}
@@ -0,0 +1,240 @@
+package imaging_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// exifJPEG returns a JPEG image with an APP1 Exif segment of the orientation
+func exifJPEG(t *testing.T, order binary.ByteOrder, orientation uint16) []byte {
+	var encoded bytes.Buffer
+
+	if err := jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var tiff bytes.Buffer
+	if order == binary.LittleEndian {
+		tiff.WriteString("II*\x00")
+	} else {
+		tiff.WriteString("MM\x00*")
+	}
+	binary.Write(&tiff, order, uint32(8))
+	binary.Write(&tiff, order, uint16(1))
+	binary.Write(&tiff, order, []uint16{0x0112, 3})
+	binary.Write(&tiff, order, uint32(1))
+	binary.Write(&tiff, order, []uint16{orientation, 0})
+	binary.Write(&tiff, order, uint32(0))
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	data := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(segment)+2))
+	data = append(data, segment...)
+
+	return append(data, encoded.Bytes()[2:]...)
+}
+
+func TestOrientation(t *testing.T) {
+	t.Run("should read the orientation in both byte orders", func(t *testing.T) {
+		assert.Equal(t, 6, imaging.Orientation(exifJPEG(t, binary.BigEndian, 6)))
+		assert.Equal(t, 8, imaging.Orientation(exifJPEG(t, binary.LittleEndian, 8)))
+	})
+
+	t.Run("should keep the image decodable", func(t *testing.T) {
+		_, err := jpeg.Decode(bytes.NewReader(exifJPEG(t, binary.BigEndian, 6)))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should be upright without exif", func(t *testing.T) {
+		var encoded bytes.Buffer
+		jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 4, 2)), nil)
+
+		assert.Equal(t, 1, imaging.Orientation(encoded.Bytes()))
+		assert.Equal(t, 1, imaging.Orientation([]byte("not an image")))
+	})
+
+	t.Run("should be upright with an invalid orientation or a truncated segment", func(t *testing.T) {
+		data := exifJPEG(t, binary.BigEndian, 9)
+		assert.Equal(t, 1, imaging.Orientation(data))
+		assert.Equal(t, 1, imaging.Orientation(data[:20]))
+	})
+}
+
+func TestOrient(t *testing.T) {
+	// a 3x2 image with a red top left pixel
+	src := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	src.Set(0, 0, color.RGBA{255, 0, 0, 255})
+
+	red := color.RGBA{255, 0, 0, 255}
+
+	cases := []struct {
+		orientation int
+		width       int
+		height      int
+		x           int
+		y           int
+	}{
+		{1, 3, 2, 0, 0},
+		{2, 3, 2, 2, 0},
+		{3, 3, 2, 2, 1},
+		{4, 3, 2, 0, 1},
+		{5, 2, 3, 0, 0},
+		{6, 2, 3, 1, 0},
+		{7, 2, 3, 1, 2},
+		{8, 2, 3, 0, 2},
+	}
+
+	for _, c := range cases {
+		oriented := imaging.Orient(src, c.orientation)
+
+		assert.Equal(t, c.width, oriented.Bounds().Dx(), "orientation %d", c.orientation)
+		assert.Equal(t, c.height, oriented.Bounds().Dy(), "orientation %d", c.orientation)
+		assert.Equal(t, red, color.RGBAModel.Convert(oriented.At(c.x, c.y)), "orientation %d", c.orientation)
+	}
+}
+
+func TestResize(t *testing.T) {
+	t.Run("should keep the aspect ratio", func(t *testing.T) {
+		resized := imaging.Resize(image.NewRGBA(image.Rect(0, 0, 1600, 1200)), 640)
+
+		assert.Equal(t, image.Rect(0, 0, 640, 480), resized.Bounds())
+	})
+
+	t.Run("should keep at least a pixel of height", func(t *testing.T) {
+		resized := imaging.Resize(image.NewRGBA(image.Rect(0, 0, 1000, 1)), 150)
+
+		assert.Equal(t, image.Rect(0, 0, 150, 1), resized.Bounds())
+	})
+}
+
+func TestReadMetadata(t *testing.T) {
+	data, err := os.ReadFile("../testdata/exif.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should read the camera fields", func(t *testing.T) {
+		metadata := imaging.ReadMetadata(data)
+
+		assert.Equal(t, "Pixel 7", metadata.CameraModel)
+		assert.Equal(t, "1/125", metadata.ExposureTime)
+		assert.Equal(t, 1.85, metadata.FNumber)
+		assert.Equal(t, 100, metadata.ISO)
+		assert.Equal(t, time.Date(2023, 4, 1, 5, 30, 0, 0, time.UTC), *metadata.TakenAt)
+	})
+
+	t.Run("should read nothing of an image without exif", func(t *testing.T) {
+		assert.Equal(t, imaging.Metadata{}, imaging.ReadMetadata(exifJPEG(t, binary.BigEndian, 1)[:4]))
+		assert.Equal(t, imaging.Metadata{}, imaging.ReadMetadata([]byte("not an image")))
+	})
+}
+
+func TestStrip(t *testing.T) {
+	t.Run("should turn a jpeg upright without its exif", func(t *testing.T) {
+		data, _ := os.ReadFile("../testdata/exif.jpg")
+
+		stripped, width, height, err := imaging.Strip(data, 1000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8, width)
+		assert.Equal(t, 16, height)
+		assert.False(t, bytes.Contains(stripped, []byte("Exif")))
+
+		img, err := jpeg.Decode(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 8, 16), img.Bounds())
+
+		// the red left half of the sideways image is now the top half
+		r, _, b, _ := img.At(4, 2).RGBA()
+		assert.Greater(t, r, b)
+
+		r, _, b, _ = img.At(4, 13).RGBA()
+		assert.Greater(t, b, r)
+	})
+
+	t.Run("should drop the text chunks of a png", func(t *testing.T) {
+		var encoded bytes.Buffer
+		png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 2, 2)))
+
+		// a tEXt chunk inserted after the IHDR one
+		chunk := []byte("Comment\x00at home")
+		data := append([]byte{}, encoded.Bytes()[:33]...)
+		data = binary.BigEndian.AppendUint32(data, uint32(len(chunk)))
+		data = append(data, "tEXt"...)
+		data = append(data, chunk...)
+		data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(append([]byte("tEXt"), chunk...)))
+		data = append(data, encoded.Bytes()[33:]...)
+
+		_, err := png.Decode(bytes.NewReader(data))
+		assert.NoError(t, err)
+
+		stripped, width, height, err := imaging.Strip(data, 1000)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, width)
+		assert.Equal(t, 2, height)
+		assert.False(t, bytes.Contains(stripped, []byte("at home")))
+	})
+
+	t.Run("should keep every frame of a gif", func(t *testing.T) {
+		palette := color.Palette{color.Black, color.White}
+		animation := &gif.GIF{
+			Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 3, 3), palette), image.NewPaletted(image.Rect(0, 0, 3, 3), palette)},
+			Delay: []int{10, 10},
+		}
+
+		var encoded bytes.Buffer
+		gif.EncodeAll(&encoded, animation)
+
+		stripped, width, height, err := imaging.Strip(encoded.Bytes(), 1000)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, width)
+		assert.Equal(t, 3, height)
+
+		decoded, err := gif.DecodeAll(bytes.NewReader(stripped))
+		assert.NoError(t, err)
+		assert.Len(t, decoded.Image, 2)
+	})
+
+	t.Run("should fail a gif of too many pixels before decoding its frames", func(t *testing.T) {
+		palette := color.Palette{color.Black, color.White}
+		animation := &gif.GIF{}
+		for i := 0; i < 5; i++ {
+			animation.Image = append(animation.Image, image.NewPaletted(image.Rect(0, 0, 3, 3), palette))
+			animation.Delay = append(animation.Delay, 10)
+		}
+
+		var encoded bytes.Buffer
+		gif.EncodeAll(&encoded, animation)
+
+		// 4 times the 9 pixels of a frame are allowed, the 5 frames have 45
+		_, _, _, err := imaging.Strip(encoded.Bytes(), 9)
+		assert.ErrorIs(t, err, imaging.ErrTooManyPixels)
+
+		_, _, _, err = imaging.Strip(encoded.Bytes(), 12)
+		assert.NoError(t, err)
+
+		_, _, _, err = imaging.Strip(encoded.Bytes(), 8)
+		assert.ErrorIs(t, err, imaging.ErrTooManyPixels)
+	})
+
+	t.Run("should fail with other formats", func(t *testing.T) {
+		_, _, _, err := imaging.Strip([]byte("not an image"), 1000)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,89 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocksUseCase "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAPIKeyAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
+	middleware.SetAPIKeys(mockAPIKeyUseCase)
+	t.Cleanup(func() { middleware.SetAPIKeys(nil) })
+
+	mockAPIKeyUseCase.On("Authenticate", mock.Anything, "mgk_valid").Return(domain.APIKey{
+		ID:     "apikey-123",
+		UserID: "user-123",
+		Scopes: "photos:read",
+		User:   &domain.User{ID: "user-123", Email: "johndoe@example.com", Role: domain.RoleUser},
+	}, nil)
+	mockAPIKeyUseCase.On("Authenticate", mock.Anything, "mgk_admin").Return(domain.APIKey{
+		ID:     "apikey-234",
+		UserID: "user-234",
+		Scopes: "photos:read",
+		User:   &domain.User{ID: "user-234", Email: "admin@example.com", Role: domain.RoleAdmin},
+	}, nil)
+	mockAPIKeyUseCase.On("Authenticate", mock.Anything, "mgk_revoked").Return(domain.APIKey{}, domain.ErrInvalidAPIKey)
+
+	var principal domain.Principal
+
+	router := gin.New()
+	router.Use(middleware.Authentication())
+	router.GET("/photo", middleware.RequireScope(domain.ScopePhotosRead), func(ctx *gin.Context) {
+		principal = middleware.CurrentPrincipal(ctx)
+		ctx.Status(http.StatusOK)
+	})
+	router.POST("/photo", middleware.RequireScope(domain.ScopePhotosWrite), func(ctx *gin.Context) {
+		ctx.Status(http.StatusCreated)
+	})
+	router.PUT("/user", middleware.SessionOnly(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	request := func(method string, path string, key string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "ApiKey "+key)
+		router.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	t.Run("should accept a valid api key as its user", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/photo", "mgk_valid"))
+		assert.Equal(t, domain.Principal{
+			UserID:     "user-123",
+			Email:      "johndoe@example.com",
+			Roles:      []string{domain.RoleUser},
+			Scopes:     []string{domain.ScopePhotosRead},
+			TokenID:    "apikey-123",
+			AuthMethod: domain.AuthMethodAPIKey,
+		}, principal)
+	})
+
+	t.Run("should only give an api key of an admin the user role", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request(http.MethodGet, "/photo", "mgk_admin"))
+		assert.Equal(t, []string{domain.RoleUser}, principal.Roles)
+	})
+
+	t.Run("should reject a revoked api key", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/photo", "mgk_revoked"))
+	})
+
+	t.Run("should forbid a scope the api key wasn't granted", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPost, "/photo", "mgk_valid"))
+	})
+
+	t.Run("should forbid managing the account with an api key", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, request(http.MethodPut, "/user", "mgk_valid"))
+	})
+}
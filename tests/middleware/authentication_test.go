@@ -0,0 +1,142 @@
+package middleware_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	tokenMemoryRepository "github.com/gusrylmubarok/mygram-backend/src/modules/token/repository/memory"
+	"github.com/gusrylmubarok/mygram-backend/src/signing"
+	"github.com/stretchr/testify/assert"
+)
+
+// generateToken signs an access token of the session of the user the way the
+// token use case does.
+func generateToken(t *testing.T, signer domain.TokenSigner, id string, email string, role string, sessionID string, version uint, ttl time.Duration) string {
+	token, err := signer.Sign(domain.TokenTypeAccess, domain.TokenClaims{
+		"id":    id,
+		"email": email,
+		"role":  role,
+		"sid":   sessionID,
+		"ver":   version,
+	}, ttl)
+	assert.NoError(t, err)
+
+	return token
+}
+
+func TestAuthentication(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	keyRing := signing.NewKeyRing()
+	assert.NoError(t, keyRing.Add("test-key", privateKey))
+	assert.NoError(t, keyRing.SetSigningKey("test-key"))
+	signer := signing.NewSigner(keyRing, "mygram", "mygram-api")
+	middleware.SetTokenSigner(signer)
+	t.Cleanup(func() { middleware.SetTokenSigner(nil) })
+
+	revocationStore := tokenMemoryRepository.NewRevocationStore()
+	middleware.SetRevocationStore(revocationStore)
+	t.Cleanup(func() { middleware.SetRevocationStore(nil) })
+
+	var principal domain.Principal
+
+	router := gin.New()
+	router.GET("/protected", middleware.Authentication(), func(ctx *gin.Context) {
+		principal = middleware.CurrentPrincipal(ctx)
+		ctx.Status(http.StatusOK)
+	})
+
+	request := func(token string) int {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(rec, req)
+
+		return rec.Code
+	}
+
+	t.Run("should accept a valid token", func(t *testing.T) {
+		token := generateToken(t, signer, "user-123", "johndoe@example.com", "moderator", "session-123", 0, time.Minute)
+
+		assert.Equal(t, http.StatusOK, request(token))
+		assert.Equal(t, "user-123", principal.UserID)
+		assert.Equal(t, "johndoe@example.com", principal.Email)
+		assert.Equal(t, []string{domain.RoleModerator}, principal.Roles)
+		assert.Equal(t, domain.AuthMethodAccessToken, principal.AuthMethod)
+		assert.NotEmpty(t, principal.TokenID)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), principal.ExpiresAt, 2*time.Second)
+	})
+
+	t.Run("should reject a token of another issuer or audience", func(t *testing.T) {
+		token := generateToken(t, signing.NewSigner(keyRing, "someone-else", "mygram-api"), "user-123", "johndoe@example.com", "user", "session-123", 0, time.Minute)
+
+		assert.Equal(t, http.StatusUnauthorized, request(token))
+
+		token = generateToken(t, signing.NewSigner(keyRing, "mygram", "another-api"), "user-123", "johndoe@example.com", "user", "session-123", 0, time.Minute)
+
+		assert.Equal(t, http.StatusUnauthorized, request(token))
+	})
+
+	t.Run("should reject a token without a user", func(t *testing.T) {
+		token, err := signer.Sign(domain.TokenTypeAccess, domain.TokenClaims{
+			"id": 123,
+		}, time.Minute)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, request(token))
+	})
+
+	t.Run("should reject a malformed token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("not.a.token"))
+	})
+
+	t.Run("should reject an expired token", func(t *testing.T) {
+		token := generateToken(t, signer, "user-123", "johndoe@example.com", "user", "session-123", 0, -time.Minute)
+
+		assert.Equal(t, http.StatusUnauthorized, request(token))
+	})
+
+	t.Run("should reject a token issued for another purpose", func(t *testing.T) {
+		token, err := signer.Sign(domain.TokenTypeEmailVerification, domain.TokenClaims{
+			"id":    "user-123",
+			"email": "johndoe@example.com",
+		}, time.Minute)
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, request(token))
+	})
+
+	t.Run("should reject a token of a revoked session", func(t *testing.T) {
+		token := generateToken(t, signer, "user-123", "johndoe@example.com", "user", "session-456", 0, time.Minute)
+
+		assert.Equal(t, http.StatusOK, request(token))
+		assert.Equal(t, "session-456", principal.SessionID)
+
+		assert.NoError(t, revocationStore.Revoke(context.Background(), "session-456", time.Now().Add(time.Minute)))
+
+		assert.Equal(t, http.StatusUnauthorized, request(token))
+	})
+
+	t.Run("should reject a token issued before logging out everywhere", func(t *testing.T) {
+		token := generateToken(t, signer, "user-456", "janedoe@example.com", "user", "session-123", 0, time.Minute)
+
+		_, err = revocationStore.BumpTokenVersion(context.Background(), "user-456")
+		assert.NoError(t, err)
+
+		assert.Equal(t, http.StatusUnauthorized, request(token))
+
+		token = generateToken(t, signer, "user-456", "janedoe@example.com", "user", "session-123", 1, time.Minute)
+
+		assert.Equal(t, http.StatusOK, request(token))
+	})
+}
@@ -0,0 +1,152 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocksRepository "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	mocksUseCase "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/middleware"
+	commentUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/comment/usecase"
+	mentionUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/mention/usecase"
+	photoUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/photo/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// signIn stands in for Authentication with an access token of the user
+func signIn(id string, role string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Request = ctx.Request.WithContext(domain.ContextWithPrincipal(ctx.Request.Context(), domain.Principal{
+			UserID:     id,
+			Roles:      []string{role},
+			AuthMethod: domain.AuthMethodAccessToken,
+		}))
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(role string) int {
+		router := gin.New()
+		router.GET("/admin", signIn("user-123", role), middleware.RequireRole(domain.RoleAdmin), func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+		return rec.Code
+	}
+
+	t.Run("should let an admin through", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request(domain.RoleAdmin))
+	})
+
+	t.Run("should forbid other roles", func(t *testing.T) {
+		assert.Equal(t, http.StatusForbidden, request(domain.RoleModerator))
+		assert.Equal(t, http.StatusForbidden, request(domain.RoleUser))
+		assert.Equal(t, http.StatusForbidden, request(""))
+	})
+}
+
+func TestAuthorizationPhoto(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockPhotoRepository := new(mocksRepository.PhotoRepository)
+	mockAuditLogUseCase := new(mocksUseCase.AuditLogUseCase)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocksRepository.FeedStrategy), storage.NewMemoryStore(), new(mocksRepository.PhotoProcessor), mentionUseCase.NewMentionUseCase(new(mocksRepository.UserRepository), new(mocksRepository.BlockRepository), new(mocksRepository.NotificationRepository)), 10<<20)
+
+	middleware.SetAuditLog(mockAuditLogUseCase)
+	t.Cleanup(func() { middleware.SetAuditLog(nil) })
+
+	mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Run(func(args mock.Arguments) {
+		*args.Get(1).(*domain.Photo) = domain.Photo{ID: "photo-123", UserID: "user-123"}
+	}).Return(nil)
+
+	request := func(id string, role string, status int) int {
+		router := gin.New()
+		router.DELETE("/photo/:photoId", signIn(id, role), middleware.AuthorizationPhoto(photoUseCase), func(ctx *gin.Context) {
+			ctx.Status(status)
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/photo/photo-123", nil))
+
+		return rec.Code
+	}
+
+	t.Run("should let the owner through without recording", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request("user-123", domain.RoleUser, http.StatusOK))
+		mockAuditLogUseCase.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should reject other users", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("user-456", domain.RoleUser, http.StatusOK))
+		mockAuditLogUseCase.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should not record a failed override", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, request("user-456", domain.RoleModerator, http.StatusBadRequest))
+		mockAuditLogUseCase.AssertNotCalled(t, "Record", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should let a moderator through and record the override", func(t *testing.T) {
+		mockAuditLogUseCase.On("Record", mock.Anything, &domain.AuditLog{
+			Action:       "delete",
+			ResourceType: "photo",
+			ResourceID:   "photo-123",
+			OwnerID:      "user-123",
+		}).Return(nil).Once()
+
+		assert.Equal(t, http.StatusOK, request("user-456", domain.RoleModerator, http.StatusOK))
+		mockAuditLogUseCase.AssertExpectations(t)
+	})
+}
+
+func TestAuthorizationCommentModeration(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockCommentRepository := new(mocksRepository.CommentRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, new(mocksRepository.PhotoRepository), new(mocksRepository.FollowRepository), mentionUseCase.NewMentionUseCase(new(mocksRepository.UserRepository), new(mocksRepository.BlockRepository), new(mocksRepository.NotificationRepository)), 1, 3)
+
+	mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-123").Run(func(args mock.Arguments) {
+		*args.Get(1).(*domain.Comment) = domain.Comment{
+			ID:      "comment-123",
+			UserID:  "user-456",
+			PhotoID: "photo-123",
+			Photo:   &domain.Photo{ID: "photo-123", UserID: "user-123"},
+		}
+	}).Return(nil)
+
+	request := func(id string, allowAuthor bool) int {
+		router := gin.New()
+		router.PUT("/comment/:commentId/hide", signIn(id, domain.RoleUser), middleware.AuthorizationCommentModeration(commentUseCase, allowAuthor), func(ctx *gin.Context) {
+			ctx.Status(http.StatusOK)
+		})
+
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/comment/comment-123/hide", nil))
+
+		return rec.Code
+	}
+
+	t.Run("should let the owner of the photo through", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request("user-123", false))
+		assert.Equal(t, http.StatusOK, request("user-123", true))
+	})
+
+	t.Run("should let the author through only when allowed", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, request("user-456", true))
+		assert.Equal(t, http.StatusUnauthorized, request("user-456", false))
+	})
+
+	t.Run("should reject other users", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, request("user-789", true))
+	})
+}
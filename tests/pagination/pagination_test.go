@@ -0,0 +1,112 @@
+package pagination_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("should decode an encoded cursor", func(t *testing.T) {
+		createdAt := time.Now()
+
+		cursor, err := pagination.DecodeCursor(pagination.EncodeCursor(createdAt, "photo-123"))
+
+		assert.NoError(t, err)
+		assert.True(t, createdAt.Equal(cursor.CreatedAt))
+		assert.Equal(t, "photo-123", cursor.ID)
+	})
+
+	t.Run("should fail decode a malformed cursor", func(t *testing.T) {
+		for _, cursor := range []string{"not a cursor", "MTIz", "YWJjfHBob3RvLTEyMw"} {
+			_, err := pagination.DecodeCursor(cursor)
+
+			assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+		}
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("should default to the first page of the newest rows", func(t *testing.T) {
+		params, err := pagination.Parse(url.Values{})
+
+		assert.NoError(t, err)
+		assert.Nil(t, params.After)
+		assert.Equal(t, pagination.DefaultLimit, params.Limit)
+		assert.False(t, params.Ascending)
+		assert.Empty(t, params.Filters)
+	})
+
+	t.Run("should cap the limit", func(t *testing.T) {
+		params, err := pagination.Parse(url.Values{"limit": {"1000"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, pagination.MaxLimit, params.Limit)
+	})
+
+	t.Run("should fail with an invalid limit", func(t *testing.T) {
+		for _, limit := range []string{"0", "-1", "ten"} {
+			_, err := pagination.Parse(url.Values{"limit": {limit}})
+
+			assert.ErrorIs(t, err, pagination.ErrInvalidLimit)
+		}
+	})
+
+	t.Run("should read the sort", func(t *testing.T) {
+		params, err := pagination.Parse(url.Values{"sort": {"created_at"}})
+
+		assert.NoError(t, err)
+		assert.True(t, params.Ascending)
+
+		_, err = pagination.Parse(url.Values{"sort": {"title"}})
+
+		assert.ErrorIs(t, err, pagination.ErrInvalidSort)
+	})
+
+	t.Run("should read the cursor", func(t *testing.T) {
+		params, err := pagination.Parse(url.Values{"cursor": {pagination.EncodeCursor(time.Now(), "photo-123")}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "photo-123", params.After.ID)
+	})
+
+	t.Run("should only read the allowed filters", func(t *testing.T) {
+		params, err := pagination.Parse(url.Values{"user_id": {"user-123"}, "password": {"secret"}}, "user_id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"user_id": "user-123"}, params.Filters)
+	})
+}
+
+func TestTrim(t *testing.T) {
+	type row struct {
+		ID        string
+		CreatedAt time.Time
+	}
+
+	now := time.Now()
+	rows := []row{{"row-3", now}, {"row-2", now.Add(-time.Second)}, {"row-1", now.Add(-2 * time.Second)}}
+	key := func(r row) (*time.Time, string) { return &r.CreatedAt, r.ID }
+
+	t.Run("should tell there is a next page", func(t *testing.T) {
+		page, meta := pagination.Trim(rows, pagination.Params{Limit: 2}, key)
+
+		assert.Equal(t, rows[:2], page)
+		assert.True(t, meta.HasMore)
+
+		cursor, err := pagination.DecodeCursor(meta.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, "row-2", cursor.ID)
+	})
+
+	t.Run("should tell the last page", func(t *testing.T) {
+		page, meta := pagination.Trim(rows, pagination.Params{Limit: 3}, key)
+
+		assert.Equal(t, rows, page)
+		assert.False(t, meta.HasMore)
+		assert.Empty(t, meta.NextCursor)
+	})
+}
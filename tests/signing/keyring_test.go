@@ -0,0 +1,122 @@
+package signing_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	"github.com/gusrylmubarok/mygram-backend/src/signing"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeKey(t *testing.T, dir string, name string, key interface{}, public bool) {
+	var (
+		der       []byte
+		blockType = "PRIVATE KEY"
+		err       error
+	)
+
+	if public {
+		blockType = "PUBLIC KEY"
+		der, err = x509.MarshalPKIXPublicKey(key)
+	} else {
+		der, err = x509.MarshalPKCS8PrivateKey(key)
+	}
+
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600))
+}
+
+func accessToken(t *testing.T, signer *signing.Signer) string {
+	token, err := signer.Sign(domain.TokenTypeAccess, domain.TokenClaims{"id": "user-123"}, time.Minute)
+	assert.NoError(t, err)
+
+	return token
+}
+
+func TestKeyRing(t *testing.T) {
+	dir := t.TempDir()
+	oldPublicKey, oldPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	writeKey(t, dir, "2023-01-01.pem", oldPrivateKey, false)
+
+	keyRing, err := signing.LoadKeyRing(dir, "")
+	assert.NoError(t, err)
+	signer := signing.NewSigner(keyRing, "mygram", "mygram-api")
+
+	verify := func(token string) error {
+		_, err := signer.Parse(domain.TokenTypeAccess, token)
+
+		return err
+	}
+
+	oldToken := accessToken(t, signer)
+
+	t.Run("should sign with the newest key after rotation and still verify old tokens", func(t *testing.T) {
+		rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		writeKey(t, dir, "2023-02-01.pem", rsaKey, false)
+
+		assert.NoError(t, keyRing.Reload(dir, ""))
+
+		newToken := accessToken(t, signer)
+
+		parsed, _, err := new(jwt.Parser).ParseUnverified(newToken, jwt.MapClaims{})
+		assert.NoError(t, err)
+		assert.Equal(t, "2023-02-01", parsed.Header["kid"])
+		assert.Equal(t, "RS256", parsed.Method.Alg())
+
+		assert.NoError(t, verify(newToken))
+		assert.NoError(t, verify(oldToken))
+	})
+
+	t.Run("should verify with a retired public key and reject removed keys", func(t *testing.T) {
+		assert.NoError(t, os.Remove(filepath.Join(dir, "2023-01-01.pem")))
+		writeKey(t, dir, "2023-01-01.pub.pem", oldPublicKey, true)
+		assert.NoError(t, keyRing.Reload(dir, ""))
+
+		assert.NoError(t, verify(oldToken))
+
+		assert.NoError(t, os.Remove(filepath.Join(dir, "2023-01-01.pub.pem")))
+		assert.NoError(t, keyRing.Reload(dir, ""))
+
+		assert.Error(t, verify(oldToken))
+	})
+
+	t.Run("should reject a token whose algorithm doesn't match its key", func(t *testing.T) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"id":  "user-123",
+			"exp": time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = "2023-02-01"
+		forged, err := token.SignedString([]byte("secret"))
+		assert.NoError(t, err)
+
+		assert.Error(t, verify(forged))
+	})
+
+	t.Run("should serve the public keys as json web key set", func(t *testing.T) {
+		jwks := keyRing.JWKS()
+
+		assert.Len(t, jwks.Keys, 1)
+		assert.Equal(t, "RSA", jwks.Keys[0].KeyType)
+		assert.Equal(t, "2023-02-01", jwks.Keys[0].KeyID)
+		assert.Equal(t, "AQAB", jwks.Keys[0].E)
+	})
+
+	t.Run("should keep the current keys when reloading fails", func(t *testing.T) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0600))
+
+		assert.Error(t, keyRing.Reload(dir, ""))
+		_, ok := keyRing.Key("2023-02-01")
+		assert.True(t, ok)
+	})
+}
@@ -0,0 +1,68 @@
+package textparse_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/textparse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashtags(t *testing.T) {
+	t.Run("should find the hashtags lowercased in order without duplicates", func(t *testing.T) {
+		hashtags := textparse.Hashtags("Golden hour #Sunset at the #beach, #sunset again #Café_2023!")
+
+		assert.Equal(t, []string{"sunset", "beach", "café_2023"}, hashtags)
+	})
+
+	t.Run("should skip what only looks like a hashtag", func(t *testing.T) {
+		hashtags := textparse.Hashtags("issue#12 #123 # alone https://example.com/#anchor ##double #a#b")
+
+		assert.Equal(t, []string{"a"}, hashtags)
+	})
+
+	t.Run("should skip a hashtag too long and keep the first ones", func(t *testing.T) {
+		assert.Empty(t, textparse.Hashtags("#"+strings.Repeat("a", textparse.MaxHashtagLength+1)))
+
+		var caption strings.Builder
+		for i := 0; i < textparse.MaxHashtags+5; i++ {
+			caption.WriteString(" #tag" + strings.Repeat("x", i))
+		}
+
+		hashtags := textparse.Hashtags(caption.String())
+
+		assert.Len(t, hashtags, textparse.MaxHashtags)
+		assert.Equal(t, "tag", hashtags[0])
+	})
+}
+
+func TestNormalizeHashtag(t *testing.T) {
+	t.Run("should normalize a hashtag with or without its #", func(t *testing.T) {
+		for _, hashtag := range []string{"#GoLang", "golang"} {
+			name, ok := textparse.NormalizeHashtag(hashtag)
+
+			assert.True(t, ok)
+			assert.Equal(t, "golang", name)
+		}
+	})
+
+	t.Run("should reject an invalid hashtag", func(t *testing.T) {
+		for _, hashtag := range []string{"", "#", "123", "go lang", "go-lang"} {
+			_, ok := textparse.NormalizeHashtag(hashtag)
+
+			assert.False(t, ok, hashtag)
+		}
+	})
+
+	t.Run("should accept the start of a hashtag being written", func(t *testing.T) {
+		for hashtag, want := range map[string]string{"": "", "#": "", "#20": "20", "Sun": "sun"} {
+			prefix, ok := textparse.NormalizeHashtagPrefix(hashtag)
+
+			assert.True(t, ok, hashtag)
+			assert.Equal(t, want, prefix)
+		}
+
+		_, ok := textparse.NormalizeHashtagPrefix("sun set")
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,41 @@
+package textparse_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/textparse"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMentions(t *testing.T) {
+	t.Run("should find the mentions with their offsets in runes", func(t *testing.T) {
+		mentions := textparse.Mentions("Café with @alice_b and @bob.smith. Thanks @alice_b!")
+
+		assert.Equal(t, []textparse.Mention{
+			{Username: "alice_b", Offset: 10, Length: 8},
+			{Username: "bob.smith", Offset: 23, Length: 10},
+			{Username: "alice_b", Offset: 42, Length: 8},
+		}, mentions)
+	})
+
+	t.Run("should skip what only looks like a mention", func(t *testing.T) {
+		mentions := textparse.Mentions("mail john@example.com, @ alone, @@double, .@dot @ok")
+
+		assert.Equal(t, []textparse.Mention{{Username: "ok", Offset: 48, Length: 3}}, mentions)
+	})
+
+	t.Run("should skip a username too long and keep the first users", func(t *testing.T) {
+		assert.Empty(t, textparse.Mentions("@"+strings.Repeat("a", textparse.MaxUsernameLength+1)))
+
+		var text strings.Builder
+		for i := 0; i < textparse.MaxMentions+5; i++ {
+			text.WriteString(" @user" + strings.Repeat("x", i))
+		}
+
+		mentions := textparse.Mentions(text.String())
+
+		assert.Len(t, mentions, textparse.MaxMentions)
+		assert.Equal(t, "user", mentions[0].Username)
+	})
+}
@@ -0,0 +1,132 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	apiKeyUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/apikey/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestCreateAPIKey(t *testing.T) {
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	apiKeyUseCase := apiKeyUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+
+	t.Run("should success create api key storing only its hash", func(t *testing.T) {
+		var stored *domain.APIKey
+
+		mockAPIKeyRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.APIKey")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.APIKey)
+		}).Return(nil).Once()
+
+		key, apiKey, err := apiKeyUseCase.Create(context.Background(), "user-123", domain.CreateAPIKey{
+			Name:   "deploy script",
+			Scopes: []string{domain.ScopePhotosRead, domain.ScopeCommentsWrite, domain.ScopePhotosRead},
+		})
+
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(key, "mgk_"))
+		assert.Equal(t, helpers.HashToken(key), stored.KeyHash)
+		assert.Equal(t, key[:12], apiKey.Prefix)
+		assert.Equal(t, []string{domain.ScopePhotosRead, domain.ScopeCommentsWrite}, apiKey.ScopeList())
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail create api key with unknown scope", func(t *testing.T) {
+		_, _, err := apiKeyUseCase.Create(context.Background(), "user-123", domain.CreateAPIKey{
+			Name:   "deploy script",
+			Scopes: []string{"users:write"},
+		})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKeyScope)
+	})
+
+	t.Run("should fail create api key without scopes", func(t *testing.T) {
+		_, _, err := apiKeyUseCase.Create(context.Background(), "user-123", domain.CreateAPIKey{Name: "deploy script"})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKeyScope)
+	})
+
+	t.Run("should fail create api key expiring in the past", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Hour)
+
+		_, _, err := apiKeyUseCase.Create(context.Background(), "user-123", domain.CreateAPIKey{
+			Name:      "deploy script",
+			Scopes:    []string{domain.ScopePhotosRead},
+			ExpiresAt: &expiresAt,
+		})
+
+		assert.Error(t, err)
+		mockAPIKeyRepository.AssertNumberOfCalls(t, "Save", 1)
+	})
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	mockAPIKeyRepository := new(mocks.APIKeyRepository)
+	apiKeyUseCase := apiKeyUseCase.NewAPIKeyUseCase(mockAPIKeyRepository)
+
+	key := "mgk_V1StGXR8_Z5jdHi6B-myT"
+	user := &domain.User{ID: "user-123", Email: "johndoe@example.com", Role: domain.RoleUser}
+
+	findByHash := func(apiKey domain.APIKey) {
+		mockAPIKeyRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), helpers.HashToken(key)).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.APIKey) = apiKey
+		}).Return(nil).Once()
+	}
+
+	t.Run("should success authenticate and record the use", func(t *testing.T) {
+		findByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123", Scopes: "photos:read", User: user})
+		mockAPIKeyRepository.On("Touch", mock.Anything, "apikey-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		apiKey, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", apiKey.UserID)
+		assert.NotNil(t, apiKey.LastUsedAt)
+		mockAPIKeyRepository.AssertExpectations(t)
+	})
+
+	t.Run("should not record a use again within a minute", func(t *testing.T) {
+		lastUsedAt := time.Now().Add(-10 * time.Second)
+		findByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123", LastUsedAt: &lastUsedAt, User: user})
+
+		_, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.NoError(t, err)
+		mockAPIKeyRepository.AssertNumberOfCalls(t, "Touch", 1)
+	})
+
+	t.Run("should fail authenticate a revoked api key", func(t *testing.T) {
+		revokedAt := time.Now().Add(-time.Hour)
+		findByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123", RevokedAt: &revokedAt, User: user})
+
+		_, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+
+	t.Run("should fail authenticate an expired api key", func(t *testing.T) {
+		expiresAt := time.Now().Add(-time.Second)
+		findByHash(domain.APIKey{ID: "apikey-123", UserID: "user-123", ExpiresAt: &expiresAt, User: user})
+
+		_, err := apiKeyUseCase.Authenticate(context.Background(), key)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+	})
+
+	t.Run("should fail authenticate an unknown api key", func(t *testing.T) {
+		mockAPIKeyRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.APIKey"), helpers.HashToken("mgk_unknown")).Return(gorm.ErrRecordNotFound).Once()
+
+		_, err := apiKeyUseCase.Authenticate(context.Background(), "mgk_unknown")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidAPIKey)
+		mockAPIKeyRepository.AssertNumberOfCalls(t, "Touch", 1)
+	})
+}
@@ -0,0 +1,50 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	auditLogUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/auditlog/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordAuditLog(t *testing.T) {
+	mockAuditLogRepository := new(mocks.AuditLogRepository)
+	auditLogUseCase := auditLogUseCase.NewAuditLogUseCase(mockAuditLogRepository)
+
+	t.Run("should success record with the principal as the actor", func(t *testing.T) {
+		ctx := domain.ContextWithPrincipal(context.Background(), domain.Principal{
+			UserID: "user-456",
+			Roles:  []string{domain.RoleModerator},
+		})
+
+		mockAuditLogRepository.On("Save", mock.Anything, &domain.AuditLog{
+			ActorID:      "user-456",
+			ActorRole:    domain.RoleModerator,
+			Action:       "delete",
+			ResourceType: "photo",
+			ResourceID:   "photo-123",
+			OwnerID:      "user-123",
+		}).Return(nil).Once()
+
+		err := auditLogUseCase.Record(ctx, &domain.AuditLog{
+			Action:       "delete",
+			ResourceType: "photo",
+			ResourceID:   "photo-123",
+			OwnerID:      "user-123",
+		})
+
+		assert.NoError(t, err)
+		mockAuditLogRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail record without a principal", func(t *testing.T) {
+		err := auditLogUseCase.Record(context.Background(), &domain.AuditLog{Action: "delete"})
+
+		assert.ErrorIs(t, err, domain.ErrUnauthenticated)
+		mockAuditLogRepository.AssertNumberOfCalls(t, "Save", 1)
+	})
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
 	commentUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/comment/usecase"
+	mentionUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/mention/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestSaveComment(t *testing.T) {
@@ -25,7 +28,8 @@ func TestSaveComment(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
 
 	t.Run("should success add comment", func(t *testing.T) {
 		tempMockAddComment := domain.Comment{
@@ -35,6 +39,7 @@ func TestSaveComment(t *testing.T) {
 
 		tempMockAddComment.ID = "comment-123"
 
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
 		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
 
 		err := commentUseCase.Save(context.Background(), &tempMockAddComment)
@@ -58,6 +63,7 @@ func TestSaveComment(t *testing.T) {
 
 		tempMockAddComment.ID = "comment-123"
 
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
 		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
 
 		err := commentUseCase.Save(context.Background(), &tempMockAddComment)
@@ -81,7 +87,7 @@ func TestSaveComment(t *testing.T) {
 
 		tempMockAddComment.ID = "comment-123"
 
-		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(errors.New("fail")).Once()
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "").Return(gorm.ErrRecordNotFound).Once()
 
 		err := commentUseCase.Save(context.Background(), &tempMockAddComment)
 
@@ -103,6 +109,7 @@ func TestSaveComment(t *testing.T) {
 
 		tempMockAddComment.ID = "comment-123"
 
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
 		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
 
 		err := commentUseCase.Save(context.Background(), &tempMockAddComment)
@@ -130,7 +137,8 @@ func TestUpdateComment(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
 
 	t.Run("should success update comment correctly", func(t *testing.T) {
 		tempMockCommentID := "comment-123"
@@ -138,6 +146,7 @@ func TestUpdateComment(t *testing.T) {
 			Message: "A new comment",
 		}
 
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
 		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("string")).Return(mockUpdatedComment, nil).Once()
 
 		comment, err := commentUseCase.Update(context.Background(), tempMockUpdateComment, tempMockCommentID)
@@ -170,6 +179,7 @@ func TestUpdateComment(t *testing.T) {
 			Message: "",
 		}
 
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
 		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("string")).Return(mockUpdatedComment, nil).Once()
 
 		comment, err := commentUseCase.Update(context.Background(), tempMockUpdateComment, tempMockCommentID)
@@ -199,6 +209,7 @@ func TestUpdateComment(t *testing.T) {
 		tempMockCommentID := "comment-123"
 		tempMockUpdateComment := domain.Comment{}
 
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
 		mockCommentRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Comment"), mock.AnythingOfType("string")).Return(mockUpdatedComment, nil).Once()
 
 		comment, err := commentUseCase.Update(context.Background(), tempMockUpdateComment, tempMockCommentID)
@@ -226,6 +237,337 @@ func TestUpdateComment(t *testing.T) {
 	})
 }
 
+func TestSaveReply(t *testing.T) {
+	parentID := "comment-123"
+	mockParent := domain.Comment{
+		ID:      parentID,
+		UserID:  "user-123",
+		PhotoID: "photo-123",
+		Message: "A comment",
+	}
+
+	findParent := func(parent domain.Comment) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(1).(*domain.Comment) = parent
+		}
+	}
+
+	t.Run("should save a reply on the photo of the comment", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		reply := domain.Comment{Message: "A reply", UserID: "user-456", ParentID: &parentID}
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Run(findParent(mockParent)).Return(nil).Once()
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &reply)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "photo-123", reply.PhotoID)
+		assert.Equal(t, 1, reply.Depth)
+		mockCommentRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail save a reply deeper than the max depth", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		tempMockParent := mockParent
+		tempMockParent.Depth = 1
+
+		reply := domain.Comment{Message: "A reply", UserID: "user-456", ParentID: &parentID}
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Run(findParent(tempMockParent)).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &reply)
+
+		assert.ErrorIs(t, err, domain.ErrReplyTooDeep)
+		mockCommentRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail save a reply to a deleted comment", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		now := time.Now()
+		tempMockParent := mockParent
+		tempMockParent.Message = ""
+		tempMockParent.DeletedAt = &now
+
+		reply := domain.Comment{Message: "A reply", UserID: "user-456", ParentID: &parentID}
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Run(findParent(tempMockParent)).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &reply)
+
+		assert.ErrorIs(t, err, domain.ErrCommentDeleted)
+		mockCommentRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail save a reply to a hidden comment", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		now := time.Now()
+		tempMockParent := mockParent
+		tempMockParent.HiddenAt = &now
+
+		reply := domain.Comment{Message: "A reply", UserID: "user-456", ParentID: &parentID}
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Run(findParent(tempMockParent)).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &reply)
+
+		assert.ErrorIs(t, err, domain.ErrCommentHidden)
+		mockCommentRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail save a reply on another photo", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		reply := domain.Comment{Message: "A reply", UserID: "user-456", PhotoID: "photo-456", ParentID: &parentID}
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Run(findParent(mockParent)).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &reply)
+
+		assert.ErrorIs(t, err, domain.ErrReplyPhotoMismatch)
+		mockCommentRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail save a reply to a comment that doesn't exist", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		reply := domain.Comment{Message: "A reply", UserID: "user-456", ParentID: &parentID}
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), parentID).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &reply)
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockCommentRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUpdateDeletedComment(t *testing.T) {
+	now := time.Now()
+	mockCommentRepository := new(mocks.CommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+	mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-123").Run(func(args mock.Arguments) {
+		*args.Get(1).(*domain.Comment) = domain.Comment{ID: "comment-123", ReplyCount: 2, DeletedAt: &now}
+	}).Return(nil).Once()
+
+	_, err := commentUseCase.Update(context.Background(), domain.Comment{Message: "A new comment"}, "comment-123")
+
+	assert.ErrorIs(t, err, domain.ErrCommentDeleted)
+	mockCommentRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSaveCommentPolicy(t *testing.T) {
+	findPhoto := func(policy string) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(1).(*domain.Photo) = domain.Photo{ID: "photo-123", UserID: "user-123", CommentPolicy: policy}
+		}
+	}
+
+	t.Run("should fail save a comment when comments are off", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Run(findPhoto(domain.CommentPolicyOff)).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &domain.Comment{Message: "A comment", PhotoID: "photo-123", UserID: "user-123"})
+
+		assert.ErrorIs(t, err, domain.ErrCommentsOff)
+		mockCommentRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("should fail save a comment of a user who doesn't follow the owner", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		mockFollowRepository := new(mocks.FollowRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, mockFollowRepository, newMentionUseCase(), 1, 3)
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Run(findPhoto(domain.CommentPolicyFollowers)).Return(nil).Once()
+		mockFollowRepository.On("IsFollowing", mock.Anything, "user-456", "user-123").Return(false, nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &domain.Comment{Message: "A comment", PhotoID: "photo-123", UserID: "user-456"})
+
+		assert.ErrorIs(t, err, domain.ErrCommentsFollowsOnly)
+		mockCommentRepository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+		mockFollowRepository.AssertExpectations(t)
+	})
+
+	t.Run("should save a comment of a follower or of the owner", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		mockFollowRepository := new(mocks.FollowRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, mockFollowRepository, newMentionUseCase(), 1, 3)
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Run(findPhoto(domain.CommentPolicyFollowers)).Return(nil).Twice()
+		mockFollowRepository.On("IsFollowing", mock.Anything, "user-456", "user-123").Return(true, nil).Once()
+		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Twice()
+
+		err := commentUseCase.Save(context.Background(), &domain.Comment{Message: "A comment", PhotoID: "photo-123", UserID: "user-456"})
+		assert.NoError(t, err)
+
+		err = commentUseCase.Save(context.Background(), &domain.Comment{Message: "A comment", PhotoID: "photo-123", UserID: "user-123"})
+		assert.NoError(t, err)
+
+		mockFollowRepository.AssertExpectations(t)
+		mockCommentRepository.AssertExpectations(t)
+	})
+}
+
+func TestCommentMentions(t *testing.T) {
+	mockCommentRepository := new(mocks.CommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockBlockRepository := new(mocks.BlockRepository)
+	mockNotificationRepository := new(mocks.NotificationRepository)
+	mentionUseCase := mentionUseCase.NewMentionUseCase(mockUserRepository, mockBlockRepository, mockNotificationRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), mentionUseCase, 1, 3)
+
+	mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "alice"}).Return(domain.User{ID: "user-alice", Username: "alice"}, nil)
+	mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "mallory"}).Return(domain.User{ID: "user-mallory", Username: "mallory"}, nil)
+
+	t.Run("should save the mentions on the photo and notify the users but the blocked ones", func(t *testing.T) {
+		comment := domain.Comment{Message: "Thanks @alice and @mallory", PhotoID: "photo-123", UserID: "user-123"}
+		commentID := "comment-123"
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Return(nil).Once()
+		mockBlockRepository.On("FindBlockedUserIDs", mock.Anything, "user-123", []string{"user-alice", "user-mallory"}).Return(map[string]bool{"user-mallory": true}, nil).Once()
+		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Run(func(args mock.Arguments) {
+			comment := args.Get(1).(*domain.Comment)
+			comment.ID = commentID
+			for i := range comment.Mentions {
+				comment.Mentions[i].CommentID = &comment.ID
+			}
+		}).Return(nil).Once()
+		mockNotificationRepository.On("SaveAll", mock.Anything, []domain.Notification{
+			{UserID: "user-alice", ActorID: "user-123", Type: domain.NotificationTypeMention, PhotoID: "photo-123", CommentID: &commentID},
+		}).Return(nil).Once()
+
+		err := commentUseCase.Save(context.Background(), &comment)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Mention{
+			{PhotoID: "photo-123", CommentID: &commentID, UserID: "user-alice", Username: "alice", Offset: 7, Length: 6},
+		}, comment.Mentions)
+		mockCommentRepository.AssertExpectations(t)
+		mockNotificationRepository.AssertExpectations(t)
+	})
+
+	t.Run("should still save the comment when the users can't be notified", func(t *testing.T) {
+		comment := domain.Comment{Message: "Hi @alice", PhotoID: "photo-123", UserID: "user-123"}
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Return(nil).Once()
+		mockBlockRepository.On("FindBlockedUserIDs", mock.Anything, "user-123", []string{"user-alice"}).Return(map[string]bool{}, nil).Once()
+		mockCommentRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Comment")).Return(nil).Once()
+		mockNotificationRepository.On("SaveAll", mock.Anything, mock.AnythingOfType("[]domain.Notification")).Return(errors.New("connection refused")).Once()
+
+		err := commentUseCase.Save(context.Background(), &comment)
+
+		assert.NoError(t, err)
+		assert.Len(t, comment.Mentions, 1)
+		mockNotificationRepository.AssertExpectations(t)
+	})
+}
+
+func TestPinComment(t *testing.T) {
+	parentID := "comment-123"
+
+	t.Run("should pin a top-level comment on its photo", func(t *testing.T) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Comment) = domain.Comment{ID: "comment-123", PhotoID: "photo-123", Message: "A comment"}
+		}).Return(nil).Once()
+
+		commentID := "comment-123"
+		mockPhotoRepository.On("UpdatePinnedComment", mock.Anything, "photo-123", &commentID).Return(nil).Once()
+
+		comment, err := commentUseCase.Pin(context.Background(), "comment-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "comment-123", comment.ID)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail pin a reply or a hidden comment", func(t *testing.T) {
+		now := time.Now()
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-456").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Comment) = domain.Comment{ID: "comment-456", PhotoID: "photo-123", ParentID: &parentID}
+		}).Return(nil).Once()
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-789").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Comment) = domain.Comment{ID: "comment-789", PhotoID: "photo-123", HiddenAt: &now}
+		}).Return(nil).Once()
+
+		_, err := commentUseCase.Pin(context.Background(), "comment-456")
+		assert.ErrorIs(t, err, domain.ErrCommentNotPinnable)
+
+		_, err = commentUseCase.Pin(context.Background(), "comment-789")
+		assert.ErrorIs(t, err, domain.ErrCommentNotPinnable)
+
+		mockPhotoRepository.AssertNotCalled(t, "UpdatePinnedComment", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestFindAllByPhotoModerated(t *testing.T) {
+	pinnedID := "comment-123"
+
+	request := func(viewerID string) domain.CommentFilter {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+
+		var filter domain.CommentFilter
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Photo) = domain.Photo{ID: "photo-123", UserID: "user-123", PinnedCommentID: &pinnedID}
+		}).Return(nil).Once()
+		mockCommentRepository.On("FindAllByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "photo-123", mock.AnythingOfType("domain.CommentFilter"), mock.AnythingOfType("pagination.Params")).Run(func(args mock.Arguments) {
+			filter = args.Get(3).(domain.CommentFilter)
+		}).Return(nil).Once()
+
+		var comments []domain.Comment
+
+		err := commentUseCase.FindAllByPhoto(context.Background(), &comments, "photo-123", viewerID, pagination.Params{})
+
+		assert.NoError(t, err)
+
+		return filter
+	}
+
+	t.Run("should leave out the pinned comment", func(t *testing.T) {
+		assert.Equal(t, pinnedID, request("user-456").ExceptID)
+	})
+
+	t.Run("should find the hidden comments only for the owner of the photo", func(t *testing.T) {
+		assert.True(t, request("user-123").WithHidden)
+		assert.False(t, request("user-456").WithHidden)
+	})
+}
+
 func TestDeleteComment(t *testing.T) {
 	now := time.Now()
 	mockComment := domain.Comment{
@@ -238,7 +580,8 @@ func TestDeleteComment(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
 
 	t.Run("should success delete comment correctly", func(t *testing.T) {
 		mockCommentRepository.On("DeleteById", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
@@ -275,25 +618,37 @@ func TestFindAllByUser(t *testing.T) {
 	mockComments = append(mockComments, mockComment)
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
 
 	t.Run("should success find all comments by user", func(t *testing.T) {
-		mockCommentRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockCommentRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string"), mock.AnythingOfType("domain.CommentFilter"), mock.AnythingOfType("pagination.Params")).Return(nil).Once()
 
-		err := commentUseCase.FindAllByUser(context.Background(), &mockComments, mockComment.UserID)
+		err := commentUseCase.FindAllByUser(context.Background(), &mockComments, mockComment.UserID, "user-123", pagination.Params{})
 
 		assert.NoError(t, err)
 		mockCommentRepository.AssertExpectations(t)
 	})
 
 	t.Run("should fail find all comment cause empty by user", func(t *testing.T) {
-		mockCommentRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
+		mockCommentRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string"), mock.AnythingOfType("domain.CommentFilter"), mock.AnythingOfType("pagination.Params")).Return(errors.New("fail")).Once()
 
-		err := commentUseCase.FindAllByUser(context.Background(), &mockComments, "user-345")
+		err := commentUseCase.FindAllByUser(context.Background(), &mockComments, "user-345", "user-123", pagination.Params{})
 
 		assert.Error(t, err)
 		mockCommentRepository.AssertExpectations(t)
 	})
+
+	t.Run("should find the hidden comments only on the photos of the viewer", func(t *testing.T) {
+		mockCommentRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "user-123", domain.CommentFilter{PhotoOwnerID: "user-456"}, mock.AnythingOfType("pagination.Params")).Return(nil).Once()
+		mockCommentRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "user-123", domain.CommentFilter{PhotoOwnerID: "user-123"}, mock.AnythingOfType("pagination.Params")).Return(nil).Once()
+
+		// the author is no exception, a comment hidden on another photo stays hidden for them
+		assert.NoError(t, commentUseCase.FindAllByUser(context.Background(), &mockComments, "user-123", "user-456", pagination.Params{}))
+		assert.NoError(t, commentUseCase.FindAllByUser(context.Background(), &mockComments, "user-123", "user-123", pagination.Params{}))
+
+		mockCommentRepository.AssertExpectations(t)
+	})
 }
 
 func TestFindAllByPhoto(t *testing.T) {
@@ -312,25 +667,53 @@ func TestFindAllByPhoto(t *testing.T) {
 	mockComments = append(mockComments, mockComment)
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
 
 	t.Run("should success find all comments by photo", func(t *testing.T) {
-		mockCommentRepository.On("FindAllByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockCommentRepository.On("FindAllByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string"), mock.AnythingOfType("domain.CommentFilter"), mock.AnythingOfType("pagination.Params")).Return(nil).Once()
 
-		err := commentUseCase.FindAllByPhoto(context.Background(), &mockComments, mockComment.PhotoID)
+		err := commentUseCase.FindAllByPhoto(context.Background(), &mockComments, mockComment.PhotoID, "user-123", pagination.Params{})
 
 		assert.NoError(t, err)
 		mockCommentRepository.AssertExpectations(t)
 	})
 
 	t.Run("should fail find all comment cause empty by photo", func(t *testing.T) {
-		mockCommentRepository.On("FindAllByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockCommentRepository.On("FindAllByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), mock.AnythingOfType("string"), mock.AnythingOfType("domain.CommentFilter"), mock.AnythingOfType("pagination.Params")).Return(errors.New("fail")).Once()
 
-		err := commentUseCase.FindAllByPhoto(context.Background(), &mockComments, "photo-345")
+		err := commentUseCase.FindAllByPhoto(context.Background(), &mockComments, "photo-345", "user-123", pagination.Params{})
 
 		assert.Error(t, err)
 		mockCommentRepository.AssertExpectations(t)
 	})
+
+	t.Run("should inline the first replies of the comments with replies", func(t *testing.T) {
+		parentID := "comment-123"
+		comments := []domain.Comment{
+			{ID: parentID, PhotoID: "photo-123", Message: "A comment", ReplyCount: 5},
+			{ID: "comment-456", PhotoID: "photo-123", Message: "Another comment"},
+		}
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockCommentRepository.On("FindAllByPhoto", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "photo-123", mock.AnythingOfType("domain.CommentFilter"), mock.AnythingOfType("pagination.Params")).Return(nil).Once()
+		mockCommentRepository.On("FindFirstReplies", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), []string{parentID}, domain.CommentFilter{}, 3).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Comment) = []domain.Comment{
+				{ID: "comment-r1", ParentID: &parentID, Message: "A reply"},
+				{ID: "comment-r2", ParentID: &parentID, Message: "Another reply"},
+			}
+		}).Return(nil).Once()
+
+		err := commentUseCase.FindAllByPhoto(context.Background(), &comments, "photo-123", "user-123", pagination.Params{})
+
+		assert.NoError(t, err)
+		assert.Len(t, comments[0].Replies, 2)
+		assert.Equal(t, "comment-r1", comments[0].Replies[0].ID)
+		assert.Empty(t, comments[1].Replies)
+		mockCommentRepository.AssertExpectations(t)
+	})
 }
 
 func TestFindCommentById(t *testing.T) {
@@ -345,7 +728,8 @@ func TestFindCommentById(t *testing.T) {
 	}
 
 	mockCommentRepository := new(mocks.CommentRepository)
-	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	commentUseCase := commentUseCase.NewCommentUseCase(mockCommentRepository, mockPhotoRepository, new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
 
 	t.Run("should success find a comment", func(t *testing.T) {
 		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), mock.AnythingOfType("string")).Return(nil).Once()
@@ -367,3 +751,46 @@ func TestFindCommentById(t *testing.T) {
 		mockCommentRepository.AssertExpectations(t)
 	})
 }
+
+func TestFindHiddenComment(t *testing.T) {
+	now := time.Now()
+	hidden := domain.Comment{ID: "comment-123", PhotoID: "photo-123", HiddenAt: &now, Photo: &domain.Photo{ID: "photo-123", UserID: "user-123"}}
+
+	newUseCase := func() (*mocks.CommentRepository, domain.CommentUseCase) {
+		mockCommentRepository := new(mocks.CommentRepository)
+		mockCommentRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Comment"), "comment-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Comment) = hidden
+		}).Return(nil).Once()
+
+		return mockCommentRepository, commentUseCase.NewCommentUseCase(mockCommentRepository, new(mocks.PhotoRepository), new(mocks.FollowRepository), newMentionUseCase(), 1, 3)
+	}
+
+	t.Run("should find a hidden comment only for the owner of the photo", func(t *testing.T) {
+		var comment domain.Comment
+
+		_, useCase := newUseCase()
+		assert.NoError(t, useCase.FindVisible(context.Background(), &comment, "comment-123", "user-123"))
+		assert.Equal(t, "comment-123", comment.ID)
+
+		comment = domain.Comment{}
+
+		_, useCase = newUseCase()
+		assert.ErrorIs(t, useCase.FindVisible(context.Background(), &comment, "comment-123", "user-456"), gorm.ErrRecordNotFound)
+		assert.Empty(t, comment.ID)
+	})
+
+	t.Run("should find the replies to a hidden comment only for the owner of the photo", func(t *testing.T) {
+		var replies []domain.Comment
+
+		mockCommentRepository, useCase := newUseCase()
+		mockCommentRepository.On("FindAllByParent", mock.Anything, mock.AnythingOfType("*[]domain.Comment"), "comment-123", domain.CommentFilter{WithHidden: true}, mock.AnythingOfType("pagination.Params")).Return(nil).Once()
+
+		assert.NoError(t, useCase.FindReplies(context.Background(), &replies, "comment-123", "user-123", pagination.Params{}))
+		mockCommentRepository.AssertExpectations(t)
+
+		mockCommentRepository, useCase = newUseCase()
+
+		assert.ErrorIs(t, useCase.FindReplies(context.Background(), &replies, "comment-123", "user-456", pagination.Params{}), gorm.ErrRecordNotFound)
+		mockCommentRepository.AssertNotCalled(t, "FindAllByParent", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
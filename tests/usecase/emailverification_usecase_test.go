@@ -0,0 +1,106 @@
+package usecase_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	"github.com/gusrylmubarok/mygram-backend/src/mailer"
+	emailVerificationUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/emailverification/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func tokenFromMail(t *testing.T, mail domain.Mail) string {
+	link := strings.Fields(mail.Body[strings.Index(mail.Body, "http://"):])[0]
+	parsed, err := url.Parse(link)
+	assert.NoError(t, err)
+
+	return parsed.Query().Get("token")
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	tokenSigner := newTokenSigner(t)
+
+	mockUserRepository := new(mocks.UserRepository)
+	memoryMailer := mailer.NewMemoryMailer()
+	emailVerificationUseCase := emailVerificationUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer, tokenSigner, "http://localhost:3000/verify-email", time.Hour)
+
+	t.Run("should success send verification link to unverified email", func(t *testing.T) {
+		err := emailVerificationUseCase.Send(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"})
+
+		assert.NoError(t, err)
+		assert.Len(t, memoryMailer.Outbox(), 1)
+		assert.Equal(t, "johndoe@example.com", memoryMailer.Outbox()[0].To)
+	})
+
+	t.Run("should success send verification link to pending email", func(t *testing.T) {
+		now := time.Now()
+
+		err := emailVerificationUseCase.Send(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com", PendingEmail: "newjohndoe@example.com", EmailVerifiedAt: &now})
+
+		assert.NoError(t, err)
+		assert.Len(t, memoryMailer.Outbox(), 2)
+		assert.Equal(t, "newjohndoe@example.com", memoryMailer.Outbox()[1].To)
+	})
+
+	t.Run("should not send verification link to verified email", func(t *testing.T) {
+		now := time.Now()
+
+		err := emailVerificationUseCase.Send(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com", EmailVerifiedAt: &now})
+
+		assert.NoError(t, err)
+		assert.Len(t, memoryMailer.Outbox(), 2)
+	})
+}
+
+func TestVerifyEmail(t *testing.T) {
+	tokenSigner := newTokenSigner(t)
+
+	mockUserRepository := new(mocks.UserRepository)
+	memoryMailer := mailer.NewMemoryMailer()
+	emailVerificationUseCase := emailVerificationUseCase.NewEmailVerificationUseCase(mockUserRepository, memoryMailer, tokenSigner, "http://localhost:3000/verify-email", time.Hour)
+
+	t.Run("should success verify email of the link", func(t *testing.T) {
+		now := time.Now()
+
+		err := emailVerificationUseCase.Send(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com", PendingEmail: "newjohndoe@example.com"})
+		assert.NoError(t, err)
+
+		mockUserRepository.On("VerifyEmail", mock.Anything, "user-123", "newjohndoe@example.com").Return(domain.User{ID: "user-123", Email: "newjohndoe@example.com", EmailVerifiedAt: &now}, nil).Once()
+
+		user, err := emailVerificationUseCase.Verify(context.Background(), tokenFromMail(t, memoryMailer.Outbox()[0]))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "newjohndoe@example.com", user.Email)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail verify email with an access token", func(t *testing.T) {
+		token, err := tokenSigner.Sign(domain.TokenTypeAccess, domain.TokenClaims{"id": "user-123", "email": "johndoe@example.com"}, time.Minute)
+		assert.NoError(t, err)
+
+		_, err = emailVerificationUseCase.Verify(context.Background(), token)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidEmailVerificationToken)
+	})
+
+	t.Run("should fail verify email with a tampered token", func(t *testing.T) {
+		_, err := emailVerificationUseCase.Verify(context.Background(), tokenFromMail(t, memoryMailer.Outbox()[0])+"x")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidEmailVerificationToken)
+	})
+
+	t.Run("should fail verify email replaced by another one", func(t *testing.T) {
+		mockUserRepository.On("VerifyEmail", mock.Anything, "user-123", "newjohndoe@example.com").Return(domain.User{}, domain.ErrInvalidEmailVerificationToken).Once()
+
+		_, err := emailVerificationUseCase.Verify(context.Background(), tokenFromMail(t, memoryMailer.Outbox()[0]))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidEmailVerificationToken)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,55 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	feedUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/feed/usecase"
+	photoUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/photo/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/gusrylmubarok/mygram-backend/src/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFindFeed(t *testing.T) {
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	feedUseCase := feedUseCase.NewFeedUseCase(mockFeedStrategy)
+
+	t.Run("should success find a page of the feed", func(t *testing.T) {
+		params := pagination.Params{After: &pagination.Cursor{CreatedAt: time.Now(), ID: "photo-123"}, Limit: 20}
+		mockPhotos := []domain.Photo{{ID: "photo-122", UserID: "user-456"}}
+
+		mockFeedStrategy.On("FindByUser", mock.Anything, mock.AnythingOfType("*[]domain.Photo"), "user-123", params).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Photo) = mockPhotos
+		}).Return(nil).Once()
+
+		var photos []domain.Photo
+		err := feedUseCase.FindByUser(context.Background(), &photos, "user-123", params)
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockPhotos, photos)
+		mockFeedStrategy.AssertExpectations(t)
+	})
+}
+
+func TestPublishPhotoToFeed(t *testing.T) {
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, mockFeedStrategy, storage.NewMemoryStore(), new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
+
+	t.Run("should add a saved photo to the feeds", func(t *testing.T) {
+		photo := domain.Photo{ID: "photo-123", Title: "A Title", PhotoUrl: "https://www.example.com/image.jpg", Status: domain.PhotoStatusReady, UserID: "user-123"}
+
+		mockPhotoRepository.On("Save", mock.Anything, &photo).Return(nil).Once()
+		mockFeedStrategy.On("AddPhoto", mock.Anything, photo).Return(nil).Once()
+
+		err := photoUseCase.Save(context.Background(), &photo)
+
+		assert.NoError(t, err)
+		mockFeedStrategy.AssertExpectations(t)
+	})
+}
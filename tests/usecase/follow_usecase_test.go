@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	followUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/follow/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestFollow(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStrategy)
+
+	janedoe := domain.User{ID: "user-456", Username: "janedoe"}
+
+	t.Run("should success follow a user", func(t *testing.T) {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "janedoe"}).Return(janedoe, nil).Once()
+		mockFollowRepository.On("Save", mock.Anything, &domain.Follow{FollowerID: "user-123", FollowingID: "user-456"}).Return(nil).Once()
+		mockFeedStrategy.On("AddFollow", mock.Anything, "user-123", "user-456").Return(nil).Once()
+
+		follow, err := followUseCase.Follow(context.Background(), "user-123", "janedoe")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-456", follow.FollowingID)
+		mockFollowRepository.AssertExpectations(t)
+		mockFeedStrategy.AssertExpectations(t)
+	})
+
+	t.Run("should fail follow yourself", func(t *testing.T) {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "janedoe"}).Return(janedoe, nil).Once()
+
+		_, err := followUseCase.Follow(context.Background(), "user-456", "janedoe")
+
+		assert.ErrorIs(t, err, domain.ErrSelfFollow)
+		mockFollowRepository.AssertNotCalled(t, "Save", mock.Anything, &domain.Follow{FollowerID: "user-456", FollowingID: "user-456"})
+	})
+
+	t.Run("should fail follow a user twice", func(t *testing.T) {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "janedoe"}).Return(janedoe, nil).Once()
+		mockFollowRepository.On("Save", mock.Anything, &domain.Follow{FollowerID: "user-789", FollowingID: "user-456"}).Return(domain.ErrAlreadyFollowing).Once()
+
+		_, err := followUseCase.Follow(context.Background(), "user-789", "janedoe")
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyFollowing)
+		mockFollowRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail follow an unknown user", func(t *testing.T) {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "nobody"}).Return(domain.User{}, gorm.ErrRecordNotFound).Once()
+
+		_, err := followUseCase.Follow(context.Background(), "user-123", "nobody")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestUnfollow(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStrategy)
+
+	janedoe := domain.User{ID: "user-456", Username: "janedoe"}
+
+	t.Run("should success unfollow a user", func(t *testing.T) {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "janedoe"}).Return(janedoe, nil).Once()
+		mockFollowRepository.On("Delete", mock.Anything, "user-123", "user-456").Return(nil).Once()
+		mockFeedStrategy.On("RemoveFollow", mock.Anything, "user-123", "user-456").Return(nil).Once()
+
+		err := followUseCase.Unfollow(context.Background(), "user-123", "janedoe")
+
+		assert.NoError(t, err)
+		mockFollowRepository.AssertExpectations(t)
+		mockFeedStrategy.AssertExpectations(t)
+	})
+
+	t.Run("should success unfollow a user when the feed fails", func(t *testing.T) {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "janedoe"}).Return(janedoe, nil).Once()
+		mockFollowRepository.On("Delete", mock.Anything, "user-321", "user-456").Return(nil).Once()
+		mockFeedStrategy.On("RemoveFollow", mock.Anything, "user-321", "user-456").Return(errors.New("connection refused")).Once()
+
+		err := followUseCase.Unfollow(context.Background(), "user-321", "janedoe")
+
+		assert.NoError(t, err)
+		mockFeedStrategy.AssertExpectations(t)
+	})
+
+	t.Run("should fail unfollow a user not followed", func(t *testing.T) {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "janedoe"}).Return(janedoe, nil).Once()
+		mockFollowRepository.On("Delete", mock.Anything, "user-789", "user-456").Return(domain.ErrNotFollowing).Once()
+
+		err := followUseCase.Unfollow(context.Background(), "user-789", "janedoe")
+
+		assert.ErrorIs(t, err, domain.ErrNotFollowing)
+	})
+}
+
+func TestFindFollowers(t *testing.T) {
+	mockFollowRepository := new(mocks.FollowRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	followUseCase := followUseCase.NewFollowUseCase(mockFollowRepository, mockUserRepository, mockFeedStrategy)
+
+	t.Run("should success find a page of followers", func(t *testing.T) {
+		params := pagination.Params{After: &pagination.Cursor{ID: "follow-123"}, Limit: 20}
+
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "janedoe"}).Return(domain.User{ID: "user-456"}, nil).Once()
+		mockFollowRepository.On("FindFollowers", mock.Anything, mock.AnythingOfType("*[]domain.Follow"), "user-456", params).Return(nil).Once()
+
+		var follows []domain.Follow
+		err := followUseCase.FindFollowers(context.Background(), &follows, "janedoe", params)
+
+		assert.NoError(t, err)
+		mockFollowRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,66 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	hashtagUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/hashtag/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFindHashtagPhotos(t *testing.T) {
+	mockHashtagRepository := new(mocks.HashtagRepository)
+	hashtagUseCase := hashtagUseCase.NewHashtagUseCase(mockHashtagRepository)
+
+	t.Run("should find the photos of the normalized hashtag", func(t *testing.T) {
+		var photos []domain.Photo
+
+		mockHashtagRepository.On("FindPhotos", mock.Anything, mock.AnythingOfType("*[]domain.Photo"), "sunset", mock.AnythingOfType("pagination.Params")).Return(nil).Once()
+
+		err := hashtagUseCase.FindPhotos(context.Background(), &photos, "#SunSet", pagination.Params{})
+
+		assert.NoError(t, err)
+		mockHashtagRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail find the photos of an invalid hashtag", func(t *testing.T) {
+		var photos []domain.Photo
+
+		err := hashtagUseCase.FindPhotos(context.Background(), &photos, "sun-set", pagination.Params{})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidHashtag)
+	})
+}
+
+func TestSearchHashtags(t *testing.T) {
+	mockHashtagRepository := new(mocks.HashtagRepository)
+	hashtagUseCase := hashtagUseCase.NewHashtagUseCase(mockHashtagRepository)
+
+	t.Run("should search the hashtags starting with the normalized query", func(t *testing.T) {
+		var usages []domain.HashtagUsage
+
+		mockHashtagRepository.On("Search", mock.Anything, mock.AnythingOfType("*[]domain.HashtagUsage"), "sun", 10).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.HashtagUsage) = []domain.HashtagUsage{{Name: "sunset", PhotoCount: 42}, {Name: "sunday", PhotoCount: 7}}
+		}).Return(nil).Once()
+
+		err := hashtagUseCase.Search(context.Background(), &usages, "#Sun", 10)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "sunset", usages[0].Name)
+		assert.Equal(t, int64(42), usages[0].PhotoCount)
+		mockHashtagRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail search an invalid query", func(t *testing.T) {
+		var usages []domain.HashtagUsage
+
+		err := hashtagUseCase.Search(context.Background(), &usages, "sun set", 10)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidHashtag)
+		mockHashtagRepository.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, "sun set", mock.Anything)
+	})
+}
@@ -0,0 +1,121 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	likeUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/like/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestLike(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository, mockPhotoRepository)
+
+	mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Return(nil)
+
+	t.Run("should success like a photo", func(t *testing.T) {
+		mockLikeRepository.On("Save", mock.Anything, &domain.Like{UserID: "user-123", PhotoID: "photo-123"}).Return(nil).Once()
+
+		err := likeUseCase.Like(context.Background(), "user-123", "photo-123")
+
+		assert.NoError(t, err)
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("should success like a photo twice", func(t *testing.T) {
+		mockLikeRepository.On("Save", mock.Anything, &domain.Like{UserID: "user-123", PhotoID: "photo-123"}).Return(nil).Twice()
+
+		assert.NoError(t, likeUseCase.Like(context.Background(), "user-123", "photo-123"))
+		assert.NoError(t, likeUseCase.Like(context.Background(), "user-123", "photo-123"))
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail like an unknown photo", func(t *testing.T) {
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-404").Return(gorm.ErrRecordNotFound).Once()
+
+		err := likeUseCase.Like(context.Background(), "user-123", "photo-404")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockLikeRepository.AssertNotCalled(t, "Save", mock.Anything, &domain.Like{UserID: "user-123", PhotoID: "photo-404"})
+	})
+}
+
+func TestUnlike(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository, mockPhotoRepository)
+
+	t.Run("should success unlike a photo", func(t *testing.T) {
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Return(nil).Once()
+		mockLikeRepository.On("Delete", mock.Anything, "user-123", "photo-123").Return(nil).Once()
+
+		err := likeUseCase.Unlike(context.Background(), "user-123", "photo-123")
+
+		assert.NoError(t, err)
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail unlike an unknown photo", func(t *testing.T) {
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-404").Return(gorm.ErrRecordNotFound).Once()
+
+		err := likeUseCase.Unlike(context.Background(), "user-123", "photo-404")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestFindLikesByPhoto(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository, mockPhotoRepository)
+
+	t.Run("should success find the likes of a photo", func(t *testing.T) {
+		var likes []domain.Like
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Return(nil).Once()
+		mockLikeRepository.On("FindByPhoto", mock.Anything, &likes, "photo-123", pagination.Params{Limit: 20}).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Like) = []domain.Like{{ID: "like-123", UserID: "user-456", PhotoID: "photo-123"}}
+		}).Return(nil).Once()
+
+		err := likeUseCase.FindByPhoto(context.Background(), &likes, "photo-123", pagination.Params{Limit: 20})
+
+		assert.NoError(t, err)
+		assert.Len(t, likes, 1)
+		mockLikeRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail find the likes of an unknown photo", func(t *testing.T) {
+		var likes []domain.Like
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-404").Return(gorm.ErrRecordNotFound).Once()
+
+		err := likeUseCase.FindByPhoto(context.Background(), &likes, "photo-404", pagination.Params{})
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestFindLikedPhotoIDs(t *testing.T) {
+	mockLikeRepository := new(mocks.LikeRepository)
+	likeUseCase := likeUseCase.NewLikeUseCase(mockLikeRepository, new(mocks.PhotoRepository))
+
+	t.Run("should find the liked photos of a page at once", func(t *testing.T) {
+		photoIDs := []string{"photo-123", "photo-456"}
+
+		mockLikeRepository.On("FindLikedPhotoIDs", mock.Anything, "user-123", photoIDs).Return(map[string]bool{"photo-456": true}, nil).Once()
+
+		liked, err := likeUseCase.FindLikedPhotoIDs(context.Background(), "user-123", photoIDs)
+
+		assert.NoError(t, err)
+		assert.False(t, liked["photo-123"])
+		assert.True(t, liked["photo-456"])
+		mockLikeRepository.AssertNumberOfCalls(t, "FindLikedPhotoIDs", 1)
+	})
+}
@@ -0,0 +1,164 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	loginAttemptUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/loginattempt/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var (
+	accountPolicy = domain.LoginThrottlePolicy{
+		BackoffAfter:  3,
+		BackoffBase:   time.Second,
+		BackoffMax:    time.Minute,
+		LockThreshold: 10,
+		LockDuration:  30 * time.Minute,
+		Window:        time.Hour,
+	}
+	ipPolicy = domain.LoginThrottlePolicy{
+		BackoffAfter: 20,
+		BackoffBase:  time.Second,
+		BackoffMax:   time.Minute,
+		Window:       time.Hour,
+	}
+)
+
+// lockedFor matches a lock time about d from now
+func lockedFor(d time.Duration) interface{} {
+	return mock.MatchedBy(func(until time.Time) bool {
+		wait := time.Until(until)
+
+		return wait > d-5*time.Second && wait <= d
+	})
+}
+
+func TestCheckLoginAttempt(t *testing.T) {
+	mockLoginThrottleRepository := new(mocks.LoginThrottleRepository)
+	loginAttemptUseCase := loginAttemptUseCase.NewLoginAttemptUseCase(mockLoginThrottleRepository, accountPolicy, ipPolicy)
+
+	t.Run("should allow login without failed attempts", func(t *testing.T) {
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "account:johndoe@example.com").Return(gorm.ErrRecordNotFound).Once()
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "ip:10.0.0.1").Return(gorm.ErrRecordNotFound).Once()
+
+		retryAfter, err := loginAttemptUseCase.Check(context.Background(), "JohnDoe@example.com ", "10.0.0.1")
+
+		assert.NoError(t, err)
+		assert.Zero(t, retryAfter)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should throttle login of a locked account", func(t *testing.T) {
+		lockedUntil := time.Now().Add(10 * time.Minute)
+
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "account:johndoe@example.com").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.LoginThrottle) = domain.LoginThrottle{Subject: "account:johndoe@example.com", Failures: 10, LockedUntil: &lockedUntil}
+		}).Return(nil).Once()
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "ip:10.0.0.1").Return(gorm.ErrRecordNotFound).Once()
+
+		retryAfter, err := loginAttemptUseCase.Check(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.ErrorIs(t, err, domain.ErrTooManyLoginAttempts)
+		assert.InDelta(t, (10 * time.Minute).Seconds(), retryAfter.Seconds(), 5)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should allow login once the lock expired", func(t *testing.T) {
+		lockedUntil := time.Now().Add(-time.Second)
+
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "account:johndoe@example.com").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.LoginThrottle) = domain.LoginThrottle{Subject: "account:johndoe@example.com", Failures: 4, LockedUntil: &lockedUntil}
+		}).Return(nil).Once()
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "ip:10.0.0.1").Return(gorm.ErrRecordNotFound).Once()
+
+		_, err := loginAttemptUseCase.Check(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+}
+
+func TestFailLoginAttempt(t *testing.T) {
+	mockLoginThrottleRepository := new(mocks.LoginThrottleRepository)
+	loginAttemptUseCase := loginAttemptUseCase.NewLoginAttemptUseCase(mockLoginThrottleRepository, accountPolicy, ipPolicy)
+
+	t.Run("should not throttle the first failed attempts", func(t *testing.T) {
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "account:johndoe@example.com", time.Hour).Return(domain.LoginThrottle{Failures: 2}, nil).Once()
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "ip:10.0.0.1", time.Hour).Return(domain.LoginThrottle{Failures: 2}, nil).Once()
+
+		err := loginAttemptUseCase.Fail(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		mockLoginThrottleRepository.AssertNotCalled(t, "LockUntil", mock.Anything, mock.Anything, mock.Anything)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should back off exponentially", func(t *testing.T) {
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "account:johndoe@example.com", time.Hour).Return(domain.LoginThrottle{Failures: 5}, nil).Once()
+		mockLoginThrottleRepository.On("LockUntil", mock.Anything, "account:johndoe@example.com", lockedFor(4*time.Second)).Return(nil).Once()
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "ip:10.0.0.1", time.Hour).Return(domain.LoginThrottle{Failures: 5}, nil).Once()
+
+		err := loginAttemptUseCase.Fail(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should cap the back off", func(t *testing.T) {
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "account:johndoe@example.com", time.Hour).Return(domain.LoginThrottle{Failures: 9}, nil).Once()
+		mockLoginThrottleRepository.On("LockUntil", mock.Anything, "account:johndoe@example.com", lockedFor(time.Minute)).Return(nil).Once()
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "ip:10.0.0.1", time.Hour).Return(domain.LoginThrottle{Failures: 60}, nil).Once()
+		mockLoginThrottleRepository.On("LockUntil", mock.Anything, "ip:10.0.0.1", lockedFor(time.Minute)).Return(nil).Once()
+
+		err := loginAttemptUseCase.Fail(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should lock the account from the threshold", func(t *testing.T) {
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "account:johndoe@example.com", time.Hour).Return(domain.LoginThrottle{Failures: 10}, nil).Once()
+		mockLoginThrottleRepository.On("LockUntil", mock.Anything, "account:johndoe@example.com", lockedFor(30*time.Minute)).Return(nil).Once()
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "ip:10.0.0.1", time.Hour).Return(domain.LoginThrottle{Failures: 10}, nil).Once()
+
+		err := loginAttemptUseCase.Fail(context.Background(), "johndoe@example.com", "10.0.0.1")
+
+		assert.NoError(t, err)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+}
+
+func TestUnlockLoginAttempt(t *testing.T) {
+	mockLoginThrottleRepository := new(mocks.LoginThrottleRepository)
+	loginAttemptUseCase := loginAttemptUseCase.NewLoginAttemptUseCase(mockLoginThrottleRepository, accountPolicy, ipPolicy)
+
+	t.Run("should report lock status of an account", func(t *testing.T) {
+		lockedUntil := time.Now().Add(time.Minute)
+
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "account:johndoe@example.com").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.LoginThrottle) = domain.LoginThrottle{Failures: 10, LockedUntil: &lockedUntil}
+		}).Return(nil).Once()
+
+		status, err := loginAttemptUseCase.Status(context.Background(), "johndoe@example.com")
+
+		assert.NoError(t, err)
+		assert.True(t, status.Locked)
+		assert.Equal(t, uint(10), status.Failures)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should success unlock an account", func(t *testing.T) {
+		mockLoginThrottleRepository.On("Delete", mock.Anything, "account:johndoe@example.com").Return(nil).Once()
+
+		err := loginAttemptUseCase.Unlock(context.Background(), "johndoe@example.com")
+
+		assert.NoError(t, err)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,97 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	mentionUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/mention/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// newMentionUseCase stands in for the mentions of the photos and comments
+// tested without any
+func newMentionUseCase() domain.MentionUseCase {
+	return mentionUseCase.NewMentionUseCase(new(mocks.UserRepository), new(mocks.BlockRepository), new(mocks.NotificationRepository))
+}
+
+func TestResolveMentions(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	mockBlockRepository := new(mocks.BlockRepository)
+	mentionUseCase := mentionUseCase.NewMentionUseCase(mockUserRepository, mockBlockRepository, new(mocks.NotificationRepository))
+
+	for username, id := range map[string]string{"alice": "user-alice", "bob": "user-bob"} {
+		mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: username}).Return(domain.User{ID: id, Username: username}, nil)
+	}
+	mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "ghost"}).Return(domain.User{}, gorm.ErrRecordNotFound)
+
+	t.Run("should drop the mentions of missing and blocked users", func(t *testing.T) {
+		mockBlockRepository.On("FindBlockedUserIDs", mock.Anything, "user-123", []string{"user-alice", "user-bob"}).Return(map[string]bool{"user-bob": true}, nil).Once()
+
+		mentions := mentionUseCase.Resolve(context.Background(), "user-123", "@alice @ghost @bob and @alice")
+
+		assert.Equal(t, []domain.Mention{
+			{UserID: "user-alice", Username: "alice", Offset: 0, Length: 6},
+			{UserID: "user-alice", Username: "alice", Offset: 23, Length: 6},
+		}, mentions)
+		mockUserRepository.AssertNumberOfCalls(t, "FindByUsername", 3)
+		mockBlockRepository.AssertExpectations(t)
+	})
+
+	t.Run("should drop every mention when the blocks can't be found", func(t *testing.T) {
+		mockBlockRepository.On("FindBlockedUserIDs", mock.Anything, "user-123", []string{"user-alice"}).Return(nil, errors.New("connection refused")).Once()
+
+		mentions := mentionUseCase.Resolve(context.Background(), "user-123", "hi @alice")
+
+		assert.Empty(t, mentions)
+		mockBlockRepository.AssertExpectations(t)
+	})
+
+	t.Run("should find no mention without any user found", func(t *testing.T) {
+		mentions := mentionUseCase.Resolve(context.Background(), "user-123", "hi @ghost, mail me at me@example.com")
+
+		assert.Empty(t, mentions)
+		mockBlockRepository.AssertNotCalled(t, "FindBlockedUserIDs", mock.Anything, "user-123", []string{})
+	})
+}
+
+func TestNotifyMentions(t *testing.T) {
+	mockNotificationRepository := new(mocks.NotificationRepository)
+	mentionUseCase := mentionUseCase.NewMentionUseCase(new(mocks.UserRepository), new(mocks.BlockRepository), mockNotificationRepository)
+
+	commentID := "comment-123"
+
+	t.Run("should notify each user newly mentioned once but the actor", func(t *testing.T) {
+		mentions := []domain.Mention{
+			{PhotoID: "photo-123", CommentID: &commentID, UserID: "user-alice"},
+			{PhotoID: "photo-123", CommentID: &commentID, UserID: "user-bob"},
+			{PhotoID: "photo-123", CommentID: &commentID, UserID: "user-alice"},
+			{PhotoID: "photo-123", CommentID: &commentID, UserID: "user-carol"},
+			{PhotoID: "photo-123", CommentID: &commentID, UserID: "user-123"},
+		}
+		previous := []domain.Mention{{PhotoID: "photo-123", CommentID: &commentID, UserID: "user-bob"}}
+
+		mockNotificationRepository.On("SaveAll", mock.Anything, []domain.Notification{
+			{UserID: "user-alice", ActorID: "user-123", Type: domain.NotificationTypeMention, PhotoID: "photo-123", CommentID: &commentID},
+			{UserID: "user-carol", ActorID: "user-123", Type: domain.NotificationTypeMention, PhotoID: "photo-123", CommentID: &commentID},
+		}).Return(nil).Once()
+
+		err := mentionUseCase.Notify(context.Background(), "user-123", mentions, previous)
+
+		assert.NoError(t, err)
+		mockNotificationRepository.AssertExpectations(t)
+	})
+
+	t.Run("should notify no one without new mentions", func(t *testing.T) {
+		mentions := []domain.Mention{{PhotoID: "photo-123", UserID: "user-alice"}}
+
+		err := mentionUseCase.Notify(context.Background(), "user-123", mentions, mentions)
+
+		assert.NoError(t, err)
+		mockNotificationRepository.AssertNumberOfCalls(t, "SaveAll", 1)
+	})
+}
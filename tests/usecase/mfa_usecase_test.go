@@ -0,0 +1,296 @@
+package usecase_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	mfaUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/mfa/usecase"
+	tokenMemoryRepository "github.com/gusrylmubarok/mygram-backend/src/modules/token/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestEnrollMFA(t *testing.T) {
+	mockMFARepository := new(mocks.MFARepository)
+	mfaUseCase := mfaUseCase.NewMFAUseCase(mockMFARepository, new(mocks.UserRepository), new(mocks.LoginThrottleRepository), tokenMemoryRepository.NewRevocationStore(), newTokenSigner(t), "MyGram", 5*time.Minute, 5, 15*time.Minute)
+
+	t.Run("should success enroll with a new secret", func(t *testing.T) {
+		var saved *domain.UserMFA
+
+		mockMFARepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.UserMFA")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*domain.UserMFA)
+		}).Return(nil).Once()
+
+		enrollment, err := mfaUseCase.Enroll(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-123", saved.UserID)
+		assert.Equal(t, saved.Secret, enrollment.Secret)
+		assert.True(t, strings.HasPrefix(enrollment.OTPAuthURI, "otpauth://totp/MyGram:johndoe@example.com?"))
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail enroll when already enabled", func(t *testing.T) {
+		mockMFARepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.UserMFA")).Return(domain.ErrMFAAlreadyEnabled).Once()
+
+		_, err := mfaUseCase.Enroll(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"})
+
+		assert.ErrorIs(t, err, domain.ErrMFAAlreadyEnabled)
+		mockMFARepository.AssertExpectations(t)
+	})
+}
+
+func TestConfirmMFA(t *testing.T) {
+	mockMFARepository := new(mocks.MFARepository)
+	mfaUseCase := mfaUseCase.NewMFAUseCase(mockMFARepository, new(mocks.UserRepository), new(mocks.LoginThrottleRepository), tokenMemoryRepository.NewRevocationStore(), newTokenSigner(t), "MyGram", 5*time.Minute, 5, 15*time.Minute)
+
+	secret, err := helpers.GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	pending := domain.UserMFA{UserID: "user-123", Secret: secret}
+	findPending := func(args mock.Arguments) {
+		*args.Get(1).(*domain.UserMFA) = pending
+	}
+
+	t.Run("should success confirm and return recovery codes", func(t *testing.T) {
+		var hashes []string
+
+		code, err := helpers.TOTP(secret, helpers.TOTPCounter(time.Now()))
+		assert.NoError(t, err)
+
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(findPending).Return(nil).Once()
+		mockMFARepository.On("Enable", mock.Anything, pending, mock.AnythingOfType("int64"), mock.AnythingOfType("[]string")).Run(func(args mock.Arguments) {
+			hashes = args.Get(3).([]string)
+		}).Return(nil).Once()
+
+		recoveryCodes, err := mfaUseCase.Confirm(context.Background(), "user-123", code)
+
+		assert.NoError(t, err)
+		assert.Len(t, recoveryCodes, 10)
+		assert.Len(t, hashes, 10)
+		assert.Equal(t, helpers.HashToken(recoveryCodes[0]), hashes[0])
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail confirm with wrong code", func(t *testing.T) {
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(findPending).Return(nil).Once()
+
+		_, err := mfaUseCase.Confirm(context.Background(), "user-123", "000000x")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidMFACode)
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail confirm without enrollment", func(t *testing.T) {
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-456").Return(gorm.ErrRecordNotFound).Once()
+
+		_, err := mfaUseCase.Confirm(context.Background(), "user-456", "123456")
+
+		assert.ErrorIs(t, err, domain.ErrMFANotEnrolled)
+		mockMFARepository.AssertExpectations(t)
+	})
+}
+
+func TestVerifyMFA(t *testing.T) {
+	tokenSigner := newTokenSigner(t)
+
+	mockMFARepository := new(mocks.MFARepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockUserRepository.On("FindById", mock.Anything, "user-123").Return(domain.User{ID: "user-123", Email: "johndoe@example.com", Role: domain.RoleAdmin}, nil)
+	mockLoginThrottleRepository := new(mocks.LoginThrottleRepository)
+	mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "mfa:user-123").Return(gorm.ErrRecordNotFound)
+	mockLoginThrottleRepository.On("Delete", mock.Anything, "mfa:user-123").Return(nil)
+	mfaUseCase := mfaUseCase.NewMFAUseCase(mockMFARepository, mockUserRepository, mockLoginThrottleRepository, tokenMemoryRepository.NewRevocationStore(), tokenSigner, "MyGram", 5*time.Minute, 3, 15*time.Minute)
+
+	secret, err := helpers.GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	enabledAt := time.Now()
+	enabled := domain.UserMFA{UserID: "user-123", Secret: secret, EnabledAt: &enabledAt}
+	findEnabled := func(args mock.Arguments) {
+		*args.Get(1).(*domain.UserMFA) = enabled
+	}
+
+	newChallenge := func(t *testing.T) domain.MFAChallenge {
+		challenge, err := mfaUseCase.Challenge(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(300), challenge.ExpiresIn)
+
+		return challenge
+	}
+
+	t.Run("should success verify with authenticator code", func(t *testing.T) {
+		challenge := newChallenge(t)
+		counter := helpers.TOTPCounter(time.Now())
+		code, err := helpers.TOTP(secret, counter)
+		assert.NoError(t, err)
+
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(findEnabled).Return(nil).Once()
+		mockMFARepository.On("UseCounter", mock.Anything, "user-123", counter).Return(nil).Once()
+
+		user, err := mfaUseCase.Verify(context.Background(), challenge.ChallengeToken, code)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.User{ID: "user-123", Email: "johndoe@example.com", Role: domain.RoleAdmin}, user)
+		mockMFARepository.AssertExpectations(t)
+
+		_, err = mfaUseCase.Verify(context.Background(), challenge.ChallengeToken, code)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidMFAChallenge)
+	})
+
+	t.Run("should fail verify with replayed authenticator code", func(t *testing.T) {
+		challenge := newChallenge(t)
+		code, err := helpers.TOTP(secret, helpers.TOTPCounter(time.Now()))
+		assert.NoError(t, err)
+
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(findEnabled).Return(nil).Once()
+		mockMFARepository.On("UseCounter", mock.Anything, "user-123", mock.AnythingOfType("int64")).Return(domain.ErrInvalidMFACode).Once()
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "mfa:user-123", 15*time.Minute).Return(domain.LoginThrottle{Failures: 1}, nil).Once()
+
+		_, err = mfaUseCase.Verify(context.Background(), challenge.ChallengeToken, code)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidMFACode)
+		mockMFARepository.AssertExpectations(t)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should success verify with recovery code", func(t *testing.T) {
+		challenge := newChallenge(t)
+
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(findEnabled).Return(nil).Once()
+		mockMFARepository.On("UseRecoveryCode", mock.Anything, "user-123", helpers.HashToken("abcde-fghij")).Return(nil).Once()
+
+		_, err := mfaUseCase.Verify(context.Background(), challenge.ChallengeToken, " ABCDE-FGHIJ ")
+
+		assert.NoError(t, err)
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail verify with an access token as challenge", func(t *testing.T) {
+		token, err := tokenSigner.Sign(domain.TokenTypeAccess, domain.TokenClaims{"id": "user-123", "email": "johndoe@example.com"}, time.Minute)
+		assert.NoError(t, err)
+
+		_, err = mfaUseCase.Verify(context.Background(), token, "123456")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidMFAChallenge)
+	})
+}
+
+func TestVerifyMFALocked(t *testing.T) {
+	tokenSigner := newTokenSigner(t)
+
+	secret, err := helpers.GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	enabledAt := time.Now()
+	lockedUntil := time.Now().Add(15 * time.Minute)
+
+	mockMFARepository := new(mocks.MFARepository)
+	mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(func(args mock.Arguments) {
+		*args.Get(1).(*domain.UserMFA) = domain.UserMFA{UserID: "user-123", Secret: secret, EnabledAt: &enabledAt}
+	}).Return(nil).Times(4)
+	mockLoginThrottleRepository := new(mocks.LoginThrottleRepository)
+	mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "mfa:user-123").Return(gorm.ErrRecordNotFound).Times(3)
+	mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "mfa:user-123").Run(func(args mock.Arguments) {
+		*args.Get(1).(*domain.LoginThrottle) = domain.LoginThrottle{Subject: "mfa:user-123", Failures: 3, LockedUntil: &lockedUntil}
+	}).Return(nil)
+	for failures := uint(1); failures <= 3; failures++ {
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "mfa:user-123", 15*time.Minute).Return(domain.LoginThrottle{Failures: failures}, nil).Once()
+	}
+	mockLoginThrottleRepository.On("LockUntil", mock.Anything, "mfa:user-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+	mfaUseCase := mfaUseCase.NewMFAUseCase(mockMFARepository, new(mocks.UserRepository), mockLoginThrottleRepository, tokenMemoryRepository.NewRevocationStore(), tokenSigner, "MyGram", 5*time.Minute, 3, 15*time.Minute)
+
+	newChallenge := func() domain.MFAChallenge {
+		challenge, err := mfaUseCase.Challenge(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"})
+		assert.NoError(t, err)
+
+		return challenge
+	}
+
+	t.Run("should lock the codes of the user after too many wrong ones whatever the challenge", func(t *testing.T) {
+		// a new login doesn't give new guesses
+		for i := 0; i < 2; i++ {
+			_, err := mfaUseCase.Verify(context.Background(), newChallenge().ChallengeToken, "000000x")
+
+			assert.ErrorIs(t, err, domain.ErrInvalidMFACode)
+		}
+
+		challenge := newChallenge()
+
+		_, err := mfaUseCase.Verify(context.Background(), challenge.ChallengeToken, "000000x")
+
+		assert.ErrorIs(t, err, domain.ErrTooManyMFAAttempts)
+
+		code, err := helpers.TOTP(secret, helpers.TOTPCounter(time.Now()))
+		assert.NoError(t, err)
+
+		// the login has to start again
+		_, err = mfaUseCase.Verify(context.Background(), challenge.ChallengeToken, code)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidMFAChallenge)
+
+		_, err = mfaUseCase.Verify(context.Background(), newChallenge().ChallengeToken, code)
+
+		assert.ErrorIs(t, err, domain.ErrTooManyMFAAttempts)
+		mockMFARepository.AssertNotCalled(t, "UseCounter", mock.Anything, mock.Anything, mock.Anything)
+		mockMFARepository.AssertExpectations(t)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+}
+
+func TestDisableMFA(t *testing.T) {
+	enabledAt := time.Now()
+	findEnabled := func(args mock.Arguments) {
+		*args.Get(1).(*domain.UserMFA) = domain.UserMFA{UserID: "user-123", Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}
+	}
+
+	mockMFARepository := new(mocks.MFARepository)
+	mockLoginThrottleRepository := new(mocks.LoginThrottleRepository)
+	mockLoginThrottleRepository.On("Delete", mock.Anything, "mfa:user-123").Return(nil)
+	mfaUseCase := mfaUseCase.NewMFAUseCase(mockMFARepository, new(mocks.UserRepository), mockLoginThrottleRepository, tokenMemoryRepository.NewRevocationStore(), newTokenSigner(t), "MyGram", 5*time.Minute, 5, 15*time.Minute)
+
+	t.Run("should success disable with recovery code", func(t *testing.T) {
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "mfa:user-123").Return(gorm.ErrRecordNotFound).Once()
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(findEnabled).Return(nil).Once()
+		mockMFARepository.On("UseRecoveryCode", mock.Anything, "user-123", helpers.HashToken("abcde-fghij")).Return(nil).Once()
+		mockMFARepository.On("Delete", mock.Anything, "user-123").Return(nil).Once()
+
+		err := mfaUseCase.Disable(context.Background(), "user-123", "abcde-fghij")
+
+		assert.NoError(t, err)
+		mockMFARepository.AssertExpectations(t)
+	})
+
+	t.Run("should lock disable after too many wrong codes", func(t *testing.T) {
+		mockLoginThrottleRepository.On("Find", mock.Anything, mock.AnythingOfType("*domain.LoginThrottle"), "mfa:user-123").Return(gorm.ErrRecordNotFound).Times(2)
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "mfa:user-123", 15*time.Minute).Return(domain.LoginThrottle{Failures: 4}, nil).Once()
+		mockLoginThrottleRepository.On("RecordFailure", mock.Anything, "mfa:user-123", 15*time.Minute).Return(domain.LoginThrottle{Failures: 5}, nil).Once()
+		mockLoginThrottleRepository.On("LockUntil", mock.Anything, "mfa:user-123", mock.AnythingOfType("time.Time")).Return(nil).Once()
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-123").Run(findEnabled).Return(nil).Times(2)
+
+		err := mfaUseCase.Disable(context.Background(), "user-123", "000000x")
+		assert.ErrorIs(t, err, domain.ErrInvalidMFACode)
+
+		err = mfaUseCase.Disable(context.Background(), "user-123", "000000x")
+		assert.ErrorIs(t, err, domain.ErrTooManyMFAAttempts)
+
+		mockMFARepository.AssertExpectations(t)
+		mockLoginThrottleRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail disable when not enabled", func(t *testing.T) {
+		mockMFARepository.On("FindByUserID", mock.Anything, mock.AnythingOfType("*domain.UserMFA"), "user-456").Return(gorm.ErrRecordNotFound).Once()
+
+		err := mfaUseCase.Disable(context.Background(), "user-456", "123456")
+
+		assert.ErrorIs(t, err, domain.ErrMFANotEnabled)
+		mockMFARepository.AssertExpectations(t)
+	})
+}
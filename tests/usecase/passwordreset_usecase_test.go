@@ -0,0 +1,127 @@
+package usecase_test
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	mocksUseCase "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	"github.com/gusrylmubarok/mygram-backend/src/mailer"
+	passwordResetUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/passwordreset/usecase"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestForgotPassword(t *testing.T) {
+	mockPasswordResetRepository := new(mocks.PasswordResetRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
+	memoryMailer := mailer.NewMemoryMailer()
+	passwordResetUseCase := passwordResetUseCase.NewPasswordResetUseCase(mockPasswordResetRepository, mockUserRepository, mockTokenUseCase, mockAPIKeyUseCase, memoryMailer, "http://localhost:3000/reset-password", time.Hour)
+
+	t.Run("should success send reset link to registered email", func(t *testing.T) {
+		var saved *domain.PasswordReset
+
+		mockUserRepository.On("FindByEmail", mock.Anything, &domain.User{Email: "johndoe@example.com"}).Return(domain.User{ID: "user-123", Email: "johndoe@example.com", Username: "johndoe"}, nil).Once()
+		mockPasswordResetRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.PasswordReset")).Run(func(args mock.Arguments) {
+			saved = args.Get(1).(*domain.PasswordReset)
+		}).Return(nil).Once()
+
+		err := passwordResetUseCase.Forgot(context.Background(), "johndoe@example.com")
+
+		assert.NoError(t, err)
+
+		outbox := memoryMailer.Outbox()
+		assert.Len(t, outbox, 1)
+		assert.Equal(t, "johndoe@example.com", outbox[0].To)
+
+		link := outbox[0].Body[strings.Index(outbox[0].Body, "http://"):]
+		link = strings.Fields(link)[0]
+		parsed, err := url.Parse(link)
+		assert.NoError(t, err)
+
+		token := parsed.Query().Get("token")
+		assert.NotEmpty(t, token)
+		assert.Equal(t, helpers.HashToken(token), saved.TokenHash)
+		assert.Equal(t, "user-123", saved.UserID)
+		mockUserRepository.AssertExpectations(t)
+		mockPasswordResetRepository.AssertExpectations(t)
+	})
+
+	t.Run("should silently succeed for unregistered email", func(t *testing.T) {
+		mockUserRepository.On("FindByEmail", mock.Anything, &domain.User{Email: "nobody@example.com"}).Return(domain.User{}, gorm.ErrRecordNotFound).Once()
+
+		err := passwordResetUseCase.Forgot(context.Background(), "nobody@example.com")
+
+		assert.NoError(t, err)
+		assert.Len(t, memoryMailer.Outbox(), 1)
+		mockUserRepository.AssertExpectations(t)
+	})
+}
+
+func TestResetPassword(t *testing.T) {
+	mockPasswordResetRepository := new(mocks.PasswordResetRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockTokenUseCase := new(mocksUseCase.TokenUseCase)
+	mockAPIKeyUseCase := new(mocksUseCase.APIKeyUseCase)
+	passwordResetUseCase := passwordResetUseCase.NewPasswordResetUseCase(mockPasswordResetRepository, mockUserRepository, mockTokenUseCase, mockAPIKeyUseCase, mailer.NewMemoryMailer(), "http://localhost:3000/reset-password", time.Hour)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	t.Run("should success reset password, sign out everywhere and revoke the api keys", func(t *testing.T) {
+		passwordReset := domain.PasswordReset{ID: "passwordreset-123", UserID: "user-123", ExpiresAt: &future}
+
+		mockPasswordResetRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), helpers.HashToken("reset-token")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.PasswordReset) = passwordReset
+		}).Return(nil).Once()
+		mockPasswordResetRepository.On("Consume", mock.Anything, passwordReset, mock.MatchedBy(func(hash string) bool {
+			return helpers.Compare([]byte(hash), []byte("newsecret"))
+		})).Return(nil).Once()
+		mockTokenUseCase.On("RevokeAll", mock.Anything, "user-123").Return(nil).Once()
+		mockAPIKeyUseCase.On("RevokeAllByUser", mock.Anything, "user-123").Return(nil).Once()
+
+		err := passwordResetUseCase.Reset(context.Background(), "reset-token", "newsecret")
+
+		assert.NoError(t, err)
+		mockPasswordResetRepository.AssertExpectations(t)
+		mockTokenUseCase.AssertExpectations(t)
+		mockAPIKeyUseCase.AssertExpectations(t)
+	})
+
+	t.Run("should fail reset password with expired token", func(t *testing.T) {
+		mockPasswordResetRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.PasswordReset) = domain.PasswordReset{ID: "passwordreset-123", UserID: "user-123", ExpiresAt: &past}
+		}).Return(nil).Once()
+
+		err := passwordResetUseCase.Reset(context.Background(), "expired-token", "newsecret")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidPasswordResetToken)
+		mockPasswordResetRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail reset password with used token", func(t *testing.T) {
+		mockPasswordResetRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.PasswordReset"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.PasswordReset) = domain.PasswordReset{ID: "passwordreset-123", UserID: "user-123", ExpiresAt: &future, UsedAt: &past}
+		}).Return(nil).Once()
+
+		err := passwordResetUseCase.Reset(context.Background(), "used-token", "newsecret")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidPasswordResetToken)
+		mockPasswordResetRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail reset password with short password", func(t *testing.T) {
+		err := passwordResetUseCase.Reset(context.Background(), "reset-token", "sec")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "minstringlength(6)")
+	})
+}
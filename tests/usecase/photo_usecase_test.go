@@ -1,15 +1,25 @@
 package usecase_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"image"
+	"image/png"
+	"io"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	"github.com/gusrylmubarok/mygram-backend/src/imaging"
+	mentionUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/mention/usecase"
 	photoUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/photo/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
+	"github.com/gusrylmubarok/mygram-backend/src/storage"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -26,7 +36,9 @@ func TestSavePhoto(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	mockFeedStrategy.On("AddPhoto", mock.Anything, mock.AnythingOfType("domain.Photo")).Return(nil)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, mockFeedStrategy, storage.NewMemoryStore(), new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
 
 	t.Run("should success add photo", func(t *testing.T) {
 		tempMockAddPhoto := domain.Photo{
@@ -140,7 +152,9 @@ func TestUpdatePhoto(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	// the photo is found for the mentions of its caption
+	mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.FeedStrategy), storage.NewMemoryStore(), new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
 
 	t.Run("should success update photo", func(t *testing.T) {
 		tempMockPhotoID := "photo-123"
@@ -275,6 +289,125 @@ func TestUpdatePhoto(t *testing.T) {
 	})
 }
 
+func TestUpdateUploadedPhoto(t *testing.T) {
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Run(func(args mock.Arguments) {
+		*args.Get(1).(*domain.Photo) = domain.Photo{
+			ID:         "photo-123",
+			PhotoUrl:   "http://localhost:8080/public/uploads/photos/user-123/abc.jpg",
+			StorageKey: "photos/user-123/abc.jpg",
+			UserID:     "user-123",
+		}
+	}).Return(nil)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.FeedStrategy), storage.NewMemoryStore(), new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
+
+	t.Run("should fail update the photo url of an uploaded photo", func(t *testing.T) {
+		_, err := photoUseCase.Update(context.Background(), domain.Photo{Title: "A New Title", PhotoUrl: "https://www.example.com/new-image.jpg"}, "photo-123")
+
+		assert.ErrorIs(t, err, domain.ErrPhotoUrlUploaded)
+		mockPhotoRepository.AssertNotCalled(t, "Update", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should success update an uploaded photo with its own photo url", func(t *testing.T) {
+		mockPhotoRepository.On("Update", mock.Anything, mock.AnythingOfType("domain.Photo"), "photo-123").Return(domain.Photo{ID: "photo-123", Title: "A New Title"}, nil).Once()
+
+		photo, err := photoUseCase.Update(context.Background(), domain.Photo{Title: "A New Title", PhotoUrl: "http://localhost:8080/public/uploads/photos/user-123/abc.jpg"}, "photo-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "A New Title", photo.Title)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+}
+
+func TestPhotoHashtags(t *testing.T) {
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	mockFeedStrategy.On("AddPhoto", mock.Anything, mock.AnythingOfType("domain.Photo")).Return(nil)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, mockFeedStrategy, storage.NewMemoryStore(), new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
+
+	t.Run("should save the hashtags of the caption", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", Caption: "Golden hour #Sunset #beach", PhotoUrl: "https://www.example.com/image.jpg"}
+
+		mockPhotoRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Photo")).Return(nil).Once()
+
+		err := photoUseCase.Save(context.Background(), &photo)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"sunset", "beach"}, photo.HashtagNames())
+		mockPhotoRepository.AssertExpectations(t)
+	})
+
+	t.Run("should update the hashtags along the caption", func(t *testing.T) {
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Return(nil).Once()
+		mockPhotoRepository.On("Update", mock.Anything, mock.MatchedBy(func(photo domain.Photo) bool {
+			return len(photo.Hashtags) == 1 && photo.Hashtags[0].Name == "night"
+		}), "photo-123").Return(domain.Photo{ID: "photo-123"}, nil).Once()
+
+		_, err := photoUseCase.Update(context.Background(), domain.Photo{Caption: "Now a #Night photo"}, "photo-123")
+
+		assert.NoError(t, err)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+}
+
+func TestPhotoMentions(t *testing.T) {
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockUserRepository := new(mocks.UserRepository)
+	mockBlockRepository := new(mocks.BlockRepository)
+	mockNotificationRepository := new(mocks.NotificationRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	mockFeedStrategy.On("AddPhoto", mock.Anything, mock.AnythingOfType("domain.Photo")).Return(nil)
+	mentionUseCase := mentionUseCase.NewMentionUseCase(mockUserRepository, mockBlockRepository, mockNotificationRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, mockFeedStrategy, storage.NewMemoryStore(), new(mocks.PhotoProcessor), mentionUseCase, 10<<20)
+
+	mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "alice"}).Return(domain.User{ID: "user-alice", Username: "alice"}, nil)
+	mockUserRepository.On("FindByUsername", mock.Anything, &domain.User{Username: "bob"}).Return(domain.User{ID: "user-bob", Username: "bob"}, nil)
+	mockBlockRepository.On("FindBlockedUserIDs", mock.Anything, "user-123", mock.AnythingOfType("[]string")).Return(map[string]bool{}, nil)
+
+	alice := domain.Mention{PhotoID: "photo-123", UserID: "user-alice", Username: "alice", Offset: 5, Length: 6}
+	bob := domain.Mention{PhotoID: "photo-123", UserID: "user-bob", Username: "bob", Offset: 16, Length: 4}
+
+	t.Run("should save the mentions of the caption and notify the users", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", Caption: "With @alice", PhotoUrl: "https://www.example.com/image.jpg", UserID: "user-123"}
+
+		mockPhotoRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Photo")).Run(func(args mock.Arguments) {
+			photo := args.Get(1).(*domain.Photo)
+			photo.ID = "photo-123"
+			for i := range photo.Mentions {
+				photo.Mentions[i].PhotoID = photo.ID
+			}
+		}).Return(nil).Once()
+		mockNotificationRepository.On("SaveAll", mock.Anything, []domain.Notification{
+			{UserID: "user-alice", ActorID: "user-123", Type: domain.NotificationTypeMention, PhotoID: "photo-123"},
+		}).Return(nil).Once()
+
+		err := photoUseCase.Save(context.Background(), &photo)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.Mention{alice}, photo.Mentions)
+		mockPhotoRepository.AssertExpectations(t)
+		mockNotificationRepository.AssertExpectations(t)
+	})
+
+	t.Run("should only notify the users newly mentioned in the updated caption", func(t *testing.T) {
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Photo) = domain.Photo{ID: "photo-123", UserID: "user-123", Mentions: []domain.Mention{alice}}
+		}).Return(nil).Once()
+		mockPhotoRepository.On("Update", mock.Anything, mock.MatchedBy(func(photo domain.Photo) bool {
+			return len(photo.Mentions) == 2
+		}), "photo-123").Return(domain.Photo{ID: "photo-123", UserID: "user-123", Mentions: []domain.Mention{alice, bob}}, nil).Once()
+		mockNotificationRepository.On("SaveAll", mock.Anything, []domain.Notification{
+			{UserID: "user-bob", ActorID: "user-123", Type: domain.NotificationTypeMention, PhotoID: "photo-123"},
+		}).Return(nil).Once()
+
+		_, err := photoUseCase.Update(context.Background(), domain.Photo{Caption: "With @alice and @bob"}, "photo-123")
+
+		assert.NoError(t, err)
+		mockPhotoRepository.AssertExpectations(t)
+		mockNotificationRepository.AssertExpectations(t)
+	})
+}
+
 func TestDeletePhoto(t *testing.T) {
 	mockPhoto := domain.Photo{
 		ID:       "photo-123",
@@ -285,9 +418,13 @@ func TestDeletePhoto(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	blobStore := storage.NewMemoryStore()
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.FeedStrategy), blobStore, new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
 
 	t.Run("should success delete photo", func(t *testing.T) {
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mockPhoto.ID).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Photo) = mockPhoto
+		}).Return(nil).Once()
 		mockPhotoRepository.On("DeleteById", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
 
 		err := photoUseCase.DeleteById(context.Background(), mockPhoto.ID)
@@ -296,8 +433,26 @@ func TestDeletePhoto(t *testing.T) {
 		mockPhotoRepository.AssertExpectations(t)
 	})
 
+	t.Run("should delete the blob of an uploaded photo", func(t *testing.T) {
+		uploaded := mockPhoto
+		uploaded.StorageKey = "photos/user-123/abc.png"
+
+		blobStore.Put(context.Background(), uploaded.StorageKey, bytes.NewReader([]byte("image")), 5, "image/png")
+
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mockPhoto.ID).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.Photo) = uploaded
+		}).Return(nil).Once()
+		mockPhotoRepository.On("DeleteById", mock.Anything, mockPhoto.ID).Return(nil).Once()
+
+		err := photoUseCase.DeleteById(context.Background(), mockPhoto.ID)
+
+		assert.NoError(t, err)
+		assert.Empty(t, blobStore.Keys())
+		mockPhotoRepository.AssertExpectations(t)
+	})
+
 	t.Run("should fail delete photo with not found photo", func(t *testing.T) {
-		mockPhotoRepository.On("DeleteById", mock.Anything, mock.AnythingOfType("string")).Return(errors.New("fail")).Once()
+		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), "photo-234").Return(errors.New("record not found")).Once()
 
 		err := photoUseCase.DeleteById(context.Background(), "photo-234")
 
@@ -306,6 +461,144 @@ func TestDeletePhoto(t *testing.T) {
 	})
 }
 
+// encodePNG returns a PNG image of the size
+func encodePNG(t *testing.T, width int, height int) []byte {
+	var buffer bytes.Buffer
+
+	if err := png.Encode(&buffer, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestUploadPhoto(t *testing.T) {
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	mockFeedStrategy.On("AddPhoto", mock.Anything, mock.AnythingOfType("domain.Photo")).Return(nil)
+	mockPhotoProcessor := new(mocks.PhotoProcessor)
+	blobStore := storage.NewMemoryStore()
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, mockFeedStrategy, blobStore, mockPhotoProcessor, newMentionUseCase(), 1024)
+
+	t.Run("should success upload photo", func(t *testing.T) {
+		image := encodePNG(t, 4, 3)
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		mockPhotoRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Photo")).Return(nil).Once()
+		mockPhotoProcessor.On("Enqueue", mock.Anything, mock.MatchedBy(func(photo domain.Photo) bool {
+			return photo.Status == domain.PhotoStatusProcessing
+		})).Return(nil).Once()
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(image), false)
+
+		assert.NoError(t, err)
+		assert.Regexp(t, `^photos/user-123/[^/]+\.png$`, photo.StorageKey)
+		assert.Equal(t, "memory://"+photo.StorageKey, photo.PhotoUrl)
+		assert.Equal(t, "image/png", photo.MimeType)
+		assert.Equal(t, int64(len(blobStore.Blob(photo.StorageKey))), photo.ByteSize)
+		assert.Equal(t, 4, photo.Width)
+		assert.Equal(t, 3, photo.Height)
+		assert.Equal(t, "image/png", blobStore.ContentType(photo.StorageKey))
+		assert.Equal(t, domain.PhotoStatusProcessing, photo.Status)
+		mockPhotoRepository.AssertExpectations(t)
+		mockPhotoProcessor.AssertExpectations(t)
+
+		blobStore.Delete(context.Background(), photo.StorageKey)
+	})
+
+	t.Run("should fail upload photo with unsupported type", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		err := photoUseCase.Upload(context.Background(), &photo, strings.NewReader("<html><body>not an image</body></html>"), false)
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedImage)
+		assert.Empty(t, blobStore.Keys())
+	})
+
+	t.Run("should fail upload photo with a forged image header", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(encodePNG(t, 4, 3)[:20]), false)
+
+		assert.ErrorIs(t, err, domain.ErrUnsupportedImage)
+		assert.Empty(t, blobStore.Keys())
+	})
+
+	t.Run("should fail upload photo too large", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		err := photoUseCase.Upload(context.Background(), &photo, io.MultiReader(bytes.NewReader(encodePNG(t, 4, 3)), bytes.NewReader(make([]byte, 1024))), false)
+
+		assert.ErrorIs(t, err, domain.ErrImageTooLarge)
+		assert.Empty(t, blobStore.Keys())
+	})
+
+	t.Run("should delete the blob when the photo is not saved", func(t *testing.T) {
+		photo := domain.Photo{UserID: "user-123"}
+
+		mockPhotoRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Photo")).Return(errors.New("title: non zero value required")).Once()
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(encodePNG(t, 4, 3)), false)
+
+		assert.Error(t, err)
+		assert.Empty(t, blobStore.Keys())
+		mockPhotoRepository.AssertExpectations(t)
+	})
+}
+
+func TestUploadPhotoMetadata(t *testing.T) {
+	// a phone photo stored sideways with its GPS coordinates and serial
+	image, err := os.ReadFile("../testdata/exif.jpg")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	mockFeedStrategy := new(mocks.FeedStrategy)
+	mockFeedStrategy.On("AddPhoto", mock.Anything, mock.AnythingOfType("domain.Photo")).Return(nil)
+	mockPhotoProcessor := new(mocks.PhotoProcessor)
+	mockPhotoProcessor.On("Enqueue", mock.Anything, mock.AnythingOfType("domain.Photo")).Return(nil)
+	blobStore := storage.NewMemoryStore()
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, mockFeedStrategy, blobStore, mockPhotoProcessor, newMentionUseCase(), 10<<20)
+
+	mockPhotoRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Photo")).Return(nil)
+
+	t.Run("should store the photo upright without its metadata", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(image), false)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 8, photo.Width)
+		assert.Equal(t, 16, photo.Height)
+		assert.Nil(t, photo.CameraMetadata())
+
+		stored := blobStore.Blob(photo.StorageKey)
+		assert.NotContains(t, string(stored), "Exif")
+		assert.NotContains(t, string(stored), "Pixel 7")
+		assert.NotContains(t, string(stored), "SN123")
+		assert.Equal(t, 1, imaging.Orientation(stored))
+	})
+
+	t.Run("should keep the camera fields when opted in", func(t *testing.T) {
+		photo := domain.Photo{Title: "A Title", UserID: "user-123"}
+
+		err := photoUseCase.Upload(context.Background(), &photo, bytes.NewReader(image), true)
+
+		takenAt := time.Date(2023, 4, 1, 5, 30, 0, 0, time.UTC)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &domain.GetPhotoMetadata{
+			CameraModel:  "Pixel 7",
+			ExposureTime: "1/125",
+			FNumber:      1.85,
+			ISO:          100,
+			TakenAt:      &takenAt,
+		}, photo.CameraMetadata())
+		assert.NotContains(t, string(blobStore.Blob(photo.StorageKey)), "Exif")
+	})
+}
+
 func TestFindAllPhoto(t *testing.T) {
 	mockPhoto := domain.Photo{
 		ID:       "photo-123",
@@ -320,12 +613,12 @@ func TestFindAllPhoto(t *testing.T) {
 	mockPhotos = append(mockPhotos, mockPhoto)
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.FeedStrategy), storage.NewMemoryStore(), new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
 
 	t.Run("should success find all photos", func(t *testing.T) {
-		mockPhotoRepository.On("FindAll", mock.Anything, mock.AnythingOfType("*[]domain.Photo")).Return(nil).Once()
+		mockPhotoRepository.On("FindAll", mock.Anything, mock.AnythingOfType("*[]domain.Photo"), mock.AnythingOfType("pagination.Params")).Return(nil).Once()
 
-		err := photoUseCase.FindAll(context.Background(), &mockPhotos)
+		err := photoUseCase.FindAll(context.Background(), &mockPhotos, pagination.Params{})
 
 		assert.NoError(t, err)
 	})
@@ -342,7 +635,7 @@ func TestFindPhotoById(t *testing.T) {
 	}
 
 	mockPhotoRepository := new(mocks.PhotoRepository)
-	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository)
+	photoUseCase := photoUseCase.NewPhotoUseCase(mockPhotoRepository, new(mocks.FeedStrategy), storage.NewMemoryStore(), new(mocks.PhotoProcessor), newMentionUseCase(), 10<<20)
 
 	t.Run("should success find a photo", func(t *testing.T) {
 		mockPhotoRepository.On("FindById", mock.Anything, mock.AnythingOfType("*domain.Photo"), mock.AnythingOfType("string")).Return(nil).Once()
@@ -0,0 +1,66 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	sessionUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/session/usecase"
+	tokenMemoryRepository "github.com/gusrylmubarok/mygram-backend/src/modules/token/repository/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestRevokeSession(t *testing.T) {
+	mockSessionRepository := new(mocks.SessionRepository)
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	revocationStore := tokenMemoryRepository.NewRevocationStore()
+	sessionUseCase := sessionUseCase.NewSessionUseCase(mockSessionRepository, mockRefreshTokenRepository, revocationStore, 15*time.Minute)
+
+	t.Run("should success revoke session with its tokens", func(t *testing.T) {
+		mockSessionRepository.On("Revoke", mock.Anything, "session-123", "user-123").Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "session-123").Return(nil).Once()
+
+		err := sessionUseCase.Revoke(context.Background(), "session-123", "user-123")
+		assert.NoError(t, err)
+
+		revoked, err := revocationStore.IsRevoked(context.Background(), "session-123")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		mockSessionRepository.AssertExpectations(t)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail revoke session of another user", func(t *testing.T) {
+		mockSessionRepository.On("Revoke", mock.Anything, "session-456", "user-123").Return(gorm.ErrRecordNotFound).Once()
+
+		err := sessionUseCase.Revoke(context.Background(), "session-456", "user-123")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+
+		revoked, err := revocationStore.IsRevoked(context.Background(), "session-456")
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+		mockRefreshTokenRepository.AssertNotCalled(t, "RevokeFamily", mock.Anything, "session-456")
+	})
+}
+
+func TestFindAllActiveSessions(t *testing.T) {
+	mockSessionRepository := new(mocks.SessionRepository)
+	sessionUseCase := sessionUseCase.NewSessionUseCase(mockSessionRepository, new(mocks.RefreshTokenRepository), tokenMemoryRepository.NewRevocationStore(), 15*time.Minute)
+
+	t.Run("should success find all active sessions", func(t *testing.T) {
+		mockSessionRepository.On("FindAllActiveByUser", mock.Anything, mock.AnythingOfType("*[]domain.Session"), "user-123").Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]domain.Session) = []domain.Session{{ID: "session-123", UserID: "user-123"}}
+		}).Return(nil).Once()
+
+		var sessions []domain.Session
+		err := sessionUseCase.FindAllActiveByUser(context.Background(), &sessions, "user-123")
+
+		assert.NoError(t, err)
+		assert.Len(t, sessions, 1)
+		mockSessionRepository.AssertExpectations(t)
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/gusrylmubarok/mygram-backend/src/domain"
 	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
 	socialMediaUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/socialmedia/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/pagination"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -316,9 +317,9 @@ func TestFindAllSocialMedia(t *testing.T) {
 	socialMediaUseCase := socialMediaUseCase.NewSocialMediaUseCase(mockSocialMediaRepository)
 
 	t.Run("should success find all social media correctly", func(t *testing.T) {
-		mockSocialMediaRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.SocialMedia"), mock.AnythingOfType("string")).Return(nil).Once()
+		mockSocialMediaRepository.On("FindAllByUser", mock.Anything, mock.AnythingOfType("*[]domain.SocialMedia"), mock.AnythingOfType("string"), mock.AnythingOfType("pagination.Params")).Return(nil).Once()
 
-		err := socialMediaUseCase.FindAllByUser(context.Background(), &mockSocialMedias, mockSocialMedia.UserID)
+		err := socialMediaUseCase.FindAllByUser(context.Background(), &mockSocialMedias, mockSocialMedia.UserID, pagination.Params{})
 
 		assert.NoError(t, err)
 	})
@@ -0,0 +1,280 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	"github.com/gusrylmubarok/mygram-backend/src/helpers"
+	tokenMemoryRepository "github.com/gusrylmubarok/mygram-backend/src/modules/token/repository/memory"
+	tokenUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/token/usecase"
+	"github.com/gusrylmubarok/mygram-backend/src/signing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newTokenSigner(t *testing.T) *signing.Signer {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	keyRing := signing.NewKeyRing()
+	assert.NoError(t, keyRing.Add("test-key", privateKey))
+	assert.NoError(t, keyRing.SetSigningKey("test-key"))
+
+	return signing.NewSigner(keyRing, "mygram", "mygram-api")
+}
+
+func TestIssueToken(t *testing.T) {
+	tokenSigner := newTokenSigner(t)
+
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	mockSessionRepository := new(mocks.SessionRepository)
+	revocationStore := tokenMemoryRepository.NewRevocationStore()
+	tokenUseCase := tokenUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockSessionRepository, revocationStore, tokenSigner, 15*time.Minute, 24*time.Hour)
+
+	client := domain.SessionClient{UserAgent: "Mozilla/5.0", IP: "203.0.113.7"}
+
+	t.Run("should success issue token pair", func(t *testing.T) {
+		var (
+			session *domain.Session
+			stored  *domain.RefreshToken
+		)
+
+		mockSessionRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Session")).Run(func(args mock.Arguments) {
+			session = args.Get(1).(*domain.Session)
+			session.ID = "session-123"
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Run(func(args mock.Arguments) {
+			stored = args.Get(1).(*domain.RefreshToken)
+		}).Return(nil).Once()
+
+		token, err := tokenUseCase.Issue(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"}, client)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.Token)
+		assert.NotEmpty(t, token.RefreshToken)
+		assert.Equal(t, "Bearer", token.TokenType)
+		assert.Equal(t, int64(900), token.ExpiresIn)
+		assert.Equal(t, int64(86400), token.RefreshTokenExpiresIn)
+		assert.Equal(t, "user-123", stored.UserID)
+		assert.Equal(t, helpers.HashToken(token.RefreshToken), stored.TokenHash)
+		assert.NotEqual(t, token.RefreshToken, stored.TokenHash)
+		assert.Equal(t, "session-123", stored.FamilyID)
+		assert.Equal(t, "user-123", session.UserID)
+		assert.Equal(t, "Mozilla/5.0", session.UserAgent)
+		assert.Equal(t, "203.0.113.7", session.IP)
+
+		claims, err := tokenSigner.Parse(domain.TokenTypeAccess, token.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, "session-123", claims["sid"])
+		mockRefreshTokenRepository.AssertExpectations(t)
+		mockSessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail issue token pair when saving refresh token fails", func(t *testing.T) {
+		mockSessionRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.Session")).Return(nil).Once()
+		mockRefreshTokenRepository.On("Save", mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(errors.New("fail")).Once()
+
+		_, err := tokenUseCase.Issue(context.Background(), domain.User{ID: "user-123", Email: "johndoe@example.com"}, client)
+
+		assert.Error(t, err)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+}
+
+func TestRefreshToken(t *testing.T) {
+	tokenSigner := newTokenSigner(t)
+
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	mockSessionRepository := new(mocks.SessionRepository)
+	revocationStore := tokenMemoryRepository.NewRevocationStore()
+	tokenUseCase := tokenUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockSessionRepository, revocationStore, tokenSigner, 15*time.Minute, 24*time.Hour)
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	t.Run("should success rotate refresh token", func(t *testing.T) {
+		current := domain.RefreshToken{
+			ID:        "refreshtoken-123",
+			UserID:    "user-123",
+			FamilyID:  "refreshtoken-123",
+			TokenHash: helpers.HashToken("refresh-token"),
+			ExpiresAt: &future,
+			User:      &domain.User{ID: "user-123", Email: "johndoe@example.com", Role: domain.RoleModerator},
+		}
+
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), helpers.HashToken("refresh-token")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = current
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("Rotate", mock.Anything, current, mock.AnythingOfType("*domain.RefreshToken")).Return(nil).Once()
+		mockSessionRepository.On("Touch", mock.Anything, "refreshtoken-123", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+		token, err := tokenUseCase.Refresh(context.Background(), "refresh-token")
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.Token)
+		assert.NotEqual(t, "refresh-token", token.RefreshToken)
+
+		claims, err := tokenSigner.Parse(domain.TokenTypeAccess, token.Token)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleModerator, claims["role"])
+		mockRefreshTokenRepository.AssertExpectations(t)
+		mockSessionRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail refresh with unknown refresh token", func(t *testing.T) {
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Return(errors.New("record not found")).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "unknown")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail refresh with expired refresh token", func(t *testing.T) {
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{
+				ID:        "refreshtoken-123",
+				FamilyID:  "refreshtoken-123",
+				ExpiresAt: &past,
+				User:      &domain.User{ID: "user-123"},
+			}
+		}).Return(nil).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "expired")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail refresh with logged out refresh token", func(t *testing.T) {
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{
+				ID:        "refreshtoken-123",
+				FamilyID:  "refreshtoken-family",
+				ExpiresAt: &future,
+				RevokedAt: &past,
+				User:      &domain.User{ID: "user-123"},
+			}
+		}).Return(nil).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "logged-out")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("should revoke family when a rotated refresh token is reused", func(t *testing.T) {
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{
+				ID:         "refreshtoken-123",
+				UserID:     "user-123",
+				FamilyID:   "refreshtoken-family",
+				ReplacedBy: "refreshtoken-456",
+				ExpiresAt:  &future,
+				RevokedAt:  &past,
+				User:       &domain.User{ID: "user-123"},
+			}
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "refreshtoken-family").Return(nil).Once()
+		mockSessionRepository.On("Revoke", mock.Anything, "refreshtoken-family", "user-123").Return(nil).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "reused")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+		mockRefreshTokenRepository.AssertExpectations(t)
+		mockSessionRepository.AssertExpectations(t)
+
+		// the access tokens of the session are rejected along
+		revoked, err := revocationStore.IsRevoked(context.Background(), "refreshtoken-family")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+
+	t.Run("should revoke family when a concurrent refresh wins the rotation", func(t *testing.T) {
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{
+				ID:        "refreshtoken-123",
+				UserID:    "user-123",
+				FamilyID:  "refreshtoken-raced",
+				ExpiresAt: &future,
+				User:      &domain.User{ID: "user-123"},
+			}
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("Rotate", mock.Anything, mock.Anything, mock.AnythingOfType("*domain.RefreshToken")).Return(domain.ErrRefreshTokenReused).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "refreshtoken-raced").Return(nil).Once()
+		// a login from before sessions has none to revoke
+		mockSessionRepository.On("Revoke", mock.Anything, "refreshtoken-raced", "user-123").Return(gorm.ErrRecordNotFound).Once()
+
+		_, err := tokenUseCase.Refresh(context.Background(), "raced")
+
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+		mockRefreshTokenRepository.AssertExpectations(t)
+		mockSessionRepository.AssertExpectations(t)
+
+		revoked, err := revocationStore.IsRevoked(context.Background(), "refreshtoken-raced")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	})
+}
+
+func TestRevokeToken(t *testing.T) {
+	mockRefreshTokenRepository := new(mocks.RefreshTokenRepository)
+	mockSessionRepository := new(mocks.SessionRepository)
+	revocationStore := tokenMemoryRepository.NewRevocationStore()
+	tokenUseCase := tokenUseCase.NewTokenUseCase(mockRefreshTokenRepository, mockSessionRepository, revocationStore, newTokenSigner(t), 15*time.Minute, 24*time.Hour)
+
+	t.Run("should success revoke access token and refresh token family", func(t *testing.T) {
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), helpers.HashToken("refresh-token")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{
+				ID:       "refreshtoken-123",
+				UserID:   "user-123",
+				FamilyID: "refreshtoken-family",
+			}
+		}).Return(nil).Once()
+		mockRefreshTokenRepository.On("RevokeFamily", mock.Anything, "refreshtoken-family").Return(nil).Once()
+		mockSessionRepository.On("Revoke", mock.Anything, "refreshtoken-family", "user-123").Return(nil).Once()
+
+		err := tokenUseCase.Revoke(context.Background(), "user-123", "jti-123", time.Now().Add(time.Minute), "refresh-token")
+		assert.NoError(t, err)
+
+		revoked, err := revocationStore.IsRevoked(context.Background(), "jti-123")
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail revoke refresh token of another user", func(t *testing.T) {
+		mockRefreshTokenRepository.On("FindByHash", mock.Anything, mock.AnythingOfType("*domain.RefreshToken"), mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+			*args.Get(1).(*domain.RefreshToken) = domain.RefreshToken{
+				ID:       "refreshtoken-123",
+				UserID:   "user-456",
+				FamilyID: "refreshtoken-family",
+			}
+		}).Return(nil).Once()
+
+		err := tokenUseCase.Revoke(context.Background(), "user-123", "jti-456", time.Now().Add(time.Minute), "refresh-token")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRefreshToken)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+
+	t.Run("should success revoke every token of a user", func(t *testing.T) {
+		mockRefreshTokenRepository.On("RevokeAllByUser", mock.Anything, "user-123").Return(nil).Once()
+		mockSessionRepository.On("RevokeAllByUser", mock.Anything, "user-123").Return(nil).Once()
+
+		err := tokenUseCase.RevokeAll(context.Background(), "user-123")
+		assert.NoError(t, err)
+
+		version, err := revocationStore.TokenVersion(context.Background(), "user-123")
+		assert.NoError(t, err)
+		assert.Equal(t, uint(1), version)
+		mockRefreshTokenRepository.AssertExpectations(t)
+	})
+}
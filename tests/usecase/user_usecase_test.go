@@ -3,6 +3,7 @@ package usecase_test
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	userUseCase "github.com/gusrylmubarok/mygram-backend/src/modules/user/usecase"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 func TestRegisterUser(t *testing.T) {
@@ -217,11 +219,14 @@ func TestRegisterUser(t *testing.T) {
 }
 
 func TestLoginUser(t *testing.T) {
+	hashedPassword, err := helpers.Hash("secret")
+	assert.NoError(t, err)
+
 	mockRegisteredUser := domain.User{
 		ID:       "user-123",
 		Username: "johndoe",
 		Email:    "johndoe@example.com",
-		Password: helpers.Hash("secret"),
+		Password: hashedPassword,
 		Age:      8,
 	}
 
@@ -496,6 +501,63 @@ func TestUpdateUser(t *testing.T) {
 		assert.Equal(t, mockUpdatedUser.Username, tempMockUpdatedUser.Username)
 		mockUserRepository.AssertExpectations(t)
 	})
+
+	t.Run("should success update user profile", func(t *testing.T) {
+		tempMockUpdateUser := domain.User{
+			ID:          "user-123",
+			DisplayName: "John Doe",
+			Bio:         "Taking photos of cats",
+			AvatarURL:   "https://www.example.com/avatar.jpg",
+			Website:     "https://johndoe.example.com",
+		}
+
+		mockUserRepository.On("Update", mock.Anything, tempMockUpdateUser).Return(mockUpdatedUser, nil).Once()
+
+		_, err := userUseCase.Update(context.Background(), tempMockUpdateUser)
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail update user profile with invalid fields", func(t *testing.T) {
+		for _, tempMockUpdateUser := range []domain.User{
+			{ID: "user-123", DisplayName: strings.Repeat("a", 51)},
+			{ID: "user-123", Bio: strings.Repeat("a", 161)},
+			{ID: "user-123", AvatarURL: "not a url"},
+			{ID: "user-123", Website: "javascript:alert(1)"},
+		} {
+			_, err := userUseCase.Update(context.Background(), tempMockUpdateUser)
+
+			assert.Error(t, err)
+			mockUserRepository.AssertNotCalled(t, "Update", mock.Anything, tempMockUpdateUser)
+		}
+	})
+}
+
+func TestFindProfileUser(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+
+	t.Run("should success find profile", func(t *testing.T) {
+		mockProfile := domain.UserProfile{ID: "user-123", Username: "johndoe", DisplayName: "John Doe", PhotoCount: 2}
+
+		mockUserRepository.On("FindProfile", mock.Anything, "johndoe").Return(mockProfile, nil).Once()
+
+		profile, err := userUseCase.FindProfile(context.Background(), "johndoe")
+
+		assert.NoError(t, err)
+		assert.Equal(t, mockProfile, profile)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail find profile of unknown user", func(t *testing.T) {
+		mockUserRepository.On("FindProfile", mock.Anything, "janedoe").Return(domain.UserProfile{}, gorm.ErrRecordNotFound).Once()
+
+		_, err := userUseCase.FindProfile(context.Background(), "janedoe")
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+		mockUserRepository.AssertExpectations(t)
+	})
 }
 
 func TestDeleteUser(t *testing.T) {
@@ -528,3 +590,63 @@ func TestDeleteUser(t *testing.T) {
 		mockUserRepository.AssertExpectations(t)
 	})
 }
+
+func TestUpdatePasswordUser(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+
+	t.Run("should success update password", func(t *testing.T) {
+		mockUserRepository.On("UpdatePassword", mock.Anything, "user-123", "secret", "newsecret").Return(nil).Once()
+
+		err := userUseCase.UpdatePassword(context.Background(), "user-123", "secret", "newsecret")
+
+		assert.NoError(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail update password with wrong current password", func(t *testing.T) {
+		mockUserRepository.On("UpdatePassword", mock.Anything, "user-123", "wrong", "newsecret").Return(errors.New("the current password you entered are wrong")).Once()
+
+		err := userUseCase.UpdatePassword(context.Background(), "user-123", "wrong", "newsecret")
+
+		assert.Error(t, err)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail update password with invalid new password", func(t *testing.T) {
+		err := userUseCase.UpdatePassword(context.Background(), "user-123", "secret", "pass")
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "minstringlength(6)")
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, "user-123", "secret", "pass")
+	})
+
+	t.Run("should fail update password with the current password", func(t *testing.T) {
+		err := userUseCase.UpdatePassword(context.Background(), "user-123", "secret", "secret")
+
+		assert.Error(t, err)
+		mockUserRepository.AssertNotCalled(t, "UpdatePassword", mock.Anything, "user-123", "secret", "secret")
+	})
+}
+
+func TestUpdateRoleUser(t *testing.T) {
+	mockUserRepository := new(mocks.UserRepository)
+	userUseCase := userUseCase.NewUserUseCase(mockUserRepository)
+
+	t.Run("should success update role", func(t *testing.T) {
+		mockUserRepository.On("UpdateRole", mock.Anything, "user-123", domain.RoleModerator).Return(domain.User{ID: "user-123", Role: domain.RoleModerator}, nil).Once()
+
+		user, err := userUseCase.UpdateRole(context.Background(), "user-123", domain.RoleModerator)
+
+		assert.NoError(t, err)
+		assert.Equal(t, domain.RoleModerator, user.Role)
+		mockUserRepository.AssertExpectations(t)
+	})
+
+	t.Run("should fail update role with unknown role", func(t *testing.T) {
+		_, err := userUseCase.UpdateRole(context.Background(), "user-123", "owner")
+
+		assert.ErrorIs(t, err, domain.ErrInvalidRole)
+		mockUserRepository.AssertNotCalled(t, "UpdateRole", mock.Anything, "user-123", "owner")
+	})
+}
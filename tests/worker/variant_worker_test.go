@@ -0,0 +1,144 @@
+package worker_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/gusrylmubarok/mygram-backend/src/domain"
+	mocks "github.com/gusrylmubarok/mygram-backend/src/domain/mocks/repository"
+	photoWorker "github.com/gusrylmubarok/mygram-backend/src/modules/photo/worker"
+	"github.com/gusrylmubarok/mygram-backend/src/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProcessVariants(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should generate the variants narrower than the photo", func(t *testing.T) {
+		var encoded bytes.Buffer
+		jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 800, 600)), nil)
+
+		blobStore := storage.NewMemoryStore()
+		blobStore.Put(ctx, "photos/user-123/abc.jpg", &encoded, int64(encoded.Len()), "image/jpeg")
+
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		variantWorker := photoWorker.NewVariantWorker(mockPhotoRepository, blobStore, []int{150, 640, 1080}, 1)
+
+		var variants []domain.PhotoVariant
+
+		mockPhotoRepository.On("SaveVariants", mock.Anything, "photo-123", mock.AnythingOfType("[]domain.PhotoVariant"), domain.PhotoStatusReady).Run(func(args mock.Arguments) {
+			variants = args.Get(2).([]domain.PhotoVariant)
+		}).Return(nil).Once()
+
+		err := variantWorker.Process(ctx, domain.Photo{ID: "photo-123", StorageKey: "photos/user-123/abc.jpg"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []domain.PhotoVariant{
+			{PhotoID: "photo-123", Width: 150, Height: 113, StorageKey: "photos/user-123/abc_150.jpg", URL: "memory://photos/user-123/abc_150.jpg"},
+			{PhotoID: "photo-123", Width: 640, Height: 480, StorageKey: "photos/user-123/abc_640.jpg", URL: "memory://photos/user-123/abc_640.jpg"},
+		}, variants)
+		assert.Equal(t, "image/jpeg", blobStore.ContentType("photos/user-123/abc_640.jpg"))
+		mockPhotoRepository.AssertExpectations(t)
+	})
+
+	t.Run("should store the variants of other formats as png", func(t *testing.T) {
+		var encoded bytes.Buffer
+		png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 300, 300)))
+
+		blobStore := storage.NewMemoryStore()
+		blobStore.Put(ctx, "photos/user-123/abc.gif", &encoded, int64(encoded.Len()), "image/gif")
+
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		variantWorker := photoWorker.NewVariantWorker(mockPhotoRepository, blobStore, []int{150}, 1)
+
+		mockPhotoRepository.On("SaveVariants", mock.Anything, "photo-123", mock.AnythingOfType("[]domain.PhotoVariant"), domain.PhotoStatusReady).Return(nil).Once()
+
+		err := variantWorker.Process(ctx, domain.Photo{ID: "photo-123", StorageKey: "photos/user-123/abc.gif"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", blobStore.ContentType("photos/user-123/abc_150.png"))
+	})
+
+	t.Run("should mark the photo failed when it can't be decoded", func(t *testing.T) {
+		blobStore := storage.NewMemoryStore()
+		blobStore.Put(ctx, "photos/user-123/abc.jpg", bytes.NewReader([]byte("broken")), 6, "image/jpeg")
+
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		variantWorker := photoWorker.NewVariantWorker(mockPhotoRepository, blobStore, []int{150}, 1)
+
+		mockPhotoRepository.On("SaveVariants", mock.Anything, "photo-123", []domain.PhotoVariant(nil), domain.PhotoStatusFailed).Return(nil).Once()
+
+		err := variantWorker.Process(ctx, domain.Photo{ID: "photo-123", StorageKey: "photos/user-123/abc.jpg"})
+
+		assert.Error(t, err)
+		mockPhotoRepository.AssertExpectations(t)
+	})
+
+	t.Run("should delete the variants of a photo deleted meanwhile", func(t *testing.T) {
+		var encoded bytes.Buffer
+		png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 300, 300)))
+
+		blobStore := storage.NewMemoryStore()
+		blobStore.Put(ctx, "photos/user-123/abc.png", &encoded, int64(encoded.Len()), "image/png")
+
+		mockPhotoRepository := new(mocks.PhotoRepository)
+		variantWorker := photoWorker.NewVariantWorker(mockPhotoRepository, blobStore, []int{150}, 1)
+
+		mockPhotoRepository.On("SaveVariants", mock.Anything, "photo-123", mock.AnythingOfType("[]domain.PhotoVariant"), domain.PhotoStatusReady).Return(errors.New("record not found")).Once()
+
+		err := variantWorker.Process(ctx, domain.Photo{ID: "photo-123", StorageKey: "photos/user-123/abc.png"})
+
+		assert.Error(t, err)
+		assert.Equal(t, []string{"photos/user-123/abc.png"}, blobStore.Keys())
+	})
+}
+
+func TestStartVariantWorker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var encoded bytes.Buffer
+	png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, 300, 300)))
+
+	blobStore := storage.NewMemoryStore()
+	blobStore.Put(ctx, "photos/user-123/abc.png", &encoded, int64(encoded.Len()), "image/png")
+
+	mockPhotoRepository := new(mocks.PhotoRepository)
+	variantWorker := photoWorker.NewVariantWorker(mockPhotoRepository, blobStore, []int{150}, 1)
+
+	done := make(chan struct{})
+
+	mockPhotoRepository.On("FindProcessing", mock.Anything, mock.AnythingOfType("*[]domain.Photo")).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]domain.Photo) = []domain.Photo{{ID: "photo-123", StorageKey: "photos/user-123/abc.png"}}
+	}).Return(nil).Once()
+	mockPhotoRepository.On("SaveVariants", mock.Anything, "photo-123", mock.AnythingOfType("[]domain.PhotoVariant"), domain.PhotoStatusReady).Run(func(args mock.Arguments) {
+		close(done)
+	}).Return(nil).Once()
+
+	variantWorker.Start(ctx, 1)
+
+	<-done
+	mockPhotoRepository.AssertExpectations(t)
+}
+
+func TestEnqueueVariantWorker(t *testing.T) {
+	variantWorker := photoWorker.NewVariantWorker(new(mocks.PhotoRepository), storage.NewMemoryStore(), []int{150}, 1)
+
+	t.Run("should queue the photo while there's room", func(t *testing.T) {
+		err := variantWorker.Enqueue(context.Background(), domain.Photo{ID: "photo-123"})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should fail queue the photo without waiting when the queue is full", func(t *testing.T) {
+		err := variantWorker.Enqueue(context.Background(), domain.Photo{ID: "photo-234"})
+
+		assert.ErrorIs(t, err, domain.ErrProcessingQueueFull)
+	})
+}